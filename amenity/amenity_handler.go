@@ -0,0 +1,129 @@
+package amenity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+//AmenitiesHandler handler for the amenity catalogue
+type AmenitiesHandler struct {
+	useCase AmenitiesUseCaseInterface
+	logger  *logrus.Logger
+}
+
+//GetAmenities get the catalogue, GET /amenity?category=wellness narrows it to a category
+func (h *AmenitiesHandler) GetAmenities(w http.ResponseWriter, r *http.Request) {
+	amenities, err := h.useCase.GetAmenities(r.Context(), r.URL.Query().Get("category"))
+	if err != nil {
+		h.logger.Info("Error occured while fetching the amenities")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the amenities")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, amenities)
+}
+
+//GetAmenity get an amenity based on its code
+func (h *AmenitiesHandler) GetAmenity(w http.ResponseWriter, r *http.Request) {
+	amenity, err := h.useCase.GetAmenity(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		h.respondWithAmenityError(w, err, "Error occured while fetching the amenity")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, amenity)
+}
+
+//AddAmenity add an amenity to the catalogue
+func (h *AmenitiesHandler) AddAmenity(w http.ResponseWriter, r *http.Request) {
+	var amenity Amenity
+	if !h.decodeAmenity(w, r, &amenity) {
+		return
+	}
+	created, err := h.useCase.AddAmenity(r.Context(), amenity)
+	if err != nil {
+		h.respondWithAmenityError(w, err, "Failed to add amenity")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/amenity/%s", created.Code))
+	utils.RespondWithJSON(w, http.StatusCreated, created)
+}
+
+//UpdateAmenity update the name and category of an amenity, the code of the path is kept
+func (h *AmenitiesHandler) UpdateAmenity(w http.ResponseWriter, r *http.Request) {
+	var amenity Amenity
+	code := chi.URLParam(r, "code")
+	if !h.decodeAmenity(w, r, &amenity) {
+		return
+	}
+	if amenity.Code != code {
+		utils.RespondWithValidationError(w, http.StatusBadRequest, []utils.InvalidParams{{Name: "code", Reason: "code can not be changed"}})
+		return
+	}
+	updated, err := h.useCase.UpdateAmenity(r.Context(), amenity)
+	if err != nil {
+		h.respondWithAmenityError(w, err, "Failed to update amenity")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, updated)
+}
+
+//DeleteAmenity remove an amenity from the catalogue
+func (h *AmenitiesHandler) DeleteAmenity(w http.ResponseWriter, r *http.Request) {
+	err := h.useCase.DeleteAmenity(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		h.respondWithAmenityError(w, err, "Failed to delete amenity")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+// decodeAmenity decodes and validates the body, it is false when a response has already been written
+func (h *AmenitiesHandler) decodeAmenity(w http.ResponseWriter, r *http.Request, amenity *Amenity) bool {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(amenity); err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return false
+	}
+	if code := chi.URLParam(r, "code"); code != "" && amenity.Code == "" {
+		amenity.Code = code
+	}
+	invalidParams := amenity.Validate()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return false
+	}
+	return true
+}
+
+// respondWithAmenityError maps the errors of the catalogue, message describes any other error
+func (h *AmenitiesHandler) respondWithAmenityError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, utils.ErrAmenityNotFound) {
+		h.logger.Info("Amenity not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Amenity not found")
+		return
+	}
+	if errors.Is(err, utils.ErrAmenityExists) {
+		h.logger.Info("Amenity already exists")
+		utils.RespondWithError(w, http.StatusConflict, utils.ErrAmenityExists.Error())
+		return
+	}
+	if errors.Is(err, utils.ErrAmenityInUse) {
+		h.logger.Info("Amenity is offered by items")
+		utils.RespondWithError(w, http.StatusConflict, utils.ErrAmenityInUse.Error())
+		return
+	}
+	h.logger.Info(message)
+	utils.RespondWithError(w, http.StatusInternalServerError, message)
+}
+
+//NewAmenitiesHandler function
+func NewAmenitiesHandler(useCase *AmenitiesUseCase, log *logrus.Logger) *AmenitiesHandler {
+	return &AmenitiesHandler{useCase, log}
+}
@@ -0,0 +1,127 @@
+package amenity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+var pool = Amenity{Code: "pool", Name: "Swimming pool", Category: "wellness"}
+
+type MockUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUseCase) GetAmenities(ctx context.Context, category string) ([]Amenity, error) {
+	args := m.Called(ctx, category)
+	return args.Get(0).([]Amenity), args.Error(1)
+}
+
+func (m *MockUseCase) GetAmenity(ctx context.Context, code string) (Amenity, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(Amenity), args.Error(1)
+}
+
+func (m *MockUseCase) AddAmenity(ctx context.Context, amenity Amenity) (Amenity, error) {
+	args := m.Called(ctx, amenity)
+	return args.Get(0).(Amenity), args.Error(1)
+}
+
+func (m *MockUseCase) UpdateAmenity(ctx context.Context, amenity Amenity) (Amenity, error) {
+	args := m.Called(ctx, amenity)
+	return args.Get(0).(Amenity), args.Error(1)
+}
+
+func (m *MockUseCase) DeleteAmenity(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func newAmenityRequest(method, url, code, body string) *http.Request {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	if code != "" {
+		rctx.URLParams.Add("code", code)
+	}
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestGetAmenitiesHandler(t *testing.T) {
+	uc := new(MockUseCase)
+	ah := AmenitiesHandler{uc, logrus.New()}
+	req := newAmenityRequest("GET", "/amenity?category=wellness", "", "")
+	uc.On("GetAmenities", req.Context(), "wellness").Return([]Amenity{pool}, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ah.GetAmenities).ServeHTTP(rr, req)
+	var res []Amenity
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []Amenity{pool}, res)
+	uc.AssertExpectations(t)
+}
+
+func TestAddAmenityHandler(t *testing.T) {
+	uc := new(MockUseCase)
+	ah := AmenitiesHandler{uc, logrus.New()}
+	req := newAmenityRequest("POST", "/amenity", "", `{"code":"pool","name":"Swimming pool","category":"wellness"}`)
+	uc.On("AddAmenity", req.Context(), pool).Return(pool, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ah.AddAmenity).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/amenity/pool", rr.Header().Get("Location"))
+	uc.AssertExpectations(t)
+}
+
+func TestAddAmenityHandlerExists(t *testing.T) {
+	uc := new(MockUseCase)
+	ah := AmenitiesHandler{uc, logrus.New()}
+	req := newAmenityRequest("POST", "/amenity", "", `{"code":"pool","name":"Swimming pool","category":"wellness"}`)
+	uc.On("AddAmenity", req.Context(), pool).Return(Amenity{}, utils.ErrAmenityExists)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ah.AddAmenity).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestUpdateAmenityHandler(t *testing.T) {
+	uc := new(MockUseCase)
+	ah := AmenitiesHandler{uc, logrus.New()}
+	req := newAmenityRequest("PUT", "/amenity/pool", "pool", `{"name":"Swimming pool","category":"wellness"}`)
+	uc.On("UpdateAmenity", req.Context(), pool).Return(pool, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ah.UpdateAmenity).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestUpdateAmenityHandlerCodeChanged(t *testing.T) {
+	uc := new(MockUseCase)
+	ah := AmenitiesHandler{uc, logrus.New()}
+	req := newAmenityRequest("PUT", "/amenity/pool", "pool", `{"code":"swimming-pool","name":"Swimming pool"}`)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ah.UpdateAmenity).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestDeleteAmenityHandlerInUse(t *testing.T) {
+	uc := new(MockUseCase)
+	ah := AmenitiesHandler{uc, logrus.New()}
+	req := newAmenityRequest("DELETE", "/amenity/pool", "pool", "")
+	uc.On("DeleteAmenity", req.Context(), "pool").Return(utils.ErrAmenityInUse)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ah.DeleteAmenity).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	uc.AssertExpectations(t)
+}
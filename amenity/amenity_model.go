@@ -0,0 +1,51 @@
+package amenity
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+const (
+	maxCodeLength     = 50
+	maxNameLength     = 100
+	maxCategoryLength = 50
+)
+
+// codePattern is the format of amenity codes, lower case words joined by hyphens like "pet-friendly"
+var codePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Amenity is a feature of the catalogue an item can offer, like "pool" or "wifi". Items and the amenities
+// filter of GET /item refer to an amenity by its Code, Category groups amenities like "wellness"
+type Amenity struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// ValidCode tells whether code is a well formed amenity code
+func ValidCode(code string) bool {
+	return len(code) <= maxCodeLength && codePattern.MatchString(code)
+}
+
+// Validate validates the amenity
+func (a Amenity) Validate() []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if a.Code == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "code", Reason: "code required"})
+	} else if !ValidCode(a.Code) {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "code", Reason: fmt.Sprintf("code should be at most %d lower case letters, digits and hyphens like pet-friendly", maxCodeLength)})
+	}
+	if a.Name == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "name", Reason: "name required"})
+	}
+	if utf8.RuneCountInString(a.Name) > maxNameLength {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "name", Reason: fmt.Sprintf("name should be at most %d characters", maxNameLength)})
+	}
+	if utf8.RuneCountInString(a.Category) > maxCategoryLength {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "category", Reason: fmt.Sprintf("category should be at most %d characters", maxCategoryLength)})
+	}
+	return validationErr
+}
@@ -0,0 +1,33 @@
+package amenity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidCode(t *testing.T) {
+	for _, code := range []string{"wifi", "pet-friendly", "24h-reception"} {
+		if !ValidCode(code) {
+			t.Errorf("Expected %s to be a valid code", code)
+		}
+	}
+	for _, code := range []string{"", "Wifi", "pet friendly", "-pool", "pool-", "spa--bath", strings.Repeat("a", maxCodeLength+1)} {
+		if ValidCode(code) {
+			t.Errorf("Expected %s not to be a valid code", code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if validateErr := (Amenity{Code: "pool", Name: "Swimming pool", Category: "wellness"}).Validate(); len(validateErr) != 0 {
+		t.Errorf("Expected no error got %v", validateErr)
+	}
+	// names and categories are counted in characters, not bytes
+	if validateErr := (Amenity{Code: "sauna", Name: strings.Repeat("ä", maxNameLength), Category: strings.Repeat("ö", maxCategoryLength)}).Validate(); len(validateErr) != 0 {
+		t.Errorf("Expected no error got %v", validateErr)
+	}
+	validateErr := Amenity{Code: "Pool", Category: strings.Repeat("a", maxCategoryLength+1)}.Validate()
+	if len(validateErr) != 3 || validateErr[0].Name != "code" || validateErr[1].Name != "name" || validateErr[2].Name != "category" {
+		t.Errorf("Expected code, name and category got %v", validateErr)
+	}
+}
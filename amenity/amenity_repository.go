@@ -0,0 +1,141 @@
+package amenity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//AmenitiesRepositoryInterface interface
+type AmenitiesRepositoryInterface interface {
+	GetAmenities(ctx context.Context, category string) ([]Amenity, error)
+	GetAmenity(ctx context.Context, code string) (Amenity, error)
+	AddAmenity(ctx context.Context, amenity Amenity) error
+	UpdateAmenity(ctx context.Context, amenity Amenity) error
+	DeleteAmenity(ctx context.Context, code string) error
+}
+
+//AmenitiesRepository struct
+type AmenitiesRepository struct {
+	db *sql.DB
+}
+
+//GetAmenities returns the catalogue ordered by category and code, an empty category returns every amenity
+func (r *AmenitiesRepository) GetAmenities(ctx context.Context, category string) ([]Amenity, error) {
+	query := `SELECT code, name, category FROM amenity WHERE $1 = '' OR category = $1 ORDER BY category, code;`
+	rows, err := r.db.QueryContext(ctx, query, category)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching amenities %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	amenities := []Amenity{}
+	for rows.Next() {
+		var a Amenity
+		if err := rows.Scan(&a.Code, &a.Name, &a.Category); err != nil {
+			return nil, fmt.Errorf("Error occured while fetching amenities %w", utils.ErrFetchError)
+		}
+		amenities = append(amenities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching amenities %w", utils.ErrFetchError)
+	}
+	return amenities, nil
+}
+
+//GetAmenity gets an amenity based on its code
+func (r *AmenitiesRepository) GetAmenity(ctx context.Context, code string) (Amenity, error) {
+	var a Amenity
+	query := `SELECT code, name, category FROM amenity WHERE code = $1;`
+	err := r.db.QueryRowContext(ctx, query, code).Scan(&a.Code, &a.Name, &a.Category)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Amenity{}, fmt.Errorf("Amenity not found %w", utils.ErrAmenityNotFound)
+		}
+		return Amenity{}, fmt.Errorf("Failed to fetch amenity %w", utils.ErrFetchError)
+	}
+	return a, nil
+}
+
+//AddAmenity adds an amenity to the catalogue, its code should not be taken
+func (r *AmenitiesRepository) AddAmenity(ctx context.Context, amenity Amenity) error {
+	query := `INSERT INTO amenity(code, name, category) VALUES($1 , $2 , $3) ON CONFLICT (code) DO NOTHING;`
+	result, err := r.db.ExecContext(ctx, query, amenity.Code, amenity.Name, amenity.Category)
+	if err != nil {
+		return fmt.Errorf("Error occured during insertion %w", utils.ErrAmenityNotSaved)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Error occured during insertion %w", utils.ErrAmenityNotSaved)
+	}
+	if rows == 0 {
+		return fmt.Errorf("Amenity %s already exists %w", amenity.Code, utils.ErrAmenityExists)
+	}
+	return nil
+}
+
+//UpdateAmenity updates the name and category of an amenity, the items offering it get a new version so
+//that their cached copies are refreshed along with the catalogue
+func (r *AmenitiesRepository) UpdateAmenity(ctx context.Context, amenity Amenity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	var id int
+	query := `UPDATE amenity SET name = $2, category = $3 WHERE code = $1 RETURNING id;`
+	err = tx.QueryRowContext(ctx, query, amenity.Code, amenity.Name, amenity.Category).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Amenity not found %w", utils.ErrAmenityNotFound)
+		}
+		return fmt.Errorf("Error occured while updating the amenity %w", utils.ErrAmenityNotSaved)
+	}
+	versionQry := `UPDATE item SET version = version + 1 FROM item_amenity WHERE item_amenity.item_id = item.item_id AND item_amenity.amenity_id = $1;`
+	_, err = tx.ExecContext(ctx, versionQry, id)
+	if err != nil {
+		return fmt.Errorf("Error occured while updating the items %w", utils.ErrAmenityNotSaved)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Error occured while updating the amenity %w", utils.ErrAmenityNotSaved)
+	}
+	return nil
+}
+
+//DeleteAmenity removes an amenity from the catalogue, an amenity still offered by an item is kept so no item
+//changes with it
+func (r *AmenitiesRepository) DeleteAmenity(ctx context.Context, code string) error {
+	query := `DELETE FROM amenity WHERE code = $1 AND NOT EXISTS (SELECT 1 FROM item_amenity WHERE item_amenity.amenity_id = amenity.id);`
+	result, err := r.db.ExecContext(ctx, query, code)
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the amenity %w", utils.ErrAmenityNotSaved)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the amenity %w", utils.ErrAmenityNotSaved)
+	}
+	if rows == 1 {
+		return nil
+	}
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM amenity WHERE code = $1);`, code).Scan(&exists); err != nil {
+		return fmt.Errorf("Error occured while fetching the amenity %w", utils.ErrFetchError)
+	}
+	if exists {
+		return fmt.Errorf("Amenity %s is offered by items %w", code, utils.ErrAmenityInUse)
+	}
+	return fmt.Errorf("Amenity not found %w", utils.ErrAmenityNotFound)
+}
+
+//NewAmenitiesRepository function
+func NewAmenitiesRepository(db *sql.DB) *AmenitiesRepository {
+	return &AmenitiesRepository{db}
+}
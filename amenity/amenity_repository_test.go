@@ -0,0 +1,98 @@
+package amenity
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAmenities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT code, name, category FROM amenity WHERE \$1 = '' OR category = \$1`).WithArgs("wellness").
+		WillReturnRows(sqlmock.NewRows([]string{"code", "name", "category"}).AddRow("pool", "Swimming pool", "wellness").AddRow("spa", "Spa", "wellness"))
+	repo := NewAmenitiesRepository(db)
+	amenities, err := repo.GetAmenities(context.Background(), "wellness")
+	assert.NoError(t, err)
+	assert.Equal(t, []Amenity{{Code: "pool", Name: "Swimming pool", Category: "wellness"}, {Code: "spa", Name: "Spa", Category: "wellness"}}, amenities)
+}
+
+func TestGetAmenityNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`WHERE code = \$1`).WithArgs("helipad").WillReturnRows(sqlmock.NewRows([]string{"code", "name", "category"}))
+	repo := NewAmenitiesRepository(db)
+	_, err = repo.GetAmenity(context.Background(), "helipad")
+	assert.True(t, errors.Is(err, utils.ErrAmenityNotFound))
+}
+
+func TestAddAmenityExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`INSERT INTO amenity.* ON CONFLICT \(code\) DO NOTHING`).WithArgs("pool", "Swimming pool", "wellness").WillReturnResult(sqlmock.NewResult(0, 0))
+	repo := NewAmenitiesRepository(db)
+	err = repo.AddAmenity(context.Background(), Amenity{Code: "pool", Name: "Swimming pool", Category: "wellness"})
+	assert.True(t, errors.Is(err, utils.ErrAmenityExists))
+}
+
+func TestUpdateAmenityNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE amenity SET name = \$2, category = \$3 WHERE code = \$1`).WithArgs("helipad", "Helipad", "transport").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+	repo := NewAmenitiesRepository(db)
+	err = repo.UpdateAmenity(context.Background(), Amenity{Code: "helipad", Name: "Helipad", Category: "transport"})
+	assert.True(t, errors.Is(err, utils.ErrAmenityNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAmenityBumpsItemVersions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE amenity SET name = \$2, category = \$3 WHERE code = \$1 RETURNING id`).WithArgs("pool", "Outdoor pool", "wellness").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(`UPDATE item SET version = version \+ 1 FROM item_amenity`).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	repo := NewAmenitiesRepository(db)
+	err = repo.UpdateAmenity(context.Background(), Amenity{Code: "pool", Name: "Outdoor pool", Category: "wellness"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAmenity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM amenity WHERE code = \$1 AND NOT EXISTS`).WithArgs("spa").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM amenity`).WithArgs("pool").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs("pool").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`DELETE FROM amenity`).WithArgs("helipad").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs("helipad").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	repo := NewAmenitiesRepository(db)
+	assert.NoError(t, repo.DeleteAmenity(context.Background(), "spa"))
+	assert.True(t, errors.Is(repo.DeleteAmenity(context.Background(), "pool"), utils.ErrAmenityInUse))
+	assert.True(t, errors.Is(repo.DeleteAmenity(context.Background(), "helipad"), utils.ErrAmenityNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
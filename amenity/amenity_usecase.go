@@ -0,0 +1,55 @@
+package amenity
+
+import (
+	"context"
+)
+
+//AmenitiesUseCaseInterface interface
+type AmenitiesUseCaseInterface interface {
+	GetAmenities(ctx context.Context, category string) ([]Amenity, error)
+	GetAmenity(ctx context.Context, code string) (Amenity, error)
+	AddAmenity(ctx context.Context, amenity Amenity) (Amenity, error)
+	UpdateAmenity(ctx context.Context, amenity Amenity) (Amenity, error)
+	DeleteAmenity(ctx context.Context, code string) error
+}
+
+//AmenitiesUseCase struct
+type AmenitiesUseCase struct {
+	repo AmenitiesRepositoryInterface
+}
+
+//GetAmenities returns the catalogue, narrowed to a category when one is given
+func (u *AmenitiesUseCase) GetAmenities(ctx context.Context, category string) ([]Amenity, error) {
+	return u.repo.GetAmenities(ctx, category)
+}
+
+//GetAmenity gets an amenity with its code
+func (u *AmenitiesUseCase) GetAmenity(ctx context.Context, code string) (Amenity, error) {
+	return u.repo.GetAmenity(ctx, code)
+}
+
+//AddAmenity adds an amenity to the catalogue and returns it
+func (u *AmenitiesUseCase) AddAmenity(ctx context.Context, amenity Amenity) (Amenity, error) {
+	if err := u.repo.AddAmenity(ctx, amenity); err != nil {
+		return Amenity{}, err
+	}
+	return amenity, nil
+}
+
+//UpdateAmenity updates an amenity and returns it
+func (u *AmenitiesUseCase) UpdateAmenity(ctx context.Context, amenity Amenity) (Amenity, error) {
+	if err := u.repo.UpdateAmenity(ctx, amenity); err != nil {
+		return Amenity{}, err
+	}
+	return amenity, nil
+}
+
+//DeleteAmenity removes an amenity no item offers from the catalogue
+func (u *AmenitiesUseCase) DeleteAmenity(ctx context.Context, code string) error {
+	return u.repo.DeleteAmenity(ctx, code)
+}
+
+//NewAmenitiesUseCase function
+func NewAmenitiesUseCase(repo *AmenitiesRepository) *AmenitiesUseCase {
+	return &AmenitiesUseCase{repo}
+}
@@ -0,0 +1,60 @@
+package amenity
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) GetAmenities(ctx context.Context, category string) ([]Amenity, error) {
+	args := m.Called(ctx, category)
+	return args.Get(0).([]Amenity), args.Error(1)
+}
+
+func (m *MockRepo) GetAmenity(ctx context.Context, code string) (Amenity, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(Amenity), args.Error(1)
+}
+
+func (m *MockRepo) AddAmenity(ctx context.Context, amenity Amenity) error {
+	args := m.Called(ctx, amenity)
+	return args.Error(0)
+}
+
+func (m *MockRepo) UpdateAmenity(ctx context.Context, amenity Amenity) error {
+	args := m.Called(ctx, amenity)
+	return args.Error(0)
+}
+
+func (m *MockRepo) DeleteAmenity(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func TestAddAmenity(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("AddAmenity", context.Background(), pool).Return(nil)
+	uc := AmenitiesUseCase{repo}
+	res, err := uc.AddAmenity(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, pool, res)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateAmenityNotFoundUseCase(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("UpdateAmenity", context.Background(), pool).Return(utils.ErrAmenityNotFound)
+	uc := AmenitiesUseCase{repo}
+	_, err := uc.UpdateAmenity(context.Background(), pool)
+	assert.True(t, errors.Is(err, utils.ErrAmenityNotFound))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,62 @@
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+//ExchangeRatesHandler handler for the exchange rate table
+type ExchangeRatesHandler struct {
+	useCase ExchangeRatesUseCaseInterface
+	logger  *logrus.Logger
+}
+
+//GetRates get every exchange rate, rates are what one EUR is worth in the currency
+func (h *ExchangeRatesHandler) GetRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.useCase.GetRates(r.Context())
+	if err != nil {
+		h.logger.Info("Error occured while fetching the exchange rates")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the exchange rates")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, rates)
+}
+
+//SaveRates store a list of exchange rates, currencies left out keep their rate
+func (h *ExchangeRatesHandler) SaveRates(w http.ResponseWriter, r *http.Request) {
+	var rates []Rate
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&rates); err != nil || len(rates) == 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload, a list of rates expected")
+		return
+	}
+	invalidParams := []utils.InvalidParams{}
+	for i, rate := range rates {
+		for _, param := range rate.Validate() {
+			param.Name = fmt.Sprintf("[%d].%s", i, param.Name)
+			invalidParams = append(invalidParams, param)
+		}
+	}
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	saved, err := h.useCase.SaveRates(r.Context(), rates)
+	if err != nil {
+		h.logger.Info("Failed to save the exchange rates")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to save the exchange rates")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, saved)
+}
+
+//NewExchangeRatesHandler function
+func NewExchangeRatesHandler(useCase *ExchangeRatesUseCase, log *logrus.Logger) *ExchangeRatesHandler {
+	return &ExchangeRatesHandler{useCase, log}
+}
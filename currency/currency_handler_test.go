@@ -0,0 +1,80 @@
+package currency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUseCase) GetRates(ctx context.Context) ([]Rate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockUseCase) SaveRates(ctx context.Context, rates []Rate) ([]Rate, error) {
+	args := m.Called(ctx, rates)
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockUseCase) LoadRates(ctx context.Context, r io.Reader) (int, error) {
+	args := m.Called(ctx, r)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUseCase) RateTable(ctx context.Context) (RateTable, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(RateTable), args.Error(1)
+}
+
+func TestSaveRatesHandler(t *testing.T) {
+	uc := new(MockUseCase)
+	h := ExchangeRatesHandler{uc, logrus.New()}
+	saved := []Rate{{Currency: "EUR", Rate: 1}, {Currency: "USD", Rate: 1.0853}}
+	uc.On("SaveRates", context.Background(), []Rate{{Currency: "USD", Rate: 1.0853}}).Return(saved, nil)
+	req, _ := http.NewRequest("PUT", "/exchange-rate", bytes.NewBufferString(`[{"currency": "USD", "rate": 1.0853}]`))
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.SaveRates).ServeHTTP(rr, req)
+	var res []Rate
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, res, 2)
+	uc.AssertExpectations(t)
+}
+
+func TestSaveRatesHandlerInvalid(t *testing.T) {
+	uc := new(MockUseCase)
+	h := ExchangeRatesHandler{uc, logrus.New()}
+	req, _ := http.NewRequest("PUT", "/exchange-rate", bytes.NewBufferString(`[{"currency": "USD", "rate": 1.0853}, {"currency": "XYZ", "rate": 2}]`))
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.SaveRates).ServeHTTP(rr, req)
+	var res utils.ErrorModel
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "[1].currency", res.InvalidParams[0].Name)
+	uc.AssertExpectations(t)
+}
+
+func TestSaveRatesHandlerEmpty(t *testing.T) {
+	uc := new(MockUseCase)
+	h := ExchangeRatesHandler{uc, logrus.New()}
+	req, _ := http.NewRequest("PUT", "/exchange-rate", bytes.NewBufferString(`[]`))
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.SaveRates).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
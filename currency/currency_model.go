@@ -0,0 +1,213 @@
+package currency
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+// BaseCurrency is the currency every exchange rate is quoted against, its rate is always 1
+const BaseCurrency = "EUR"
+
+// DefaultCurrency is the currency of items stored without one
+const DefaultCurrency = "EUR"
+
+// RoundingHalfUp rounds a converted amount to the nearest minor unit of its currency, halves up
+const RoundingHalfUp = "half_up"
+
+// minorUnits maps the ISO 4217 codes of the currencies we sell in to the number of decimals of their minor unit,
+// prices are whole numbers of minor units, cents for EUR and yen for JPY
+var minorUnits = map[string]int{
+	"AED": 2,
+	"ARS": 2,
+	"AUD": 2,
+	"BGN": 2,
+	"BHD": 3,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CLP": 0,
+	"CNY": 2,
+	"CZK": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"HUF": 2,
+	"IDR": 2,
+	"ILS": 2,
+	"INR": 2,
+	"ISK": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"MYR": 2,
+	"NOK": 2,
+	"NZD": 2,
+	"PHP": 2,
+	"PLN": 2,
+	"RON": 2,
+	"SAR": 2,
+	"SEK": 2,
+	"SGD": 2,
+	"THB": 2,
+	"TRY": 2,
+	"USD": 2,
+	"VND": 0,
+	"ZAR": 2,
+}
+
+// Known tells whether code is the ISO 4217 code of a currency we sell in
+func Known(code string) bool {
+	_, ok := minorUnits[code]
+	return ok
+}
+
+// MinorUnits returns the number of decimals of the minor unit of a known currency
+func MinorUnits(code string) int {
+	return minorUnits[code]
+}
+
+// Rate is what one unit of the BaseCurrency is worth in Currency
+type Rate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate validates the rate, the rate of the BaseCurrency can only be 1
+func (r Rate) Validate() []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if !Known(r.Currency) {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "currency", Reason: "currency should be an ISO 4217 code like EUR"})
+	}
+	if r.Rate <= 0 {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "rate", Reason: "rate should be > 0"})
+	}
+	if r.Currency == BaseCurrency && r.Rate != 1 {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "rate", Reason: "rate of " + BaseCurrency + " should be 1, rates are quoted against it"})
+	}
+	return validationErr
+}
+
+// Conversion is a price converted to another currency. Amount is the price times the exact rate, in minor units
+// of Currency, rounded once with Rounding. Rate is that rate to 8 decimals and RatesAsOf is when the older of
+// the two rates used was stored
+type Conversion struct {
+	Currency   string    `json:"currency"`
+	Amount     uint64    `json:"amount"`
+	MinorUnits int       `json:"minor_units"`
+	Rate       float64   `json:"rate"`
+	Rounding   string    `json:"rounding"`
+	RatesAsOf  time.Time `json:"rates_as_of"`
+}
+
+// RateTable converts prices with a set of exchange rates
+type RateTable struct {
+	rates map[string]Rate
+}
+
+// NewRateTable returns a table of the rates, the BaseCurrency is 1 when it is not among them
+func NewRateTable(rates []Rate) RateTable {
+	table := RateTable{rates: map[string]Rate{BaseCurrency: {Currency: BaseCurrency, Rate: 1}}}
+	for _, rate := range rates {
+		table.rates[rate.Currency] = rate
+	}
+	return table
+}
+
+// Convert converts amount minor units of from to minor units of to through the BaseCurrency. The exact
+// result is rounded once, half up, so converting through the table gives the same amount whatever the path
+func (t RateTable) Convert(amount uint64, from, to string) (Conversion, error) {
+	fromRate, ok := t.rates[from]
+	if !ok {
+		return Conversion{}, fmt.Errorf("%w %s", utils.ErrExchangeRateNotFound, from)
+	}
+	toRate, ok := t.rates[to]
+	if !ok {
+		return Conversion{}, fmt.Errorf("%w %s", utils.ErrExchangeRateNotFound, to)
+	}
+
+	// amount / 10^minor(from) / rate(from) * rate(to) * 10^minor(to)
+	rate := new(big.Rat).Quo(exactRate(toRate.Rate), exactRate(fromRate.Rate))
+	exact := new(big.Rat).SetInt(new(big.Int).SetUint64(amount))
+	exact.Mul(exact, rate)
+	exact.Mul(exact, new(big.Rat).SetFrac(pow10(MinorUnits(to)), pow10(MinorUnits(from))))
+
+	// the base currency has no date unless its rate is stored
+	ratesAsOf := fromRate.UpdatedAt
+	if ratesAsOf.IsZero() || (!toRate.UpdatedAt.IsZero() && toRate.UpdatedAt.Before(ratesAsOf)) {
+		ratesAsOf = toRate.UpdatedAt
+	}
+	reported, _ := strconv.ParseFloat(rate.FloatString(8), 64)
+	return Conversion{
+		Currency:   to,
+		Amount:     roundHalfUp(exact),
+		MinorUnits: MinorUnits(to),
+		Rate:       reported,
+		Rounding:   RoundingHalfUp,
+		RatesAsOf:  ratesAsOf,
+	}, nil
+}
+
+// exactRate is the decimal rate as written, 1.0853 is 10853/10000 and not its nearest float
+func exactRate(rate float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	return r
+}
+
+// pow10 returns 10^n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundHalfUp rounds a non negative number to the nearest whole number, halves up
+func roundHalfUp(r *big.Rat) uint64 {
+	// floor((2 * num + den) / (2 * den))
+	num := new(big.Int).Mul(r.Num(), big.NewInt(2))
+	num.Add(num, r.Denom())
+	den := new(big.Int).Mul(r.Denom(), big.NewInt(2))
+	return new(big.Int).Quo(num, den).Uint64()
+}
+
+// ReadECBRates reads the euro foreign exchange reference rates of the European Central Bank, eurofxref.csv of
+// https://www.ecb.europa.eu/stats/eurofxref/eurofxref.zip. The first line names the currencies after a Date
+// column and the second has their rates, currencies we do not sell in and rates marked N/A are skipped
+func ReadECBRates(r io.Reader) ([]Rate, error) {
+	scanner := bufio.NewScanner(r)
+	lines := [][]string{}
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		lines = append(lines, strings.Split(scanner.Text(), ","))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read the exchange rates %w", utils.ErrInvalidExchangeRates)
+	}
+	if len(lines) < 2 || strings.TrimSpace(lines[0][0]) != "Date" {
+		return nil, fmt.Errorf("A header line starting with Date and a line of rates expected %w", utils.ErrInvalidExchangeRates)
+	}
+	header, values := lines[0], lines[1]
+	rates := []Rate{}
+	for i := 1; i < len(header) && i < len(values); i++ {
+		code := strings.TrimSpace(header[i])
+		value := strings.TrimSpace(values[i])
+		if !Known(code) || value == "" || value == "N/A" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("Invalid rate %q for %s %w", value, code, utils.ErrInvalidExchangeRates)
+		}
+		rates = append(rates, Rate{Currency: code, Rate: rate})
+	}
+	return rates, nil
+}
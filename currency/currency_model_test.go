@@ -0,0 +1,90 @@
+package currency
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+func TestConvert(t *testing.T) {
+	usdDate := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	table := NewRateTable([]Rate{
+		{Currency: "USD", Rate: 1.0853, UpdatedAt: usdDate},
+		{Currency: "JPY", Rate: 160, UpdatedAt: usdDate.AddDate(0, 0, -1)},
+		{Currency: "KWD", Rate: 0.333, UpdatedAt: usdDate},
+	})
+	for _, c := range []struct {
+		amount   uint64
+		from, to string
+		expected uint64
+	}{
+		// 10.00 EUR is 10.853 USD
+		{1000, "EUR", "USD", 1085},
+		// 1000.00 EUR is exactly 1085.30 USD, the rate is not taken as its nearest float
+		{100000, "EUR", "USD", 108530},
+		// 100 JPY is 0.675 USD, halves are rounded up
+		{100, "JPY", "USD", 68},
+		// 10.00 EUR is 3.330 KWD, in fils
+		{1000, "EUR", "KWD", 3330},
+		// 10.00 EUR is 1600 JPY, which has no minor unit
+		{1000, "EUR", "JPY", 1600},
+		{1000, "EUR", "EUR", 1000},
+	} {
+		conversion, err := table.Convert(c.amount, c.from, c.to)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+		if conversion.Amount != c.expected || conversion.Currency != c.to || conversion.Rounding != RoundingHalfUp {
+			t.Errorf("Expected %d %s from %d %s got %+v", c.expected, c.to, c.amount, c.from, conversion)
+		}
+	}
+
+	conversion, _ := table.Convert(100, "JPY", "USD")
+	if conversion.Rate != 0.00678313 || conversion.MinorUnits != 2 || !conversion.RatesAsOf.Equal(usdDate.AddDate(0, 0, -1)) {
+		t.Errorf("Expected the JPY to USD rate as of the JPY rate got %+v", conversion)
+	}
+}
+
+func TestConvertNoRate(t *testing.T) {
+	table := NewRateTable(nil)
+	if _, err := table.Convert(1000, "EUR", "GBP"); !errors.Is(err, utils.ErrExchangeRateNotFound) || !strings.HasSuffix(err.Error(), "GBP") {
+		t.Errorf("Expected ErrExchangeRateNotFound for GBP got %v", err)
+	}
+	if _, err := table.Convert(1000, "INR", "EUR"); !errors.Is(err, utils.ErrExchangeRateNotFound) {
+		t.Errorf("Expected ErrExchangeRateNotFound got %v", err)
+	}
+}
+
+func TestRateValidate(t *testing.T) {
+	if invalid := (Rate{Currency: "USD", Rate: 1.0853}).Validate(); len(invalid) != 0 {
+		t.Errorf("Expected a valid rate got %v", invalid)
+	}
+	if invalid := (Rate{Currency: "usd", Rate: 0}).Validate(); len(invalid) != 2 {
+		t.Errorf("Expected the currency and the rate to be invalid got %v", invalid)
+	}
+	if invalid := (Rate{Currency: "EUR", Rate: 1.1}).Validate(); len(invalid) != 1 || invalid[0].Name != "rate" {
+		t.Errorf("Expected the base rate to be invalid got %v", invalid)
+	}
+}
+
+func TestReadECBRates(t *testing.T) {
+	file := "Date, USD, JPY, RUB, XYZ, \n16 October 2026, 1.0853, 161.23, N/A, 2.0, \n"
+	rates, err := ReadECBRates(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(rates) != 2 || rates[0].Currency != "USD" || rates[0].Rate != 1.0853 || rates[1].Currency != "JPY" || rates[1].Rate != 161.23 {
+		t.Errorf("Expected the USD and JPY rates got %+v", rates)
+	}
+}
+
+func TestReadECBRatesInvalid(t *testing.T) {
+	for _, file := range []string{"", "USD, JPY\n1.0853, 161.23\n", "Date, USD\n16 October 2026, abc\n", "Date, USD\n"} {
+		if _, err := ReadECBRates(strings.NewReader(file)); !errors.Is(err, utils.ErrInvalidExchangeRates) {
+			t.Errorf("Expected ErrInvalidExchangeRates for %q got %v", file, err)
+		}
+	}
+}
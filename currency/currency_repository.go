@@ -0,0 +1,73 @@
+package currency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//ExchangeRatesRepositoryInterface interface
+type ExchangeRatesRepositoryInterface interface {
+	GetRates(ctx context.Context) ([]Rate, error)
+	SaveRates(ctx context.Context, rates []Rate) error
+}
+
+//ExchangeRatesRepository struct
+type ExchangeRatesRepository struct {
+	db *sql.DB
+}
+
+//GetRates returns every exchange rate by currency
+func (r *ExchangeRatesRepository) GetRates(ctx context.Context) ([]Rate, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT currency, rate, updated_at FROM exchange_rate ORDER BY currency;`)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching exchange rates %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	rates := []Rate{}
+	for rows.Next() {
+		var rate Rate
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("Error occured while fetching exchange rates %w", utils.ErrFetchError)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching exchange rates %w", utils.ErrFetchError)
+	}
+	return rates, nil
+}
+
+//SaveRates stores the rates in one statement, the rate of a currency that is already stored is replaced
+func (r *ExchangeRatesRepository) SaveRates(ctx context.Context, rates []Rate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	// a statement can not insert and then update the same row, so the last of the duplicates wins
+	unique := map[string]int{}
+	values := []string{}
+	args := []interface{}{}
+	for _, rate := range rates {
+		if i, ok := unique[rate.Currency]; ok {
+			args[i*2+1] = rate.Rate
+			continue
+		}
+		unique[rate.Currency] = len(values)
+		values = append(values, fmt.Sprintf("($%d, $%d, NOW())", len(args)+1, len(args)+2))
+		args = append(args, rate.Currency, rate.Rate)
+	}
+	query := `INSERT INTO exchange_rate(currency, rate, updated_at) VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at;`
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("Error occured while saving exchange rates %w", utils.ErrExchangeRatesNotSaved)
+	}
+	return nil
+}
+
+//NewExchangeRatesRepository function
+func NewExchangeRatesRepository(db *sql.DB) *ExchangeRatesRepository {
+	return &ExchangeRatesRepository{db}
+}
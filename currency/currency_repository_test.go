@@ -0,0 +1,55 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	updatedAt := time.Now()
+	mock.ExpectQuery(`SELECT currency, rate, updated_at FROM exchange_rate ORDER BY currency`).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "rate", "updated_at"}).AddRow("EUR", "1.0000000000", updatedAt).AddRow("USD", "1.0853000000", updatedAt))
+	repo := NewExchangeRatesRepository(db)
+	rates, err := repo.GetRates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Rate{{Currency: "EUR", Rate: 1, UpdatedAt: updatedAt}, {Currency: "USD", Rate: 1.0853, UpdatedAt: updatedAt}}, rates)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveRates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`INSERT INTO exchange_rate\(currency, rate, updated_at\) VALUES \(\$1, \$2, NOW\(\)\), \(\$3, \$4, NOW\(\)\)\s+ON CONFLICT \(currency\) DO UPDATE`).
+		WithArgs("USD", 1.09, "JPY", 161.23).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	repo := NewExchangeRatesRepository(db)
+	err = repo.SaveRates(context.Background(), []Rate{{Currency: "USD", Rate: 1.0853}, {Currency: "JPY", Rate: 161.23}, {Currency: "USD", Rate: 1.09}})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveRatesError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`INSERT INTO exchange_rate`).WillReturnError(errors.New("error"))
+	repo := NewExchangeRatesRepository(db)
+	err = repo.SaveRates(context.Background(), []Rate{{Currency: "USD", Rate: 1.0853}})
+	assert.True(t, errors.Is(err, utils.ErrExchangeRatesNotSaved))
+}
@@ -0,0 +1,55 @@
+package currency
+
+import (
+	"context"
+	"io"
+)
+
+//ExchangeRatesUseCaseInterface interface
+type ExchangeRatesUseCaseInterface interface {
+	GetRates(ctx context.Context) ([]Rate, error)
+	SaveRates(ctx context.Context, rates []Rate) ([]Rate, error)
+	LoadRates(ctx context.Context, r io.Reader) (int, error)
+	RateTable(ctx context.Context) (RateTable, error)
+}
+
+//ExchangeRatesUseCase struct
+type ExchangeRatesUseCase struct {
+	repo ExchangeRatesRepositoryInterface
+}
+
+//GetRates returns every exchange rate by currency
+func (u *ExchangeRatesUseCase) GetRates(ctx context.Context) ([]Rate, error) {
+	return u.repo.GetRates(ctx)
+}
+
+//SaveRates stores the rates and returns every exchange rate, currencies left out keep their rate
+func (u *ExchangeRatesUseCase) SaveRates(ctx context.Context, rates []Rate) ([]Rate, error) {
+	if err := u.repo.SaveRates(ctx, rates); err != nil {
+		return nil, err
+	}
+	return u.repo.GetRates(ctx)
+}
+
+//LoadRates stores the rates of an ECB reference rates file and returns how many were read
+func (u *ExchangeRatesUseCase) LoadRates(ctx context.Context, r io.Reader) (int, error) {
+	rates, err := ReadECBRates(r)
+	if err != nil {
+		return 0, err
+	}
+	return len(rates), u.repo.SaveRates(ctx, rates)
+}
+
+//RateTable returns a table of the stored rates to convert prices with
+func (u *ExchangeRatesUseCase) RateTable(ctx context.Context) (RateTable, error) {
+	rates, err := u.repo.GetRates(ctx)
+	if err != nil {
+		return RateTable{}, err
+	}
+	return NewRateTable(rates), nil
+}
+
+//NewExchangeRatesUseCase function
+func NewExchangeRatesUseCase(repo *ExchangeRatesRepository) *ExchangeRatesUseCase {
+	return &ExchangeRatesUseCase{repo}
+}
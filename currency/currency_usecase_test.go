@@ -0,0 +1,56 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) GetRates(ctx context.Context) ([]Rate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockRepo) SaveRates(ctx context.Context, rates []Rate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func TestLoadRates(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("SaveRates", context.Background(), []Rate{{Currency: "USD", Rate: 1.0853}, {Currency: "JPY", Rate: 161.23}}).Return(nil)
+	uc := ExchangeRatesUseCase{repo}
+	count, err := uc.LoadRates(context.Background(), strings.NewReader("Date, USD, JPY, \n16 October 2026, 1.0853, 161.23, \n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	repo.AssertExpectations(t)
+}
+
+func TestLoadRatesInvalid(t *testing.T) {
+	repo := new(MockRepo)
+	uc := ExchangeRatesUseCase{repo}
+	_, err := uc.LoadRates(context.Background(), strings.NewReader("USD,1.0853\n"))
+	assert.True(t, errors.Is(err, utils.ErrInvalidExchangeRates))
+	repo.AssertExpectations(t)
+}
+
+func TestRateTable(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("GetRates", context.Background()).Return([]Rate{{Currency: "USD", Rate: 2}}, nil)
+	uc := ExchangeRatesUseCase{repo}
+	table, err := uc.RateTable(context.Background())
+	assert.NoError(t, err)
+	conversion, err := table.Convert(1000, "EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2000), conversion.Amount)
+	repo.AssertExpectations(t)
+}
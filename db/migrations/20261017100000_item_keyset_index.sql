@@ -0,0 +1,24 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE INDEX idx_item_price ON item (price, item_id);
+
+CREATE INDEX idx_item_rating ON item (rating, item_id);
+
+CREATE INDEX idx_item_reputation ON item (reputation, item_id);
+
+CREATE INDEX idx_item_name ON item (name, item_id);
+
+CREATE INDEX idx_item_availability ON item (availability, item_id);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX idx_item_price;
+
+DROP INDEX idx_item_rating;
+
+DROP INDEX idx_item_reputation;
+
+DROP INDEX idx_item_name;
+
+DROP INDEX idx_item_availability;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE item_booking
+    ADD COLUMN check_in DATE,
+    ADD COLUMN check_out DATE;
+
+-- rooms booked on a night, item.availability is the number of rooms sold each night
+CREATE TABLE item_inventory
+(
+    item_id INT NOT NULL,
+    date DATE NOT NULL,
+    rooms_total INT NOT NULL,
+    rooms_booked INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, date),
+    CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE CASCADE
+);
+
+-- bookings made before this migration have no dates and took their rooms for good, hand them back
+UPDATE item
+SET availability = item.availability + booked.rooms
+FROM (SELECT item_id, SUM(no_of_rooms) AS rooms FROM item_booking GROUP BY item_id) AS booked
+WHERE item.item_id = booked.item_id;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE item_inventory;
+
+-- bookings take their rooms for good again, as before the migration
+UPDATE item
+SET availability = item.availability - booked.rooms
+FROM (SELECT item_id, SUM(no_of_rooms) AS rooms FROM item_booking GROUP BY item_id) AS booked
+WHERE item.item_id = booked.item_id;
+
+ALTER TABLE item_booking
+    DROP COLUMN check_in,
+    DROP COLUMN check_out;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE item
+    ADD CONSTRAINT chk_item_availability CHECK (availability >= 0);
+
+ALTER TABLE item_inventory
+    ADD CONSTRAINT chk_item_inventory_rooms CHECK (rooms_booked >= 0 AND rooms_booked <= rooms_total);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE item_inventory
+    DROP CONSTRAINT chk_item_inventory_rooms;
+
+ALTER TABLE item
+    DROP CONSTRAINT chk_item_availability;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE item_booking
+    ADD COLUMN status VARCHAR ( 20 ) NOT NULL DEFAULT 'confirmed',
+    ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_item_booking_item ON item_booking (item_id);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX idx_item_booking_item;
+
+ALTER TABLE item_booking
+    DROP COLUMN status,
+    DROP COLUMN cancelled_at;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE item_booking
+    ADD COLUMN confirmation_code VARCHAR ( 9 ),
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ADD COLUMN confirmed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN checked_in_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN no_show_at TIMESTAMP WITH TIME ZONE,
+    ALTER COLUMN status SET DEFAULT 'pending';
+
+-- existing bookings get a code in the same XXXX-XXXX shape, drawn from the alphabet the api uses
+UPDATE item_booking
+SET confirmation_code = (
+    SELECT STRING_AGG(
+        CASE WHEN i = 5 THEN '-' ELSE SUBSTR('ABCDEFGHJKLMNPQRSTUVWXYZ23456789', 1 + FLOOR(RANDOM() * 32)::int, 1) END,
+        '' ORDER BY i)
+    FROM GENERATE_SERIES(1, 9) AS i
+    -- referencing the row makes the subquery run once per booking
+    WHERE id_booking IS NOT NULL
+);
+
+ALTER TABLE item_booking
+    ALTER COLUMN confirmation_code SET NOT NULL,
+    ADD CONSTRAINT uq_item_booking_confirmation_code UNIQUE (confirmation_code),
+    ADD CONSTRAINT chk_item_booking_status CHECK (status IN ('pending', 'confirmed', 'checked_in', 'completed', 'cancelled', 'no_show'));
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE item_booking
+    DROP CONSTRAINT chk_item_booking_status,
+    DROP CONSTRAINT uq_item_booking_confirmation_code,
+    DROP COLUMN confirmation_code,
+    DROP COLUMN created_at,
+    DROP COLUMN updated_at,
+    DROP COLUMN confirmed_at,
+    DROP COLUMN checked_in_at,
+    DROP COLUMN completed_at,
+    DROP COLUMN no_show_at,
+    ALTER COLUMN status SET DEFAULT 'confirmed';
@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE idempotency_key
+(
+    idempotency_key VARCHAR ( 255 ) PRIMARY KEY,
+    request_hash CHAR ( 64 ) NOT NULL,
+    status_code INT,
+    response_headers JSONB,
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_idempotency_key_created_at ON idempotency_key (created_at);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE idempotency_key;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE item ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- bookings outlive a purged item, they keep their details without the item
+ALTER TABLE item_booking
+    DROP CONSTRAINT fk_item,
+    ALTER COLUMN item_id DROP NOT NULL,
+    ADD CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE SET NULL;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DELETE FROM item_booking WHERE item_id IS NULL;
+
+ALTER TABLE item_booking
+    DROP CONSTRAINT fk_item,
+    ALTER COLUMN item_id SET NOT NULL,
+    ADD CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE CASCADE;
+
+ALTER TABLE item DROP COLUMN deleted_at;
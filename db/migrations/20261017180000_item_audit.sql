@@ -0,0 +1,24 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- append only history of the items, it has no foreign key so it outlives a purged item
+CREATE TABLE item_audit
+(
+    id BIGSERIAL PRIMARY KEY,
+    item_id INT NOT NULL,
+    action VARCHAR ( 20 ) NOT NULL,
+    actor VARCHAR ( 100 ) NOT NULL,
+    request_id VARCHAR ( 100 ) NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_audit_item ON item_audit (item_id, id);
+
+CREATE RULE item_audit_no_update AS ON UPDATE TO item_audit DO INSTEAD NOTHING;
+CREATE RULE item_audit_no_delete AS ON DELETE TO item_audit DO INSTEAD NOTHING;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE item_audit;
@@ -0,0 +1,64 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- the search document of an item, the name weighs most, then the city, state and country, then the address.
+-- The simple configuration does not stem, names of hotels and places are not English words
+-- +goose StatementBegin
+CREATE FUNCTION item_search_vector(INT, TEXT) RETURNS TSVECTOR AS $$
+    SELECT
+        setweight(to_tsvector('simple', $2), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(string_agg(concat_ws(' ', city, state, country), ' '), '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(string_agg(address, ' '), '')), 'C')
+    FROM item_location
+    WHERE item_location.item_id = $1
+$$ LANGUAGE SQL STABLE;
+-- +goose StatementEnd
+
+ALTER TABLE item ADD COLUMN search_vector TSVECTOR NOT NULL DEFAULT ''::TSVECTOR;
+
+UPDATE item SET search_vector = item_search_vector(item_id, name);
+
+-- +goose StatementBegin
+CREATE FUNCTION item_search_vector_refresh() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := item_search_vector(NEW.item_id, NEW.name);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER item_search_vector_refresh BEFORE INSERT OR UPDATE OF name ON item
+    FOR EACH ROW EXECUTE PROCEDURE item_search_vector_refresh();
+
+-- the location is stored after its item, so the item is refreshed once the location is there
+-- +goose StatementBegin
+CREATE FUNCTION item_location_search_vector_refresh() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE item SET search_vector = item_search_vector(item_id, name) WHERE item_id = NEW.item_id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER item_location_search_vector_refresh AFTER INSERT OR UPDATE ON item_location
+    FOR EACH ROW EXECUTE PROCEDURE item_location_search_vector_refresh();
+
+CREATE INDEX idx_item_search_vector ON item USING GIN (search_vector);
+
+-- trigram indexes of the typo tolerant fallback
+CREATE INDEX idx_item_name_trgm ON item USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_item_location_city_trgm ON item_location USING GIN (city gin_trgm_ops);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX idx_item_location_city_trgm;
+DROP INDEX idx_item_name_trgm;
+DROP INDEX idx_item_search_vector;
+DROP TRIGGER item_location_search_vector_refresh ON item_location;
+DROP FUNCTION item_location_search_vector_refresh();
+DROP TRIGGER item_search_vector_refresh ON item;
+DROP FUNCTION item_search_vector_refresh();
+ALTER TABLE item DROP COLUMN search_vector;
+DROP FUNCTION item_search_vector(INT, TEXT);
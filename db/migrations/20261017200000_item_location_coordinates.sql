@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE item_location
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT chk_item_location_latitude CHECK (latitude BETWEEN -90 AND 90),
+    ADD CONSTRAINT chk_item_location_longitude CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT chk_item_location_coordinates CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+-- the nearby search narrows the items to a bounding box before computing the distance
+CREATE INDEX idx_item_location_coordinates ON item_location (latitude, longitude) WHERE latitude IS NOT NULL;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX idx_item_location_coordinates;
+ALTER TABLE item_location
+    DROP COLUMN latitude,
+    DROP COLUMN longitude;
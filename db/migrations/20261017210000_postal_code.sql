@@ -0,0 +1,28 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- postal code reference data, loaded from GeoNames dumps with go run . postal-codes
+CREATE TABLE postal_code
+(
+    country_code CHAR ( 2 ) NOT NULL,
+    zip_code VARCHAR ( 20 ) NOT NULL,
+    city VARCHAR ( 180 ) NOT NULL,
+    state VARCHAR ( 100 ) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (country_code, zip_code, city)
+);
+
+-- postal codes are looked up without their spaces, 1012AB finds 1012 AB
+CREATE INDEX idx_postal_code_compact ON postal_code (country_code, UPPER(REPLACE(zip_code, ' ', '')));
+
+ALTER TABLE item_location ADD COLUMN city_mismatch BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_item_location_city_mismatch ON item_location (item_id) WHERE city_mismatch;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX idx_item_location_city_mismatch;
+ALTER TABLE item_location DROP COLUMN city_mismatch;
+DROP INDEX idx_postal_code_compact;
+DROP TABLE postal_code;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- zip codes are text, the leading zeros of countries with five digit zip codes that were dropped
+-- while they were stored as numbers are put back
+ALTER TABLE item_location ALTER COLUMN zip_code TYPE VARCHAR ( 10 ) USING
+    CASE
+        WHEN LOWER(TRIM(country)) IN ('de', 'germany', 'es', 'spain', 'fi', 'finland', 'fr', 'france', 'it', 'italy',
+            'mx', 'mexico', 'th', 'thailand', 'tr', 'turkey', 'us', 'usa', 'united states')
+            AND zip_code BETWEEN 0 AND 99999 THEN LPAD(zip_code::TEXT, 5, '0')
+        ELSE zip_code::TEXT
+    END;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- zip codes that are not numbers can not be kept
+UPDATE item_location SET zip_code = '0' WHERE zip_code !~ '^[0-9]{1,9}$';
+ALTER TABLE item_location ALTER COLUMN zip_code TYPE INT USING zip_code::INT;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- the gallery of an item ordered by position, item.image is kept as the url of the primary image
+CREATE TABLE item_image
+(
+    id serial PRIMARY KEY,
+    item_id INT NOT NULL,
+    url TEXT NOT NULL,
+    position INT NOT NULL CHECK (position >= 0),
+    caption VARCHAR ( 250 ) NOT NULL DEFAULT '',
+    width INT CHECK (width > 0),
+    height INT CHECK (height > 0),
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- images are moved by shifting their neighbours, positions are unique once the move is committed
+    CONSTRAINT uq_item_image_position UNIQUE (item_id, position) DEFERRABLE INITIALLY DEFERRED,
+    CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_item_image_primary ON item_image (item_id) WHERE is_primary;
+
+-- the image of every item becomes the first and primary image of its gallery
+INSERT INTO item_image(item_id, url, position, is_primary)
+SELECT item_id, image, 0, TRUE FROM item WHERE image <> '';
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE item_image;
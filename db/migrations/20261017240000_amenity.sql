@@ -0,0 +1,48 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- the catalogue of amenities items can offer, items and filters refer to them by code
+CREATE TABLE amenity
+(
+    id serial PRIMARY KEY,
+    code VARCHAR ( 50 ) NOT NULL UNIQUE,
+    name VARCHAR ( 100 ) NOT NULL,
+    category VARCHAR ( 50 ) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- an amenity offered by items can not be deleted, so it is restricted rather than cascaded
+CREATE TABLE item_amenity
+(
+    item_id INT NOT NULL,
+    amenity_id INT NOT NULL,
+    PRIMARY KEY (item_id, amenity_id),
+    CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_amenity
+        FOREIGN KEY(amenity_id)
+        REFERENCES amenity(id)
+        ON DELETE RESTRICT
+);
+
+-- the amenities filter looks up the items of an amenity
+CREATE INDEX idx_item_amenity_amenity_id ON item_amenity (amenity_id, item_id);
+
+INSERT INTO amenity(code, name, category) VALUES
+    ('wifi', 'Free WiFi', 'connectivity'),
+    ('parking', 'Parking', 'transport'),
+    ('airport-shuttle', 'Airport shuttle', 'transport'),
+    ('pool', 'Swimming pool', 'wellness'),
+    ('spa', 'Spa', 'wellness'),
+    ('gym', 'Fitness centre', 'wellness'),
+    ('restaurant', 'Restaurant', 'food'),
+    ('breakfast', 'Breakfast included', 'food'),
+    ('air-conditioning', 'Air conditioning', 'room'),
+    ('pet-friendly', 'Pets allowed', 'policy');
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE item_amenity;
+DROP TABLE amenity;
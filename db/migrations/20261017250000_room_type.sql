@@ -0,0 +1,58 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- the room categories an item sells, like a double room or a dorm bed. price covers capacity guests and
+-- a room takes up to max_occupancy guests, inventory is the number of rooms sold each night
+CREATE TABLE room_type
+(
+    id serial PRIMARY KEY,
+    item_id INT NOT NULL,
+    name VARCHAR ( 50 ) NOT NULL,
+    price INT NOT NULL CHECK (price > 0),
+    capacity INT NOT NULL CHECK (capacity > 0),
+    max_occupancy INT NOT NULL,
+    inventory INT NOT NULL CHECK (inventory >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_room_type_name UNIQUE (item_id, name),
+    CONSTRAINT chk_room_type_occupancy CHECK (max_occupancy >= capacity),
+    CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE CASCADE
+);
+
+-- rooms of a room type booked on a night, like item_inventory is for items without room types
+CREATE TABLE room_type_inventory
+(
+    room_type_id INT NOT NULL,
+    date DATE NOT NULL,
+    rooms_total INT NOT NULL,
+    rooms_booked INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (room_type_id, date),
+    CONSTRAINT chk_room_type_inventory_rooms CHECK (rooms_booked >= 0 AND rooms_booked <= rooms_total),
+    CONSTRAINT fk_room_type
+        FOREIGN KEY(room_type_id)
+        REFERENCES room_type(id)
+        ON DELETE CASCADE
+);
+
+-- past bookings of a removed room type are kept without it
+ALTER TABLE item_booking
+    ADD COLUMN room_type_id INT,
+    ADD COLUMN guests INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT fk_room_type
+        FOREIGN KEY(room_type_id)
+        REFERENCES room_type(id)
+        ON DELETE SET NULL;
+
+CREATE INDEX idx_item_booking_room_type_id ON item_booking (room_type_id);
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE item_booking
+    DROP CONSTRAINT fk_room_type,
+    DROP COLUMN room_type_id,
+    DROP COLUMN guests;
+
+DROP TABLE room_type_inventory;
+DROP TABLE room_type;
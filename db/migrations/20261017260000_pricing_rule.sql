@@ -0,0 +1,47 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- pricing rules of the price of an item, or of one of its room types when room_type_id is set. A nightly
+-- rule sets the price of the nights in its dates and weekdays, a stay_discount rule takes discount_percent
+-- off stays of min_nights or more checking in within its dates
+CREATE TABLE pricing_rule
+(
+    id serial PRIMARY KEY,
+    item_id INT NOT NULL,
+    room_type_id INT,
+    name VARCHAR ( 100 ) NOT NULL,
+    kind VARCHAR ( 20 ) NOT NULL,
+    start_date DATE,
+    end_date DATE,
+    weekdays TEXT[] NOT NULL DEFAULT '{}',
+    min_nights INT NOT NULL DEFAULT 0 CHECK (min_nights >= 0),
+    price INT NOT NULL DEFAULT 0,
+    discount_percent INT NOT NULL DEFAULT 0,
+    priority INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_pricing_rule_kind CHECK (
+        (kind = 'nightly' AND price > 0 AND discount_percent = 0) OR
+        (kind = 'stay_discount' AND price = 0 AND discount_percent BETWEEN 1 AND 100)),
+    CONSTRAINT chk_pricing_rule_dates CHECK (end_date >= start_date),
+    CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_room_type
+        FOREIGN KEY(room_type_id)
+        REFERENCES room_type(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pricing_rule_item_id ON pricing_rule (item_id, room_type_id);
+
+-- the total quoted when the booking was made, bookings made before pricing rules have none
+ALTER TABLE item_booking
+    ADD COLUMN total_price BIGINT;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE item_booking
+    DROP COLUMN total_price;
+
+DROP TABLE pricing_rule;
@@ -0,0 +1,51 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- prices are whole numbers of minor units of the currency of their item, items stored before currencies are in EUR
+ALTER TABLE item
+    ADD COLUMN currency CHAR ( 3 ) NOT NULL DEFAULT 'EUR';
+
+-- what one EUR is worth in each currency, loaded from the ECB reference rates with go run . exchange-rates
+CREATE TABLE exchange_rate
+(
+    currency CHAR ( 3 ) PRIMARY KEY,
+    rate NUMERIC ( 20, 10 ) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_exchange_rate_rate CHECK (rate > 0)
+);
+
+INSERT INTO exchange_rate(currency, rate) VALUES ('EUR', 1);
+
+-- the currency of the total price, the currency of the item when the booking was made
+ALTER TABLE item_booking
+    ADD COLUMN currency CHAR ( 3 );
+
+UPDATE item_booking SET currency = 'EUR' WHERE total_price IS NOT NULL;
+
+-- prices stored before currencies are whole EUR, they become cents
+UPDATE item SET price = price * 100;
+
+UPDATE room_type SET price = price * 100;
+
+UPDATE pricing_rule SET price = price * 100;
+
+UPDATE item_booking SET total_price = total_price * 100 WHERE total_price IS NOT NULL;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- prices go back to whole units, rounded half up
+UPDATE item_booking SET total_price = (total_price + 50) / 100 WHERE total_price IS NOT NULL;
+
+UPDATE pricing_rule SET price = GREATEST((price + 50) / 100, 1) WHERE kind = 'nightly';
+
+UPDATE room_type SET price = GREATEST((price + 50) / 100, 1);
+
+UPDATE item SET price = (price + 50) / 100;
+
+ALTER TABLE item_booking
+    DROP COLUMN currency;
+
+DROP TABLE exchange_rate;
+
+ALTER TABLE item
+    DROP COLUMN currency;
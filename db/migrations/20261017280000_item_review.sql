@@ -0,0 +1,37 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- reviews of guests, a completed booking is reviewed once
+CREATE TABLE item_review
+(
+    id serial PRIMARY KEY,
+    item_id INT NOT NULL,
+    booking_id INT NOT NULL,
+    guest_name VARCHAR ( 50 ) NOT NULL,
+    score INT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_item_review_booking_id UNIQUE (booking_id),
+    CONSTRAINT chk_item_review_score CHECK (score BETWEEN 1 AND 10),
+    CONSTRAINT fk_item
+        FOREIGN KEY(item_id)
+        REFERENCES item(item_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_item_booking
+        FOREIGN KEY(booking_id)
+        REFERENCES item_booking(id_booking)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_item_review_item_id ON item_review (item_id, created_at DESC, id DESC);
+
+-- the reputation of an item with reviews is computed from them
+ALTER TABLE item
+    ADD COLUMN review_count INT NOT NULL DEFAULT 0;
+
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE item
+    DROP COLUMN review_count;
+
+DROP TABLE item_review;
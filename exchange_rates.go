@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sayooj/trivago/currency"
+)
+
+//runLoadExchangeRates stores the rates of ECB reference rate files, it returns the exit code
+func runLoadExchangeRates(args []string) int {
+	flags := flag.NewFlagSet("exchange-rates", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: exchange-rates <file>...")
+		fmt.Fprintln(flags.Output(), "files are ECB reference rates, eurofxref.csv of https://www.ecb.europa.eu/stats/eurofxref/eurofxref.zip")
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	uc := currency.NewExchangeRatesUseCase(currency.NewExchangeRatesRepository(server.db))
+	for _, path := range flags.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		count, err := uc.LoadRates(context.Background(), file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d rates loaded\n", path, count)
+	}
+	return 0
+}
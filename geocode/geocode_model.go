@@ -0,0 +1,179 @@
+package geocode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+// PostalCode is a place of the postal code reference data, a postal code may cover several places
+type PostalCode struct {
+	CountryCode string
+	ZipCode     string
+	City        string
+	State       string
+	Latitude    float64
+	Longitude   float64
+}
+
+// Match is what the reference data tells about a location
+type Match struct {
+	// CountryKnown is set when postal codes of the country are loaded, only then a location can be checked
+	CountryKnown bool
+	Found        bool
+	// PostalCode is the place of the city of the location, or the first place of the postal code
+	PostalCode PostalCode
+	// CityMatches is set when the city of the location is one of the places of the postal code
+	CityMatches bool
+}
+
+// countryCodes maps country names to the ISO 3166-1 alpha-2 codes the reference data is keyed by
+var countryCodes = map[string]string{
+	"argentina":      "AR",
+	"australia":      "AU",
+	"austria":        "AT",
+	"belgium":        "BE",
+	"brazil":         "BR",
+	"canada":         "CA",
+	"czech republic": "CZ",
+	"czechia":        "CZ",
+	"denmark":        "DK",
+	"finland":        "FI",
+	"france":         "FR",
+	"germany":        "DE",
+	"india":          "IN",
+	"ireland":        "IE",
+	"italy":          "IT",
+	"japan":          "JP",
+	"mexico":         "MX",
+	"netherlands":    "NL",
+	"new zealand":    "NZ",
+	"norway":         "NO",
+	"poland":         "PL",
+	"portugal":       "PT",
+	"south africa":   "ZA",
+	"spain":          "ES",
+	"sweden":         "SE",
+	"switzerland":    "CH",
+	"thailand":       "TH",
+	"turkey":         "TR",
+	"united kingdom": "GB",
+	"united states":  "US",
+	"usa":            "US",
+}
+
+// outwardCodes cut a compact postal code down to the part the GeoNames dumps of a country carry, the dumps of
+// these countries only have the outward or partial codes, like K1A for K1A 0B1 and SW1A for SW1A 1AA
+var outwardCodes = map[string]func(string) string{
+	"CA": func(code string) string { return prefix(code, 3) },
+	"GB": func(code string) string {
+		// the inward code is always a digit and two letters
+		if len(code) < 5 {
+			return code
+		}
+		return code[:len(code)-3]
+	},
+	"NL": func(code string) string { return prefix(code, 4) },
+}
+
+// prefix returns the first n characters of code, a shorter code is returned whole
+func prefix(code string, n int) string {
+	if len(code) < n {
+		return code
+	}
+	return code[:n]
+}
+
+// CompactZipCode upper-cases a postal code and drops its spaces, postal codes are compared this way so that
+// 1012AB and 1012 AB are the same
+func CompactZipCode(zipCode string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(zipCode), ""))
+}
+
+// OutwardCode returns the part of a compact postal code that the reference data of a country with partial
+// dumps holds, ok is false when the dumps of the country have full codes or the code is already that part
+func OutwardCode(countryCode, zipCode string) (string, bool) {
+	outward, ok := outwardCodes[countryCode]
+	if !ok {
+		return "", false
+	}
+	code := outward(zipCode)
+	return code, code != zipCode
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of a country name, a two letter country is taken as a code
+func CountryCode(country string) (string, bool) {
+	name := strings.ToLower(strings.TrimSpace(country))
+	if code, ok := countryCodes[name]; ok {
+		return code, true
+	}
+	if len(name) == 2 && name[0] >= 'a' && name[0] <= 'z' && name[1] >= 'a' && name[1] <= 'z' {
+		return strings.ToUpper(name), true
+	}
+	return "", false
+}
+
+// NewMatch picks the place of city among the places of a postal code
+func NewMatch(places []PostalCode, city string) Match {
+	match := Match{CountryKnown: true, Found: len(places) > 0}
+	if !match.Found {
+		return match
+	}
+	match.PostalCode = places[0]
+	for _, place := range places {
+		if sameCity(place.City, city) {
+			match.PostalCode = place
+			match.CityMatches = true
+			break
+		}
+	}
+	return match
+}
+
+// sameCity compares a place of the reference data with a city ignoring case, the place may
+// carry a suffix like "Thrissur H.O"
+func sameCity(place, city string) bool {
+	place = strings.ToLower(strings.TrimSpace(place))
+	city = strings.ToLower(strings.TrimSpace(city))
+	return city != "" && (place == city || strings.HasPrefix(place, city+" "))
+}
+
+// ReadGeoNames calls each for every place of a GeoNames postal code dump. The dump is tab separated:
+// country code, postal code, place name, admin name1, admin code1, admin name2, admin code2,
+// admin name3, admin code3, latitude, longitude, accuracy
+func ReadGeoNames(r io.Reader, each func(PostalCode) error) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 11 {
+			return fmt.Errorf("Line %d should have at least 11 tab separated fields %w", line, utils.ErrInvalidPostalCodes)
+		}
+		latitude, latErr := strconv.ParseFloat(strings.TrimSpace(fields[9]), 64)
+		longitude, lngErr := strconv.ParseFloat(strings.TrimSpace(fields[10]), 64)
+		if latErr != nil || lngErr != nil {
+			return fmt.Errorf("Line %d has invalid coordinates %w", line, utils.ErrInvalidPostalCodes)
+		}
+		err := each(PostalCode{
+			CountryCode: strings.ToUpper(strings.TrimSpace(fields[0])),
+			ZipCode:     strings.TrimSpace(fields[1]),
+			City:        strings.TrimSpace(fields[2]),
+			State:       strings.TrimSpace(fields[3]),
+			Latitude:    latitude,
+			Longitude:   longitude,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Failed to read the postal codes %w", utils.ErrInvalidPostalCodes)
+	}
+	return nil
+}
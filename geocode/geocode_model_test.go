@@ -0,0 +1,82 @@
+package geocode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+func TestReadGeoNames(t *testing.T) {
+	dump := "IN\t680001\tThrissur H.O\tKerala\t13\tThrissur\t593\t\t\t10.5167\t76.2167\t4\n" +
+		"\n" +
+		"IN\t680001\tKizhakkumpattukara\tKerala\t13\tThrissur\t593\t\t\t10.5276\t76.2144\t\n"
+	codes := []PostalCode{}
+	err := ReadGeoNames(strings.NewReader(dump), func(code PostalCode) error {
+		codes = append(codes, code)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(codes) != 2 {
+		t.Fatalf("Expected 2 places got %d", len(codes))
+	}
+	expected := PostalCode{CountryCode: "IN", ZipCode: "680001", City: "Thrissur H.O", State: "Kerala", Latitude: 10.5167, Longitude: 76.2167}
+	if codes[0] != expected {
+		t.Errorf("Expected %+v got %+v", expected, codes[0])
+	}
+}
+
+func TestReadGeoNamesInvalid(t *testing.T) {
+	err := ReadGeoNames(strings.NewReader("IN\t680001\tThrissur\n"), func(PostalCode) error { return nil })
+	if !errors.Is(err, utils.ErrInvalidPostalCodes) {
+		t.Errorf("Expected ErrInvalidPostalCodes got %v", err)
+	}
+}
+
+func TestCountryCode(t *testing.T) {
+	for country, expected := range map[string]string{"India": "IN", " united states ": "US", "de": "DE"} {
+		if code, ok := CountryCode(country); !ok || code != expected {
+			t.Errorf("Expected %s for %q got %s", expected, country, code)
+		}
+	}
+	if _, ok := CountryCode("atlantis"); ok {
+		t.Errorf("Expected an unknown country")
+	}
+}
+
+func TestOutwardCode(t *testing.T) {
+	for _, c := range []struct{ country, code, outward string }{
+		{"CA", "K1A0B1", "K1A"},
+		{"GB", "SW1A1AA", "SW1A"},
+		{"GB", "M11AE", "M1"},
+		{"NL", "1012AB", "1012"},
+	} {
+		if outward, ok := OutwardCode(c.country, c.code); !ok || outward != c.outward {
+			t.Errorf("Expected %s for %s %s got %s", c.outward, c.country, c.code, outward)
+		}
+	}
+	if _, ok := OutwardCode("GB", "SW1A"); ok {
+		t.Errorf("Expected no outward code of an outward code")
+	}
+	if _, ok := OutwardCode("IN", "680001"); ok {
+		t.Errorf("Expected no outward code for a country with full codes")
+	}
+}
+
+func TestNewMatch(t *testing.T) {
+	places := []PostalCode{{City: "Ayyanthole"}, {City: "Thrissur H.O", Latitude: 10.5}}
+	match := NewMatch(places, "thrissur")
+	if !match.Found || !match.CityMatches || match.PostalCode.Latitude != 10.5 {
+		t.Errorf("Expected the Thrissur place got %+v", match)
+	}
+	match = NewMatch(places, "Kochi")
+	if !match.Found || match.CityMatches || match.PostalCode.City != "Ayyanthole" {
+		t.Errorf("Expected a mismatch on the first place got %+v", match)
+	}
+	if match = NewMatch(nil, "Kochi"); !match.CountryKnown || match.Found {
+		t.Errorf("Expected an unknown zip code got %+v", match)
+	}
+}
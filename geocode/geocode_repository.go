@@ -0,0 +1,88 @@
+package geocode
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//PostalCodesRepositoryInterface interface
+type PostalCodesRepositoryInterface interface {
+	FindPostalCodes(ctx context.Context, countryCode, zipCode string) ([]PostalCode, error)
+	HasCountry(ctx context.Context, countryCode string) (bool, error)
+	SavePostalCodes(ctx context.Context, codes []PostalCode) error
+}
+
+//PostalCodesRepository struct
+type PostalCodesRepository struct {
+	db *sql.DB
+}
+
+//FindPostalCodes returns the places of a compact postal code, the stored codes are compared without their spaces
+func (r *PostalCodesRepository) FindPostalCodes(ctx context.Context, countryCode, zipCode string) ([]PostalCode, error) {
+	query := `SELECT country_code, zip_code, city, state, latitude, longitude FROM postal_code
+		WHERE country_code = $1 AND UPPER(REPLACE(zip_code, ' ', '')) = $2 ORDER BY city;`
+	rows, err := r.db.QueryContext(ctx, query, countryCode, zipCode)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching postal codes %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	codes := []PostalCode{}
+	for rows.Next() {
+		var code PostalCode
+		if err := rows.Scan(&code.CountryCode, &code.ZipCode, &code.City, &code.State, &code.Latitude, &code.Longitude); err != nil {
+			return nil, fmt.Errorf("Error occured while fetching postal codes %w", utils.ErrFetchError)
+		}
+		codes = append(codes, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching postal codes %w", utils.ErrFetchError)
+	}
+	return codes, nil
+}
+
+//HasCountry tells whether postal codes of the country are loaded
+func (r *PostalCodesRepository) HasCountry(ctx context.Context, countryCode string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM postal_code WHERE country_code = $1);`
+	if err := r.db.QueryRowContext(ctx, query, countryCode).Scan(&exists); err != nil {
+		return false, fmt.Errorf("Error occured while fetching postal codes %w", utils.ErrFetchError)
+	}
+	return exists, nil
+}
+
+//SavePostalCodes stores the places in one statement, a place that is already stored is replaced
+func (r *PostalCodesRepository) SavePostalCodes(ctx context.Context, codes []PostalCode) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	// a statement can not insert and then update the same row, so the last of the duplicates wins
+	unique := map[string]int{}
+	values := []string{}
+	args := []interface{}{}
+	for _, code := range codes {
+		key := code.CountryCode + "\t" + code.ZipCode + "\t" + code.City
+		if i, ok := unique[key]; ok {
+			copy(args[i*6:], []interface{}{code.CountryCode, code.ZipCode, code.City, code.State, code.Latitude, code.Longitude})
+			continue
+		}
+		unique[key] = len(values)
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6))
+		args = append(args, code.CountryCode, code.ZipCode, code.City, code.State, code.Latitude, code.Longitude)
+	}
+	query := `INSERT INTO postal_code(country_code, zip_code, city, state, latitude, longitude) VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (country_code, zip_code, city) DO UPDATE SET state = EXCLUDED.state, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude;`
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("Error occured while saving postal codes %w", utils.ErrPostalCodesNotSaved)
+	}
+	return nil
+}
+
+//NewPostalCodesRepository function
+func NewPostalCodesRepository(db *sql.DB) *PostalCodesRepository {
+	return &PostalCodesRepository{db}
+}
@@ -0,0 +1,57 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPostalCodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT country_code, zip_code, city, state, latitude, longitude FROM postal_code`).WithArgs("IN", "680001").
+		WillReturnRows(sqlmock.NewRows([]string{"country_code", "zip_code", "city", "state", "latitude", "longitude"}).AddRow("IN", "680001", "Thrissur H.O", "Kerala", 10.5167, 76.2167))
+	repo := NewPostalCodesRepository(db)
+	codes, err := repo.FindPostalCodes(context.Background(), "IN", "680001")
+	assert.NoError(t, err)
+	assert.Equal(t, []PostalCode{{CountryCode: "IN", ZipCode: "680001", City: "Thrissur H.O", State: "Kerala", Latitude: 10.5167, Longitude: 76.2167}}, codes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavePostalCodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`INSERT INTO postal_code\(country_code, zip_code, city, state, latitude, longitude\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\), \(\$7, \$8, \$9, \$10, \$11, \$12\)\s+ON CONFLICT`).
+		WithArgs("IN", "680001", "Thrissur", "Kerala", 10.6, 76.3, "IN", "680002", "Ayyanthole", "Kerala", 10.5, 76.2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	repo := NewPostalCodesRepository(db)
+	err = repo.SavePostalCodes(context.Background(), []PostalCode{
+		{CountryCode: "IN", ZipCode: "680001", City: "Thrissur", State: "Kerala", Latitude: 10.5, Longitude: 76.2},
+		{CountryCode: "IN", ZipCode: "680002", City: "Ayyanthole", State: "Kerala", Latitude: 10.5, Longitude: 76.2},
+		{CountryCode: "IN", ZipCode: "680001", City: "Thrissur", State: "Kerala", Latitude: 10.6, Longitude: 76.3},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavePostalCodesError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`INSERT INTO postal_code`).WillReturnError(errors.New("error"))
+	repo := NewPostalCodesRepository(db)
+	err = repo.SavePostalCodes(context.Background(), []PostalCode{{CountryCode: "IN", ZipCode: "680001", City: "Thrissur"}})
+	assert.True(t, errors.Is(err, utils.ErrPostalCodesNotSaved))
+}
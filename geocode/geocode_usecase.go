@@ -0,0 +1,73 @@
+package geocode
+
+import (
+	"context"
+	"io"
+)
+
+// loadBatchSize is the number of places stored in one statement
+const loadBatchSize = 1000
+
+//GeocodeUseCaseInterface interface
+type GeocodeUseCaseInterface interface {
+	Resolve(ctx context.Context, country, zipCode, city string) (Match, error)
+	LoadPostalCodes(ctx context.Context, r io.Reader) (int, error)
+}
+
+//GeocodeUseCase struct
+type GeocodeUseCase struct {
+	repo PostalCodesRepositoryInterface
+}
+
+//Resolve looks up the postal code of a location in the reference data, a country without a code or
+//without loaded postal codes is not known. A full code missing from the partial dumps of a country is looked up
+//by its outward code
+func (u *GeocodeUseCase) Resolve(ctx context.Context, country, zipCode, city string) (Match, error) {
+	countryCode, ok := CountryCode(country)
+	zipCode = CompactZipCode(zipCode)
+	if !ok || zipCode == "" {
+		return Match{}, nil
+	}
+	places, err := u.repo.FindPostalCodes(ctx, countryCode, zipCode)
+	if err != nil {
+		return Match{}, err
+	}
+	if outward, ok := OutwardCode(countryCode, zipCode); ok && len(places) == 0 {
+		places, err = u.repo.FindPostalCodes(ctx, countryCode, outward)
+		if err != nil {
+			return Match{}, err
+		}
+	}
+	if len(places) == 0 {
+		known, err := u.repo.HasCountry(ctx, countryCode)
+		if err != nil || !known {
+			return Match{}, err
+		}
+	}
+	return NewMatch(places, city), nil
+}
+
+//LoadPostalCodes stores the places of a GeoNames postal code dump and returns how many were read
+func (u *GeocodeUseCase) LoadPostalCodes(ctx context.Context, r io.Reader) (int, error) {
+	count := 0
+	batch := make([]PostalCode, 0, loadBatchSize)
+	err := ReadGeoNames(r, func(code PostalCode) error {
+		count++
+		batch = append(batch, code)
+		if len(batch) < loadBatchSize {
+			return nil
+		}
+		err := u.repo.SavePostalCodes(ctx, batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, u.repo.SavePostalCodes(ctx, batch)
+}
+
+//NewGeocodeUseCase function
+func NewGeocodeUseCase(repo *PostalCodesRepository) *GeocodeUseCase {
+	return &GeocodeUseCase{repo}
+}
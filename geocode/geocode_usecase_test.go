@@ -0,0 +1,108 @@
+package geocode
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) FindPostalCodes(ctx context.Context, countryCode, zipCode string) ([]PostalCode, error) {
+	args := m.Called(ctx, countryCode, zipCode)
+	return args.Get(0).([]PostalCode), args.Error(1)
+}
+
+func (m *MockRepo) HasCountry(ctx context.Context, countryCode string) (bool, error) {
+	args := m.Called(ctx, countryCode)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) SavePostalCodes(ctx context.Context, codes []PostalCode) error {
+	args := m.Called(ctx, codes)
+	return args.Error(0)
+}
+
+func TestResolve(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("FindPostalCodes", context.Background(), "IN", "680001").Return([]PostalCode{{City: "Thrissur H.O"}}, nil)
+	uc := GeocodeUseCase{repo}
+	match, err := uc.Resolve(context.Background(), "india", "680001", "Thrissur")
+	assert.NoError(t, err)
+	assert.Equal(t, Match{CountryKnown: true, Found: true, CityMatches: true, PostalCode: PostalCode{City: "Thrissur H.O"}}, match)
+	repo.AssertExpectations(t)
+}
+
+func TestResolveCountryNotLoaded(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("FindPostalCodes", context.Background(), "DE", "10115").Return([]PostalCode{}, nil)
+	repo.On("HasCountry", context.Background(), "DE").Return(false, nil)
+	uc := GeocodeUseCase{repo}
+	match, err := uc.Resolve(context.Background(), "Germany", "10115", "Berlin")
+	assert.NoError(t, err)
+	assert.False(t, match.CountryKnown)
+	repo.AssertExpectations(t)
+}
+
+func TestResolveSpacing(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("FindPostalCodes", context.Background(), "NL", "1012AB").Return([]PostalCode{{City: "Amsterdam"}}, nil)
+	uc := GeocodeUseCase{repo}
+	match, err := uc.Resolve(context.Background(), "Netherlands", " 1012 ab", "Amsterdam")
+	assert.NoError(t, err)
+	assert.True(t, match.Found)
+	assert.True(t, match.CityMatches)
+	repo.AssertExpectations(t)
+}
+
+func TestResolveCanadaFullCode(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("FindPostalCodes", context.Background(), "CA", "K1A0B1").Return([]PostalCode{}, nil)
+	repo.On("FindPostalCodes", context.Background(), "CA", "K1A").Return([]PostalCode{{City: "Ottawa"}}, nil)
+	uc := GeocodeUseCase{repo}
+	match, err := uc.Resolve(context.Background(), "Canada", "K1A 0B1", "Ottawa")
+	assert.NoError(t, err)
+	assert.True(t, match.Found)
+	assert.True(t, match.CityMatches)
+	repo.AssertExpectations(t)
+}
+
+func TestResolveUnitedKingdomFullCode(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("FindPostalCodes", context.Background(), "GB", "SW1A1AA").Return([]PostalCode{}, nil)
+	repo.On("FindPostalCodes", context.Background(), "GB", "SW1A").Return([]PostalCode{{City: "London"}}, nil)
+	uc := GeocodeUseCase{repo}
+	match, err := uc.Resolve(context.Background(), "United Kingdom", "SW1A 1AA", "London")
+	assert.NoError(t, err)
+	assert.True(t, match.Found)
+	assert.True(t, match.CityMatches)
+	repo.AssertExpectations(t)
+}
+
+func TestResolveUnitedKingdomUnknownCode(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("FindPostalCodes", context.Background(), "GB", "ZZ99ZZZ").Return([]PostalCode{}, nil)
+	repo.On("FindPostalCodes", context.Background(), "GB", "ZZ99").Return([]PostalCode{}, nil)
+	repo.On("HasCountry", context.Background(), "GB").Return(true, nil)
+	uc := GeocodeUseCase{repo}
+	match, err := uc.Resolve(context.Background(), "GB", "ZZ99 ZZZ", "London")
+	assert.NoError(t, err)
+	assert.True(t, match.CountryKnown)
+	assert.False(t, match.Found)
+	repo.AssertExpectations(t)
+}
+
+func TestLoadPostalCodes(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("SavePostalCodes", context.Background(), mock.MatchedBy(func(codes []PostalCode) bool { return len(codes) == 2 })).Return(nil)
+	uc := GeocodeUseCase{repo}
+	count, err := uc.LoadPostalCodes(context.Background(), strings.NewReader("IN\t680001\tThrissur\tKerala\t13\t\t\t\t\t10.5\t76.2\t\nIN\t680002\tAyyanthole\tKerala\t13\t\t\t\t\t10.5\t76.2\t\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	repo.AssertExpectations(t)
+}
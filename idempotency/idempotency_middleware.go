@@ -0,0 +1,162 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// HeaderKey is the request header carrying the idempotency key
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed marks responses served from a stored key
+	HeaderReplayed = "Idempotent-Replayed"
+	maxKeyLength   = 255
+	// storeTimeout bounds saving or releasing a key, which outlives the request so that a cancelled request
+	// does not leave its key reserved
+	storeTimeout = 5 * time.Second
+)
+
+//Middleware replays the stored response of requests repeated with the same Idempotency-Key
+type Middleware struct {
+	repo   KeysRepositoryInterface
+	logger *logrus.Logger
+}
+
+//Handler wraps the handler of a non idempotent route
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			utils.RespondWithValidationError(w, http.StatusBadRequest, []utils.InvalidParams{{Name: HeaderKey, Reason: "Idempotency-Key should not be longer than 255 characters"}})
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		// keys are scoped to the actor of the request, so two clients may pick the same key
+		key = scopedKey(utils.GetActor(r.Context()), key)
+		record, reserved, err := m.repo.Reserve(r.Context(), key, requestHash(r, body))
+		if err != nil {
+			m.logger.Info("Error occured while reserving the idempotency key")
+			utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while reserving the idempotency key")
+			return
+		}
+		if !reserved {
+			m.replay(w, r, record, body)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		// a panicking handler releases the key like a server error, the panic is passed on to the recoverer
+		defer func() {
+			if rec := recover(); rec != nil {
+				m.release(key)
+				panic(rec)
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+
+		// server errors are not remembered so that the client can retry with the same key
+		if recorder.statusCode >= http.StatusInternalServerError {
+			m.release(key)
+			return
+		}
+		record.Completed = true
+		record.StatusCode = recorder.statusCode
+		record.Header = w.Header().Clone()
+		record.Body = recorder.body.Bytes()
+		m.save(record)
+	})
+}
+
+// save stores the response of the key, a key that could not be stored is released rather than left reserved
+func (m *Middleware) save(record Record) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := m.repo.Save(ctx, record); err != nil {
+		m.logger.Info("Error occured while saving the idempotency key")
+		m.release(record.Key)
+	}
+}
+
+// release frees the key reserved by the request
+func (m *Middleware) release(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := m.repo.Release(ctx, key); err != nil {
+		m.logger.Info("Error occured while releasing the idempotency key")
+	}
+}
+
+// replay answers a request whose key is already taken
+func (m *Middleware) replay(w http.ResponseWriter, r *http.Request, record Record, body []byte) {
+	if record.RequestHash != requestHash(r, body) {
+		m.logger.Info("Idempotency key reused with a different request")
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+		return
+	}
+	if !record.Completed {
+		m.logger.Info("Idempotency key in use")
+		utils.RespondWithError(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+		return
+	}
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// scopedKey is the key stored for the Idempotency-Key of an actor, hashed so that it fits the key column
+func scopedKey(actor, key string) string {
+	h := sha256.New()
+	h.Write([]byte(actor + "\n" + key))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestHash identifies a request by its method, path and body
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes the response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	rec.statusCode = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+//NewMiddleware method
+func NewMiddleware(repo *KeysRepository, log *logrus.Logger) *Middleware {
+	return &Middleware{repo, log}
+}
@@ -0,0 +1,218 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) Reserve(ctx context.Context, key, requestHash string) (Record, bool, error) {
+	args := m.Called(ctx, key, requestHash)
+	return args.Get(0).(Record), args.Bool(1), args.Error(2)
+}
+
+func (m *MockRepo) Save(ctx context.Context, record Record) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockRepo) Release(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// anonymousKey is the stored key of Idempotency-Key key-1 sent without an actor
+var anonymousKey = scopedKey(utils.AnonymousActor, "key-1")
+
+func createdHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/item/1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	})
+}
+
+func newRequest(key, body string) *http.Request {
+	req, _ := http.NewRequest("POST", "/item", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	return req
+}
+
+func TestMiddlewareWithoutKey(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	calls := 0
+	rr := httptest.NewRecorder()
+	m.Handler(createdHandler(&calls)).ServeHTTP(rr, newRequest("", `{}`))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 1, calls)
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareFirstRequest(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{"name":"hotel"}`)
+	hash := requestHash(req, []byte(`{"name":"hotel"}`))
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(Record{Key: anonymousKey, RequestHash: hash}, true, nil)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(r Record) bool {
+		return r.Key == anonymousKey && r.StatusCode == http.StatusCreated && r.Header.Get("Location") == "/item/1" && string(r.Body) == `{"id":1}`
+	})).Return(nil)
+	calls := 0
+	rr := httptest.NewRecorder()
+	m.Handler(createdHandler(&calls)).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 1, calls)
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareReplay(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{"name":"hotel"}`)
+	hash := requestHash(req, []byte(`{"name":"hotel"}`))
+	stored := Record{
+		Key:         anonymousKey,
+		RequestHash: hash,
+		Completed:   true,
+		StatusCode:  http.StatusCreated,
+		Header:      http.Header{"Location": []string{"/item/1"}},
+		Body:        []byte(`{"id":1}`),
+	}
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(stored, false, nil)
+	calls := 0
+	rr := httptest.NewRecorder()
+	m.Handler(createdHandler(&calls)).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "/item/1", rr.Header().Get("Location"))
+	assert.Equal(t, "true", rr.Header().Get(HeaderReplayed))
+	assert.Equal(t, `{"id":1}`, rr.Body.String())
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareDifferentBody(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{"name":"other hotel"}`)
+	hash := requestHash(req, []byte(`{"name":"other hotel"}`))
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(Record{Key: anonymousKey, RequestHash: "another hash", Completed: true}, false, nil)
+	calls := 0
+	rr := httptest.NewRecorder()
+	m.Handler(createdHandler(&calls)).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Equal(t, 0, calls)
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareInProgress(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{}`)
+	hash := requestHash(req, []byte(`{}`))
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(Record{Key: anonymousKey, RequestHash: hash}, false, nil)
+	calls := 0
+	rr := httptest.NewRecorder()
+	m.Handler(createdHandler(&calls)).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestMiddlewareReleasesOnServerError(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{}`)
+	hash := requestHash(req, []byte(`{}`))
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(Record{Key: anonymousKey, RequestHash: hash}, true, nil)
+	repo.On("Release", mock.Anything, anonymousKey).Return(nil)
+	failing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	rr := httptest.NewRecorder()
+	m.Handler(failing).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareReleasesOnPanic(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{}`)
+	hash := requestHash(req, []byte(`{}`))
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(Record{Key: anonymousKey, RequestHash: hash}, true, nil)
+	repo.On("Release", mock.Anything, anonymousKey).Return(nil)
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	})
+	rr := httptest.NewRecorder()
+	assert.Panics(t, func() { m.Handler(panicking).ServeHTTP(rr, req) })
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareKeysScopedToActor(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{}`)
+	req = req.WithContext(utils.WithActor(req.Context(), "alice"))
+	hash := requestHash(req, []byte(`{}`))
+	key := scopedKey("alice", "key-1")
+	assert.NotEqual(t, anonymousKey, key)
+	repo.On("Reserve", req.Context(), key, hash).Return(Record{Key: key, RequestHash: hash}, true, nil)
+	repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	calls := 0
+	rr := httptest.NewRecorder()
+	m.Handler(createdHandler(&calls)).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareSavesAfterCancel(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{}`)
+	ctx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(ctx)
+	hash := requestHash(req, []byte(`{}`))
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(Record{Key: anonymousKey, RequestHash: hash}, true, nil)
+	// the response is stored even though the client went away while it was written
+	repo.On("Save", mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }), mock.Anything).Return(nil)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusCreated)
+	})
+	rr := httptest.NewRecorder()
+	m.Handler(handler).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestMiddlewareReleasesWhenSaveFails(t *testing.T) {
+	repo := new(MockRepo)
+	m := Middleware{repo, logrus.New()}
+	req := newRequest("key-1", `{}`)
+	hash := requestHash(req, []byte(`{}`))
+	repo.On("Reserve", req.Context(), anonymousKey, hash).Return(Record{Key: anonymousKey, RequestHash: hash}, true, nil)
+	repo.On("Save", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+	repo.On("Release", mock.Anything, anonymousKey).Return(nil)
+	calls := 0
+	rr := httptest.NewRecorder()
+	m.Handler(createdHandler(&calls)).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,13 @@
+package idempotency
+
+import "net/http"
+
+// Record is a stored Idempotency-Key along with the response it produced
+type Record struct {
+	Key         string
+	RequestHash string
+	Completed   bool
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+}
@@ -0,0 +1,90 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//KeysRepositoryInterface interface
+type KeysRepositoryInterface interface {
+	Reserve(ctx context.Context, key, requestHash string) (Record, bool, error)
+	Save(ctx context.Context, record Record) error
+	Release(ctx context.Context, key string) error
+}
+
+//KeysRepository struct
+type KeysRepository struct {
+	db *sql.DB
+}
+
+//Reserve claims the key for a request, when the key is already taken the stored record is returned instead
+func (r *KeysRepository) Reserve(ctx context.Context, key, requestHash string) (Record, bool, error) {
+	// keys are only remembered for a day, an expired key can be claimed again
+	expireQry := `DELETE FROM idempotency_key WHERE idempotency_key = $1 AND created_at < NOW() - INTERVAL '24 hours';`
+	if _, err := r.db.ExecContext(ctx, expireQry, key); err != nil {
+		return Record{}, false, fmt.Errorf("Error occured while expiring the key %w", utils.ErrFetchError)
+	}
+
+	reserveQry := `INSERT INTO idempotency_key(idempotency_key, request_hash) VALUES ($1, $2) ON CONFLICT (idempotency_key) DO NOTHING;`
+	result, err := r.db.ExecContext(ctx, reserveQry, key, requestHash)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("Error occured while reserving the key %w", utils.ErrIdempotencyKeyNotSaved)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return Record{}, false, fmt.Errorf("Error occured while reserving the key %w", utils.ErrIdempotencyKeyNotSaved)
+	}
+	if rows == 1 {
+		return Record{Key: key, RequestHash: requestHash}, true, nil
+	}
+
+	record := Record{Key: key}
+	var statusCode sql.NullInt64
+	var header []byte
+	query := `SELECT request_hash, status_code, response_headers, response_body FROM idempotency_key WHERE idempotency_key = $1;`
+	err = r.db.QueryRowContext(ctx, query, key).Scan(&record.RequestHash, &statusCode, &header, &record.Body)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("Error occured while fetching the key %w", utils.ErrFetchError)
+	}
+	if statusCode.Valid {
+		record.Completed = true
+		record.StatusCode = int(statusCode.Int64)
+		if err := json.Unmarshal(header, &record.Header); err != nil {
+			return Record{}, false, fmt.Errorf("Error occured while fetching the key %w", utils.ErrFetchError)
+		}
+	}
+	return record, false, nil
+}
+
+//Save stores the response of the request that reserved the key
+func (r *KeysRepository) Save(ctx context.Context, record Record) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return fmt.Errorf("Error occured while saving the key %w", utils.ErrIdempotencyKeyNotSaved)
+	}
+	query := `UPDATE idempotency_key SET status_code = $2, response_headers = $3, response_body = $4, completed_at = NOW() WHERE idempotency_key = $1;`
+	_, err = r.db.ExecContext(ctx, query, record.Key, record.StatusCode, header, record.Body)
+	if err != nil {
+		return fmt.Errorf("Error occured while saving the key %w", utils.ErrIdempotencyKeyNotSaved)
+	}
+	return nil
+}
+
+//Release frees a reserved key so that the request can be retried
+func (r *KeysRepository) Release(ctx context.Context, key string) error {
+	query := `DELETE FROM idempotency_key WHERE idempotency_key = $1 AND completed_at IS NULL;`
+	_, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("Error occured while releasing the key %w", utils.ErrIdempotencyKeyNotSaved)
+	}
+	return nil
+}
+
+//NewKeysRepository method
+func NewKeysRepository(db *sql.DB) *KeysRepository {
+	return &KeysRepository{db}
+}
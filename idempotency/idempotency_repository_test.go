@@ -0,0 +1,107 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+func TestReserveNewKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM idempotency_key`).WithArgs("key-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO idempotency_key`).WithArgs("key-1", "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+	repo := NewKeysRepository(db)
+	record, reserved, err := repo.Reserve(context.Background(), "key-1", "hash")
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, Record{Key: "key-1", RequestHash: "hash"}, record)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveTakenKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM idempotency_key`).WithArgs("key-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO idempotency_key`).WithArgs("key-1", "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT request_hash`).WithArgs("key-1").WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "response_headers", "response_body"}).
+		AddRow("hash", 201, []byte(`{"Content-Type":["application/json"]}`), []byte(`{"id":1}`)))
+	repo := NewKeysRepository(db)
+	record, reserved, err := repo.Reserve(context.Background(), "key-1", "hash")
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.True(t, record.Completed)
+	assert.Equal(t, 201, record.StatusCode)
+	assert.Equal(t, "application/json", record.Header.Get("Content-Type"))
+	assert.Equal(t, []byte(`{"id":1}`), record.Body)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReserveInProgressKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM idempotency_key`).WithArgs("key-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO idempotency_key`).WithArgs("key-1", "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT request_hash`).WithArgs("key-1").WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "response_headers", "response_body"}).
+		AddRow("hash", nil, nil, nil))
+	repo := NewKeysRepository(db)
+	record, reserved, err := repo.Reserve(context.Background(), "key-1", "hash")
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.False(t, record.Completed)
+}
+
+func TestReserveError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM idempotency_key`).WithArgs("key-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO idempotency_key`).WithArgs("key-1", "hash").WillReturnError(errors.New("error"))
+	repo := NewKeysRepository(db)
+	_, _, err = repo.Reserve(context.Background(), "key-1", "hash")
+	assert.True(t, errors.Is(err, utils.ErrIdempotencyKeyNotSaved))
+}
+
+func TestSave(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	header := http.Header{"Location": []string{"/item/1"}}
+	mock.ExpectExec(`UPDATE idempotency_key`).WithArgs("key-1", 201, []byte(`{"Location":["/item/1"]}`), []byte(`{}`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	repo := NewKeysRepository(db)
+	err = repo.Save(context.Background(), Record{Key: "key-1", StatusCode: 201, Header: header, Body: []byte(`{}`)})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelease(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM idempotency_key WHERE idempotency_key = \$1 AND completed_at IS NULL`).WithArgs("key-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	repo := NewKeysRepository(db)
+	err = repo.Release(context.Background(), "key-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sayooj/trivago/currency"
+	"github.com/sayooj/trivago/geocode"
+	"github.com/sayooj/trivago/item"
+	"github.com/sayooj/trivago/utils"
+)
+
+//importActor is recorded in the item history for items added by the import command
+const importActor = "cli-import"
+
+//runImport adds the items of a CSV or JSON Lines file and prints the report, it returns the exit code
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "csv or jsonl, taken from the file extension when empty")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: import [-format csv|jsonl] <file>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			*format = "csv"
+		case ".jsonl", ".ndjson":
+			*format = "jsonl"
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+	reader, err := item.NewItemReader(file, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	gu := geocode.NewGeocodeUseCase(geocode.NewPostalCodesRepository(server.db))
+	cu := currency.NewExchangeRatesUseCase(currency.NewExchangeRatesRepository(server.db))
+	uc := item.NewItemsUseCase(item.NewItemsRepository(server.db), gu, cu)
+	report, err := uc.ImportItems(utils.WithActor(context.Background(), importActor), reader)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+	fmt.Fprintf(os.Stderr, "%d accepted, %d rejected, %d failed\n", report.Accepted, report.Rejected, report.Failed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package item
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// AuditEntry is a change of an item recorded in its history
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	ItemID    uint64          `json:"item_id"`
+	Action    string          `json:"action"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Changes   []FieldChange   `json:"changes"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// FieldChange is the change of a single field between two snapshots, nested fields are dotted like location.city
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// diffSnapshots lists the fields that differ between two JSON snapshots, sorted by field
+func diffSnapshots(before, after json.RawMessage) ([]FieldChange, error) {
+	beforeFields, err := flattenSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := flattenSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	changes := []FieldChange{}
+	for _, field := range fields {
+		if !reflect.DeepEqual(beforeFields[field], afterFields[field]) {
+			changes = append(changes, FieldChange{Field: field, From: beforeFields[field], To: afterFields[field]})
+		}
+	}
+	return changes, nil
+}
+
+// flattenSnapshot maps every leaf of a JSON object to its dotted path
+func flattenSnapshot(snapshot json.RawMessage) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if len(snapshot) == 0 {
+		return fields, nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(snapshot, &object); err != nil {
+		return nil, err
+	}
+	var flatten func(prefix string, object map[string]interface{})
+	flatten = func(prefix string, object map[string]interface{}) {
+		for name, value := range object {
+			if nested, ok := value.(map[string]interface{}); ok {
+				flatten(prefix+name+".", nested)
+				continue
+			}
+			fields[prefix+name] = value
+		}
+	}
+	flatten("", object)
+	return fields, nil
+}
@@ -0,0 +1,67 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/sayooj/trivago/utils"
+)
+
+// recordAudit appends an entry to the history of an item, inside the transaction making the change
+func recordAudit(ctx context.Context, tx queryer, itemID uint64, action string, before, after interface{}) error {
+	beforeSnapshot, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterSnapshot, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO item_audit(item_id, action, actor, request_id, before, after) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = tx.ExecContext(ctx, query, itemID, action, utils.GetActor(ctx), middleware.GetReqID(ctx), beforeSnapshot, afterSnapshot)
+	return err
+}
+
+// auditSnapshot encodes a snapshot as JSON, nil is stored as NULL
+func auditSnapshot(snapshot interface{}) (interface{}, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+//GetItemHistory returns the history of an item, oldest change first
+func (r *ItemsRepository) GetItemHistory(ctx context.Context, id int) ([]AuditEntry, error) {
+	query := `SELECT id, item_id, action, actor, request_id, before, after, created_at FROM item_audit WHERE item_id = $1 ORDER BY id`
+	rows, err := r.conn(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching the history %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var entry AuditEntry
+		var before, after []byte
+		err := rows.Scan(&entry.ID, &entry.ItemID, &entry.Action, &entry.Actor, &entry.RequestID, &before, &after, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Error occured while fetching the history %w", utils.ErrFetchError)
+		}
+		if before != nil {
+			entry.Before = json.RawMessage(before)
+		}
+		if after != nil {
+			entry.After = json.RawMessage(after)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching the history %w", utils.ErrFetchError)
+	}
+	return entries, nil
+}
@@ -0,0 +1,93 @@
+package item
+
+import (
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+// BatchRequest is a list of item operations. Atomic runs them in one transaction, nothing is stored
+// unless every operation succeeds. Otherwise every operation is tried on its own
+type BatchRequest struct {
+	Atomic     bool             `json:"atomic"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation creates, updates or deletes a single item. Ref is chosen by the client to match the
+// operation with its result, a non zero Version is checked like an If-Match header
+type BatchOperation struct {
+	Ref     string `json:"ref"`
+	Op      string `json:"op"`
+	ID      int    `json:"id,omitempty"`
+	Version uint64 `json:"version,omitempty"`
+	Item    *Item  `json:"item,omitempty"`
+}
+
+// BatchResult has the result of every operation of a BatchRequest, in the same order
+type BatchResult struct {
+	Atomic    bool                   `json:"atomic"`
+	Committed bool                   `json:"committed"`
+	Results   []BatchOperationResult `json:"results"`
+}
+
+// BatchOperationResult is the outcome of one operation, Status is the HTTP status the operation
+// would have had on its own endpoint
+type BatchOperationResult struct {
+	Ref           string                `json:"ref"`
+	Op            string                `json:"op"`
+	Status        int                   `json:"status"`
+	ID            uint64                `json:"id,omitempty"`
+	ETag          string                `json:"etag,omitempty"`
+	Error         string                `json:"error,omitempty"`
+	InvalidParams []utils.InvalidParams `json:"invalid-params,omitempty"`
+	err           error
+}
+
+// Validate checks the size of the batch and that every ref is set and unique
+func (b BatchRequest) Validate() []utils.InvalidParams {
+	invalidParams := []utils.InvalidParams{}
+	if len(b.Operations) == 0 {
+		invalidParams = append(invalidParams, utils.InvalidParams{Name: "operations", Reason: "operations required"})
+	}
+	if len(b.Operations) > batchMaxOperations {
+		invalidParams = append(invalidParams, utils.InvalidParams{Name: "operations", Reason: fmt.Sprintf("operations should be at most %d", batchMaxOperations)})
+	}
+	refs := map[string]bool{}
+	for i, op := range b.Operations {
+		name := fmt.Sprintf("operations[%d].ref", i)
+		if op.Ref == "" {
+			invalidParams = append(invalidParams, utils.InvalidParams{Name: name, Reason: "ref required"})
+			continue
+		}
+		if refs[op.Ref] {
+			invalidParams = append(invalidParams, utils.InvalidParams{Name: name, Reason: "ref should be unique"})
+		}
+		refs[op.Ref] = true
+	}
+	return invalidParams
+}
+
+// Validate checks the operation and its item, create and update need a complete item
+func (o BatchOperation) Validate() []utils.InvalidParams {
+	invalidParams := []utils.InvalidParams{}
+	switch o.Op {
+	case batchOpCreate:
+	case batchOpUpdate, batchOpDelete:
+		if o.ID <= 0 {
+			invalidParams = append(invalidParams, utils.InvalidParams{Name: "id", Reason: "id required"})
+		}
+	default:
+		return append(invalidParams, utils.InvalidParams{Name: "op", Reason: "op should be create, update or delete"})
+	}
+	if o.Op == batchOpDelete {
+		return invalidParams
+	}
+	if o.Item == nil {
+		return append(invalidParams, utils.InvalidParams{Name: "item", Reason: "item required"})
+	}
+	invalidParams = append(invalidParams, o.Item.ValidateRequiredItem()...)
+	if len(invalidParams) == 0 {
+		invalidParams = o.Item.ValidateFields()
+	}
+	return invalidParams
+}
@@ -0,0 +1,138 @@
+package item
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+//BookingsHandler handler for bookings
+type BookingsHandler struct {
+	useCase BookingsUseCaseInterface
+	logger  *logrus.Logger
+}
+
+//GetBooking get booking based on id
+func (h *BookingsHandler) GetBooking(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := strconv.Atoi(chi.URLParam(r, "bookingId"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid booking id")
+		return
+	}
+	booking, err := h.useCase.GetBooking(r.Context(), bookingID)
+	if errors.Is(err, utils.ErrBookingNotFound) {
+		h.logger.Info("Booking not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Booking not found")
+		return
+	}
+	if err != nil {
+		h.logger.Info("Error occured while fetching the booking")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the booking")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, booking)
+}
+
+//GetBookingByCode get booking based on its confirmation code
+func (h *BookingsHandler) GetBookingByCode(w http.ResponseWriter, r *http.Request) {
+	booking, err := h.useCase.GetBookingByCode(r.Context(), chi.URLParam(r, "code"))
+	if errors.Is(err, utils.ErrBookingNotFound) {
+		h.logger.Info("Booking not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Booking not found")
+		return
+	}
+	if err != nil {
+		h.logger.Info("Error occured while fetching the booking")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the booking")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, booking)
+}
+
+//GetItemBookings get the bookings of an item
+func (h *BookingsHandler) GetItemBookings(w http.ResponseWriter, r *http.Request) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	bookings, err := h.useCase.GetItemBookings(r.Context(), itemID)
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if err != nil {
+		h.logger.Info("Error occured while fetching the bookings")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the bookings")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, bookings)
+}
+
+//CancelBooking cancel a booking based on id
+func (h *BookingsHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := strconv.Atoi(chi.URLParam(r, "bookingId"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid booking id")
+		return
+	}
+	booking, err := h.useCase.CancelBooking(r.Context(), bookingID)
+	if err != nil {
+		h.respondWithStatusError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, booking)
+}
+
+//UpdateBookingStatus move a booking to the status in the request body
+func (h *BookingsHandler) UpdateBookingStatus(w http.ResponseWriter, r *http.Request) {
+	var status BookingStatus
+	bookingID, err := strconv.Atoi(chi.URLParam(r, "bookingId"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid booking id")
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&status); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	booking, err := h.useCase.UpdateBookingStatus(r.Context(), bookingID, status.Status)
+	if err != nil {
+		h.respondWithStatusError(w, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, booking)
+}
+
+// respondWithStatusError maps the errors of a booking status change
+func (h *BookingsHandler) respondWithStatusError(w http.ResponseWriter, err error) {
+	if errors.Is(err, utils.ErrInvalidBookingStatus) {
+		h.logger.Info("Invalid booking status")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, []utils.InvalidParams{{Name: "status", Reason: "status should be any of [confirmed, checked_in, completed, cancelled, no_show]"}})
+		return
+	}
+	if errors.Is(err, utils.ErrBookingNotFound) {
+		h.logger.Info("Booking not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Booking not found")
+		return
+	}
+	if errors.Is(err, utils.ErrBookingStatusTransition) {
+		h.logger.Info("Booking status can not be changed")
+		utils.RespondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+	h.logger.Info("Failed to update booking")
+	utils.RespondWithError(w, http.StatusInternalServerError, "Failed to update booking")
+}
+
+//NewBookingsHandler method
+func NewBookingsHandler(useCase *BookingsUseCase, log *logrus.Logger) *BookingsHandler {
+	return &BookingsHandler{useCase, log}
+}
@@ -0,0 +1,157 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+type MockBookingUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBookingUseCase) GetBooking(ctx context.Context, id int) (Booking, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) GetBookingByCode(ctx context.Context, code string) (Booking, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) GetItemBookings(ctx context.Context, itemID int) ([]Booking, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) CancelBooking(ctx context.Context, id int) (Booking, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) UpdateBookingStatus(ctx context.Context, id int, status string) (Booking, error) {
+	args := m.Called(ctx, id, status)
+	return args.Get(0).(Booking), args.Error(1)
+}
+
+func TestGetBookingHandler(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/booking/7", "", "bookingId", "7")
+	uc.On("GetBooking", req.Context(), 7).Return(booking, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.GetBooking).ServeHTTP(rr, req)
+	var res Booking
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, booking, res)
+	uc.AssertExpectations(t)
+}
+
+func TestGetBookingHandlerNotFound(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/booking/7", "", "bookingId", "7")
+	uc.On("GetBooking", req.Context(), 7).Return(Booking{}, utils.ErrBookingNotFound)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.GetBooking).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemBookingsHandler(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/bookings", "", "id", "1")
+	uc.On("GetItemBookings", req.Context(), 1).Return([]Booking{booking}, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.GetItemBookings).ServeHTTP(rr, req)
+	var res []Booking
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, res, 1)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemBookingsHandlerBadRequest(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/bad/bookings", "", "id", "bad")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.GetItemBookings).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCancelBookingHandler(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("DELETE", "/booking/7", "", "bookingId", "7")
+	cancelled := booking
+	cancelled.Status = "cancelled"
+	uc.On("CancelBooking", req.Context(), 7).Return(cancelled, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.CancelBooking).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetBookingByCodeHandler(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/booking/code/ABCD-EF23", "", "code", "ABCD-EF23")
+	uc.On("GetBookingByCode", req.Context(), "ABCD-EF23").Return(booking, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.GetBookingByCode).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestUpdateBookingStatusHandler(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("PUT", "/booking/7/status", `{"status":"checked_in"}`, "bookingId", "7")
+	checkedIn := booking
+	checkedIn.Status = "checked_in"
+	uc.On("UpdateBookingStatus", req.Context(), 7, "checked_in").Return(checkedIn, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.UpdateBookingStatus).ServeHTTP(rr, req)
+	var res Booking
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "checked_in", res.Status)
+	uc.AssertExpectations(t)
+}
+
+func TestUpdateBookingStatusHandlerInvalidStatus(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("PUT", "/booking/7/status", `{"status":"lost"}`, "bookingId", "7")
+	uc.On("UpdateBookingStatus", req.Context(), 7, "lost").Return(Booking{}, utils.ErrInvalidBookingStatus)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.UpdateBookingStatus).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestCancelBookingHandlerConflict(t *testing.T) {
+	uc := new(MockBookingUseCase)
+	bh := BookingsHandler{uc, logrus.New()}
+	req := newRouteRequest("DELETE", "/booking/7", "", "bookingId", "7")
+	uc.On("CancelBooking", req.Context(), 7).Return(Booking{}, utils.ErrBookingStatusTransition)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(bh.CancelBooking).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	uc.AssertExpectations(t)
+}
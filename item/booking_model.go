@@ -0,0 +1,70 @@
+package item
+
+import (
+	"crypto/rand"
+	"math/big"
+	"time"
+)
+
+// Booking struct
+type Booking struct {
+	ID               uint64     `json:"id"`
+	ConfirmationCode string     `json:"confirmation_code"`
+	ItemID           uint64     `json:"item_id"`
+	RoomTypeID       uint64     `json:"room_type_id,omitempty"`
+	PersonName       string     `json:"person_name"`
+	NoOfRooms        uint       `json:"no_of_rooms"`
+	Guests           uint       `json:"guests,omitempty"`
+	CheckIn          string     `json:"check_in"`
+	CheckOut         string     `json:"check_out"`
+	TotalPrice       uint64     `json:"total_price,omitempty"`
+	Currency         string     `json:"currency,omitempty"`
+	Status           string     `json:"status"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	CheckedInAt      *time.Time `json:"checked_in_at,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	NoShowAt         *time.Time `json:"no_show_at,omitempty"`
+}
+
+// BookingStatus is the body of a booking status change
+type BookingStatus struct {
+	Status string `json:"status"`
+}
+
+// CanTransition tells whether a booking in status from can move to status to
+func CanTransition(from, to string) bool {
+	for _, status := range bookingTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBookingStatus tells whether status is one of the booking statuses
+func IsBookingStatus(status string) bool {
+	if status == bookingStatusPending {
+		return true
+	}
+	_, ok := bookingStatusTimestamps[status]
+	return ok
+}
+
+// newConfirmationCode generates a random code formatted like ABCD-EF23
+func newConfirmationCode() (string, error) {
+	code := make([]byte, 0, confirmationCodeLength+1)
+	max := big.NewInt(int64(len(confirmationCodeAlphabet)))
+	for i := 0; i < confirmationCodeLength; i++ {
+		if i == confirmationCodeLength/2 {
+			code = append(code, '-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code = append(code, confirmationCodeAlphabet[n.Int64()])
+	}
+	return string(code), nil
+}
@@ -0,0 +1,44 @@
+package item
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	allowed := [][2]string{
+		{"pending", "confirmed"},
+		{"pending", "cancelled"},
+		{"confirmed", "checked_in"},
+		{"confirmed", "no_show"},
+		{"checked_in", "completed"},
+	}
+	for _, transition := range allowed {
+		if !CanTransition(transition[0], transition[1]) {
+			t.Errorf("Expected %s -> %s to be allowed", transition[0], transition[1])
+		}
+	}
+	denied := [][2]string{
+		{"pending", "checked_in"},
+		{"checked_in", "cancelled"},
+		{"completed", "cancelled"},
+		{"cancelled", "confirmed"},
+		{"no_show", "checked_in"},
+	}
+	for _, transition := range denied {
+		if CanTransition(transition[0], transition[1]) {
+			t.Errorf("Expected %s -> %s to be denied", transition[0], transition[1])
+		}
+	}
+}
+
+func TestNewConfirmationCode(t *testing.T) {
+	re := regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}-[A-HJ-NP-Z2-9]{4}$`)
+	code, err := newConfirmationCode()
+	if err != nil {
+		t.Fatalf("Expected no error got %s", err)
+	}
+	if !re.MatchString(code) {
+		t.Errorf("Expected code like ABCD-EF23 got %s", code)
+	}
+}
@@ -0,0 +1,217 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//BookingsRepositoryInterface interface
+type BookingsRepositoryInterface interface {
+	GetBooking(ctx context.Context, id int) (Booking, error)
+	GetBookingByCode(ctx context.Context, code string) (Booking, error)
+	GetItemBookings(ctx context.Context, itemID int) ([]Booking, error)
+	UpdateBookingStatus(ctx context.Context, id int, status string) error
+}
+
+const bookingSelectQuery = `
+	SELECT
+		id_booking,
+		confirmation_code,
+		COALESCE(item_id, 0),
+		COALESCE(room_type_id, 0),
+		person_name,
+		no_of_rooms,
+		guests,
+		check_in,
+		check_out,
+		COALESCE(total_price, 0),
+		COALESCE(currency, ''),
+		status,
+		created_at,
+		confirmed_at,
+		checked_in_at,
+		completed_at,
+		cancelled_at,
+		no_show_at
+	FROM
+		item_booking
+	`
+
+//BookingsRepository struct
+type BookingsRepository struct {
+	db *sql.DB
+}
+
+//GetBooking gets a booking based on id
+func (r *BookingsRepository) GetBooking(ctx context.Context, id int) (Booking, error) {
+	query := bookingSelectQuery + `WHERE id_booking = $1`
+	booking, err := scanBooking(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Booking{}, fmt.Errorf("Booking not found %w", utils.ErrBookingNotFound)
+		}
+		return Booking{}, fmt.Errorf("Failed to fetch booking %w", utils.ErrFetchError)
+	}
+	return booking, nil
+}
+
+//GetBookingByCode gets a booking based on its confirmation code
+func (r *BookingsRepository) GetBookingByCode(ctx context.Context, code string) (Booking, error) {
+	query := bookingSelectQuery + `WHERE confirmation_code = $1`
+	booking, err := scanBooking(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Booking{}, fmt.Errorf("Booking not found %w", utils.ErrBookingNotFound)
+		}
+		return Booking{}, fmt.Errorf("Failed to fetch booking %w", utils.ErrFetchError)
+	}
+	return booking, nil
+}
+
+//GetItemBookings returns the bookings of an item
+func (r *BookingsRepository) GetItemBookings(ctx context.Context, itemID int) ([]Booking, error) {
+	query := bookingSelectQuery + `WHERE item_id = $1 ORDER BY id_booking`
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching bookings %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	bookings := []Booking{}
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Error occured while fetching bookings %w", utils.ErrFetchError)
+		}
+		bookings = append(bookings, booking)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching bookings %w", utils.ErrFetchError)
+	}
+	return bookings, nil
+}
+
+//UpdateBookingStatus moves a booking to status, a cancelled booking releases its rooms. The move is recorded
+//in the history of the item
+func (r *BookingsRepository) UpdateBookingStatus(ctx context.Context, id int, status string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+
+	booking, err := scanBooking(tx.QueryRowContext(ctx, bookingSelectQuery+`WHERE id_booking = $1 FOR UPDATE`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Booking not found %w", utils.ErrBookingNotFound)
+		}
+		return fmt.Errorf("Error occured while fetching the booking %w", utils.ErrBookingNotUpdated)
+	}
+	if !CanTransition(booking.Status, status) {
+		err = utils.ErrBookingStatusTransition
+		return fmt.Errorf("Booking can not move from %s to %s %w", booking.Status, status, err)
+	}
+
+	if status == bookingStatusCancelled {
+		// the item row is locked the same way BookAccommodation does, so inventory rows are updated in the same order
+		_, err = tx.ExecContext(ctx, `SELECT 1 FROM item WHERE item_id = $1 FOR UPDATE;`, booking.ItemID)
+		if err != nil {
+			return fmt.Errorf("Error occured while locking the item %w", utils.ErrBookingNotUpdated)
+		}
+	}
+
+	statusQry := fmt.Sprintf(`UPDATE item_booking SET status = $2, %s = NOW(), updated_at = NOW() WHERE id_booking = $1;`, bookingStatusTimestamps[status])
+	_, err = tx.ExecContext(ctx, statusQry, id, status)
+	if err != nil {
+		return fmt.Errorf("Error occured while updating the booking %w", utils.ErrBookingNotUpdated)
+	}
+
+	if status == bookingStatusCancelled {
+		// hand the rooms of every night of the stay back to the inventory of the item or of its room type
+		releaseQry := `
+		UPDATE item_inventory SET rooms_booked = rooms_booked - booking.no_of_rooms
+		FROM item_booking AS booking
+		WHERE booking.id_booking = $1
+			AND booking.room_type_id IS NULL
+			AND item_inventory.item_id = booking.item_id
+			AND item_inventory.date >= booking.check_in
+			AND item_inventory.date < booking.check_out;`
+		_, err = tx.ExecContext(ctx, releaseQry, id)
+		if err != nil {
+			return fmt.Errorf("Error occured while updating the inventory %w", utils.ErrBookingNotUpdated)
+		}
+		roomTypeReleaseQry := `
+		UPDATE room_type_inventory SET rooms_booked = rooms_booked - booking.no_of_rooms
+		FROM item_booking AS booking
+		WHERE booking.id_booking = $1
+			AND room_type_inventory.room_type_id = booking.room_type_id
+			AND room_type_inventory.date >= booking.check_in
+			AND room_type_inventory.date < booking.check_out;`
+		_, err = tx.ExecContext(ctx, roomTypeReleaseQry, id)
+		if err != nil {
+			return fmt.Errorf("Error occured while updating the inventory %w", utils.ErrBookingNotUpdated)
+		}
+	}
+
+	// the booking is recorded in the history of its item, bookings of purged items have none
+	if booking.ItemID != 0 {
+		var updated Booking
+		updated, err = scanBooking(tx.QueryRowContext(ctx, bookingSelectQuery+`WHERE id_booking = $1`, id))
+		if err != nil {
+			return fmt.Errorf("Error occured while fetching the booking %w", utils.ErrBookingNotUpdated)
+		}
+		err = recordAudit(ctx, tx, booking.ItemID, auditActionBookingStatus, booking, updated)
+		if err != nil {
+			return fmt.Errorf("Error occured while recording the history %w", utils.ErrBookingNotUpdated)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Error occured while updating the booking %w", utils.ErrBookingNotUpdated)
+	}
+	return nil
+}
+
+// scanBooking scans a row selected with bookingSelectQuery, bookings made
+// before check in and check out were recorded have no dates
+func scanBooking(row rowScanner) (Booking, error) {
+	var b Booking
+	var checkIn, checkOut, confirmedAt, checkedInAt, completedAt, cancelledAt, noShowAt sql.NullTime
+	err := row.Scan(&b.ID, &b.ConfirmationCode, &b.ItemID, &b.RoomTypeID, &b.PersonName, &b.NoOfRooms, &b.Guests, &checkIn, &checkOut, &b.TotalPrice, &b.Currency, &b.Status, &b.CreatedAt, &confirmedAt, &checkedInAt, &completedAt, &cancelledAt, &noShowAt)
+	if err != nil {
+		return Booking{}, err
+	}
+	if checkIn.Valid {
+		b.CheckIn = checkIn.Time.Format(dateLayout)
+	}
+	if checkOut.Valid {
+		b.CheckOut = checkOut.Time.Format(dateLayout)
+	}
+	b.ConfirmedAt = nullTime(confirmedAt)
+	b.CheckedInAt = nullTime(checkedInAt)
+	b.CompletedAt = nullTime(completedAt)
+	b.CancelledAt = nullTime(cancelledAt)
+	b.NoShowAt = nullTime(noShowAt)
+	return b, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+//NewBookingsRepository method
+func NewBookingsRepository(db *sql.DB) *BookingsRepository {
+	return &BookingsRepository{db}
+}
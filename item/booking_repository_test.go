@@ -0,0 +1,166 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var bookingColumns = []string{"id_booking", "confirmation_code", "item_id", "room_type_id", "person_name", "no_of_rooms", "guests", "check_in", "check_out", "total_price", "currency", "status", "created_at", "confirmed_at", "checked_in_at", "completed_at", "cancelled_at", "no_show_at"}
+
+func TestGetBooking(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT`).WithArgs(7).WillReturnRows(sqlmock.NewRows(bookingColumns).AddRow(7, "ABCD-EF23", 1, 0, "SVR", 2, 0, checkIn, checkIn.AddDate(0, 0, 2), 18000, "EUR", "confirmed", time.Now(), time.Now(), nil, nil, nil, nil))
+	repo := NewBookingsRepository(db)
+	resp, err := repo.GetBooking(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), resp.ID)
+	assert.Equal(t, "2030-01-10", resp.CheckIn)
+	assert.Equal(t, "2030-01-12", resp.CheckOut)
+	assert.Equal(t, "ABCD-EF23", resp.ConfirmationCode)
+	assert.NotNil(t, resp.ConfirmedAt)
+	assert.Nil(t, resp.CancelledAt)
+}
+
+func TestGetBookingByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`WHERE confirmation_code = \$1`).WithArgs("ABCD-EF23").WillReturnRows(sqlmock.NewRows(bookingColumns).AddRow(7, "ABCD-EF23", 1, 0, "SVR", 2, 0, nil, nil, 0, "", "pending", time.Now(), nil, nil, nil, nil, nil))
+	repo := NewBookingsRepository(db)
+	resp, err := repo.GetBookingByCode(context.Background(), "ABCD-EF23")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), resp.ID)
+}
+
+func TestGetBookingNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT`).WithArgs(7).WillReturnRows(sqlmock.NewRows(bookingColumns))
+	repo := NewBookingsRepository(db)
+	_, err = repo.GetBooking(context.Background(), 7)
+	assert.True(t, errors.Is(err, utils.ErrBookingNotFound))
+}
+
+func TestGetItemBookings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	cancelledAt := time.Now()
+	mock.ExpectQuery(`WHERE item_id = \$1`).WithArgs(1).WillReturnRows(sqlmock.NewRows(bookingColumns).
+		AddRow(3, "ABCD-EF23", 1, 0, "SVR", 2, 0, nil, nil, 0, "", "confirmed", time.Now(), nil, nil, nil, nil, nil).
+		AddRow(4, "ABCD-EF24", 1, 3, "ABC", 1, 2, time.Now(), time.Now(), 9000, "EUR", "cancelled", time.Now(), nil, nil, nil, cancelledAt, nil))
+	repo := NewBookingsRepository(db)
+	resp, err := repo.GetItemBookings(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, "", resp[0].CheckIn)
+	assert.Equal(t, cancelledAt, *resp[1].CancelledAt)
+	assert.Equal(t, uint64(3), resp[1].RoomTypeID)
+	assert.Equal(t, uint(2), resp[1].Guests)
+}
+
+func TestGetItemBookingsError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT`).WithArgs(1).WillReturnError(errors.New("error"))
+	repo := NewBookingsRepository(db)
+	_, err = repo.GetItemBookings(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrFetchError))
+}
+
+// bookingRow is booking 7 of item 1 in status
+func bookingRow(status string) *sqlmock.Rows {
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	return sqlmock.NewRows(bookingColumns).AddRow(7, "ABCD-EF23", 1, 0, "SVR", 2, 0, checkIn, checkIn.AddDate(0, 0, 2), 18000, "EUR", status, time.Now(), nil, nil, nil, nil, nil)
+}
+
+func TestUpdateBookingStatusCancel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM\s+item_booking\s+WHERE id_booking = \$1 FOR UPDATE`).WithArgs(7).WillReturnRows(bookingRow("confirmed"))
+	mock.ExpectExec(`FROM item WHERE item_id = \$1 FOR UPDATE`).WithArgs(uint64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE item_booking SET status = \$2, cancelled_at = NOW\(\)`).WithArgs(7, "cancelled").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE item_inventory SET rooms_booked = rooms_booked - booking.no_of_rooms`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE room_type_inventory SET rooms_booked = rooms_booked - booking.no_of_rooms`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`WHERE id_booking = \$1$`).WithArgs(7).WillReturnRows(bookingRow("cancelled"))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(uint64(1), "booking_status", utils.AnonymousActor, "", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	repo := NewBookingsRepository(db)
+	err = repo.UpdateBookingStatus(context.Background(), 7, "cancelled")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBookingStatusCheckIn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(7).WillReturnRows(bookingRow("confirmed"))
+	mock.ExpectExec(`UPDATE item_booking SET status = \$2, checked_in_at = NOW\(\)`).WithArgs(7, "checked_in").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`WHERE id_booking = \$1$`).WithArgs(7).WillReturnRows(bookingRow("checked_in"))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(uint64(1), "booking_status", utils.AnonymousActor, "", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	repo := NewBookingsRepository(db)
+	err = repo.UpdateBookingStatus(context.Background(), 7, "checked_in")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBookingStatusInvalidTransition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(7).WillReturnRows(bookingRow("checked_in"))
+	mock.ExpectRollback()
+	repo := NewBookingsRepository(db)
+	err = repo.UpdateBookingStatus(context.Background(), 7, "cancelled")
+	assert.True(t, errors.Is(err, utils.ErrBookingStatusTransition))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBookingStatusError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(7).WillReturnRows(bookingRow("pending"))
+	mock.ExpectExec(`UPDATE item_booking`).WithArgs(7, "confirmed").WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+	repo := NewBookingsRepository(db)
+	err = repo.UpdateBookingStatus(context.Background(), 7, "confirmed")
+	assert.True(t, errors.Is(err, utils.ErrBookingNotUpdated))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
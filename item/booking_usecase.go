@@ -0,0 +1,82 @@
+package item
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//BookingsUseCaseInterface interface
+type BookingsUseCaseInterface interface {
+	GetBooking(ctx context.Context, id int) (Booking, error)
+	GetBookingByCode(ctx context.Context, code string) (Booking, error)
+	GetItemBookings(ctx context.Context, itemID int) ([]Booking, error)
+	CancelBooking(ctx context.Context, id int) (Booking, error)
+	UpdateBookingStatus(ctx context.Context, id int, status string) (Booking, error)
+}
+
+//BookingsUseCase struct
+type BookingsUseCase struct {
+	bookingRepo BookingsRepositoryInterface
+	itemRepo    ItemsRepositoryInterface
+}
+
+//GetBooking gets a booking with id
+func (u *BookingsUseCase) GetBooking(ctx context.Context, id int) (Booking, error) {
+	booking, err := u.bookingRepo.GetBooking(ctx, id)
+	if err != nil {
+		return Booking{}, err
+	}
+	return booking, nil
+}
+
+//GetBookingByCode gets a booking with its confirmation code
+func (u *BookingsUseCase) GetBookingByCode(ctx context.Context, code string) (Booking, error) {
+	booking, err := u.bookingRepo.GetBookingByCode(ctx, strings.ToUpper(code))
+	if err != nil {
+		return Booking{}, err
+	}
+	return booking, nil
+}
+
+//GetItemBookings returns the bookings of an item, a deleted item keeps them until it is purged
+func (u *BookingsUseCase) GetItemBookings(ctx context.Context, itemID int) ([]Booking, error) {
+	bookings, err := u.bookingRepo.GetItemBookings(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if len(bookings) == 0 {
+		exists, err := u.itemRepo.ItemExists(ctx, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		}
+	}
+	return bookings, nil
+}
+
+//CancelBooking cancels a booking and returns it
+func (u *BookingsUseCase) CancelBooking(ctx context.Context, id int) (Booking, error) {
+	return u.UpdateBookingStatus(ctx, id, bookingStatusCancelled)
+}
+
+//UpdateBookingStatus moves a booking to status and returns it
+func (u *BookingsUseCase) UpdateBookingStatus(ctx context.Context, id int, status string) (Booking, error) {
+	if !IsBookingStatus(status) {
+		return Booking{}, fmt.Errorf("Unknown status %s %w", status, utils.ErrInvalidBookingStatus)
+	}
+	err := u.bookingRepo.UpdateBookingStatus(ctx, id, status)
+	if err != nil {
+		return Booking{}, err
+	}
+	return u.bookingRepo.GetBooking(ctx, id)
+}
+
+//NewBookingsUseCase method
+func NewBookingsUseCase(bookingRepo *BookingsRepository, itemRepo *ItemsRepository) *BookingsUseCase {
+	return &BookingsUseCase{bookingRepo, itemRepo}
+}
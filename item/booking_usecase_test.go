@@ -0,0 +1,134 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+var booking = Booking{
+	ID:         7,
+	ItemID:     1,
+	PersonName: "SVR",
+	NoOfRooms:  3,
+	CheckIn:    "2030-01-10",
+	CheckOut:   "2030-01-12",
+	Status:     "confirmed",
+	CreatedAt:  time.Date(2029, 12, 1, 10, 0, 0, 0, time.UTC),
+}
+
+type MockBookingRepo struct {
+	mock.Mock
+}
+
+func (m *MockBookingRepo) GetBooking(ctx context.Context, id int) (Booking, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Booking), args.Error(1)
+}
+
+func (m *MockBookingRepo) GetBookingByCode(ctx context.Context, code string) (Booking, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(Booking), args.Error(1)
+}
+
+func (m *MockBookingRepo) GetItemBookings(ctx context.Context, itemID int) ([]Booking, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]Booking), args.Error(1)
+}
+
+func (m *MockBookingRepo) UpdateBookingStatus(ctx context.Context, id int, status string) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func TestGetBookingSuccess(t *testing.T) {
+	repo := new(MockBookingRepo)
+	repo.On("GetBooking", context.Background(), 7).Return(booking, nil)
+	uc := BookingsUseCase{repo, new(MockRepo)}
+	res, err := uc.GetBooking(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), res.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestGetItemBookingsSuccess(t *testing.T) {
+	repo := new(MockBookingRepo)
+	itemRepo := new(MockRepo)
+	repo.On("GetItemBookings", context.Background(), 1).Return([]Booking{booking}, nil)
+	uc := BookingsUseCase{repo, itemRepo}
+	res, err := uc.GetItemBookings(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	repo.AssertExpectations(t)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestGetItemBookingsItemNotFound(t *testing.T) {
+	repo := new(MockBookingRepo)
+	itemRepo := new(MockRepo)
+	repo.On("GetItemBookings", context.Background(), 1).Return([]Booking{}, nil)
+	itemRepo.On("ItemExists", context.Background(), 1).Return(false, nil)
+	uc := BookingsUseCase{repo, itemRepo}
+	_, err := uc.GetItemBookings(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	repo.AssertExpectations(t)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestGetItemBookingsNoBookings(t *testing.T) {
+	repo := new(MockBookingRepo)
+	itemRepo := new(MockRepo)
+	repo.On("GetItemBookings", context.Background(), 1).Return([]Booking{}, nil)
+	itemRepo.On("ItemExists", context.Background(), 1).Return(true, nil)
+	uc := BookingsUseCase{repo, itemRepo}
+	res, err := uc.GetItemBookings(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestCancelBookingSuccess(t *testing.T) {
+	repo := new(MockBookingRepo)
+	cancelled := booking
+	cancelled.Status = "cancelled"
+	repo.On("UpdateBookingStatus", context.Background(), 7, "cancelled").Return(nil)
+	repo.On("GetBooking", context.Background(), 7).Return(cancelled, nil)
+	uc := BookingsUseCase{repo, new(MockRepo)}
+	res, err := uc.CancelBooking(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", res.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestCancelBookingFail(t *testing.T) {
+	repo := new(MockBookingRepo)
+	repo.On("UpdateBookingStatus", context.Background(), 7, "cancelled").Return(utils.ErrBookingStatusTransition)
+	uc := BookingsUseCase{repo, new(MockRepo)}
+	_, err := uc.CancelBooking(context.Background(), 7)
+	assert.True(t, errors.Is(err, utils.ErrBookingStatusTransition))
+	repo.AssertExpectations(t)
+}
+
+func TestGetBookingByCodeUpperCases(t *testing.T) {
+	repo := new(MockBookingRepo)
+	repo.On("GetBookingByCode", context.Background(), "ABCD-EF23").Return(booking, nil)
+	uc := BookingsUseCase{repo, new(MockRepo)}
+	res, err := uc.GetBookingByCode(context.Background(), "abcd-ef23")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), res.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateBookingStatusUnknownStatus(t *testing.T) {
+	repo := new(MockBookingRepo)
+	uc := BookingsUseCase{repo, new(MockRepo)}
+	_, err := uc.UpdateBookingStatus(context.Background(), 7, "lost")
+	assert.True(t, errors.Is(err, utils.ErrInvalidBookingStatus))
+	repo.AssertExpectations(t)
+}
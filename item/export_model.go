@@ -0,0 +1,107 @@
+package item
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ItemWriter writes the items of an export one at a time
+type ItemWriter interface {
+	Write(item Item) error
+	// Flush writes out anything still buffered
+	Flush() error
+}
+
+// NewItemWriter returns a writer for an export in the format csv, jsonl or ndjson
+func NewItemWriter(w io.Writer, format string) (ItemWriter, error) {
+	switch format {
+	case importFormatCSV:
+		return &csvItemWriter{writer: csv.NewWriter(w)}, nil
+	case importFormatJSONL, exportFormatNDJSON:
+		return &jsonlItemWriter{encoder: json.NewEncoder(w)}, nil
+	}
+	return nil, fmt.Errorf("Unknown export format %s", format)
+}
+
+// ExportContentType returns the content type of an export format
+func ExportContentType(format string) string {
+	if format == importFormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// csvItemWriter writes the columns of an import CSV, with the id in front, so an export can be imported again
+type csvItemWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvItemWriter) Write(item Item) error {
+	if !c.headerWritten {
+		if err := c.writer.Write(csvExportHeader()); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	return c.writer.Write([]string{
+		strconv.FormatUint(item.ID, 10),
+		item.Name,
+		strconv.FormatUint(uint64(item.Rating), 10),
+		item.Category,
+		item.Image,
+		strconv.FormatUint(item.Reputation, 10),
+		strconv.FormatUint(item.Price, 10),
+		strconv.FormatUint(uint64(item.Availability), 10),
+		item.Location.City,
+		item.Location.State,
+		item.Location.Country,
+		item.Location.ZipCode,
+		item.Location.Address,
+		formatCoordinate(item.Location.Latitude),
+		formatCoordinate(item.Location.Longitude),
+		item.Currency,
+	})
+}
+
+// csvExportHeader is the id followed by every import column
+func csvExportHeader() []string {
+	header := append([]string{"id"}, importCSVColumns...)
+	return append(header, importCSVOptionalColumns...)
+}
+
+// formatCoordinate formats a coordinate, "" when there is none
+func formatCoordinate(coordinate *float64) string {
+	if coordinate == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*coordinate, 'f', -1, 64)
+}
+
+func (c *csvItemWriter) Flush() error {
+	if !c.headerWritten {
+		// an empty export still tells its columns
+		if err := c.writer.Write(csvExportHeader()); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// jsonlItemWriter writes one item JSON object per line
+type jsonlItemWriter struct {
+	encoder *json.Encoder
+}
+
+func (j *jsonlItemWriter) Write(item Item) error {
+	return j.encoder.Encode(item)
+}
+
+func (j *jsonlItemWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,49 @@
+package item
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCSVItemWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewItemWriter(&out, "csv")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	lat, lng := 10.5276, 76.2144
+	item := Item{ID: 7, Name: "hotel, abcd", Rating: 4, Price: 1000, Currency: "JPY", Location: Location{City: "tsr", ZipCode: "680001", Latitude: &lat, Longitude: &lng}}
+	if err := writer.Write(item); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	writer.Flush()
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,name,rating") {
+		t.Fatalf("Expected a header and a row got %q", out.String())
+	}
+	if lines[1] != `7,"hotel, abcd",4,,,0,1000,0,tsr,,,680001,,10.5276,76.2144,JPY` {
+		t.Errorf("Unexpected row %q", lines[1])
+	}
+
+	// an export can be imported again
+	reader, _ := NewItemReader(strings.NewReader(out.String()), "csv")
+	imported, invalidParams, err := reader.Read()
+	if err != nil || len(invalidParams) != 0 || imported.Name != item.Name || imported.Location.ZipCode != "680001" || *imported.Location.Longitude != lng || imported.Currency != "JPY" {
+		t.Errorf("Expected the row to be read back got %+v %v %v", imported, invalidParams, err)
+	}
+}
+
+func TestJSONLItemWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer, _ := NewItemWriter(&out, "ndjson")
+	writer.Write(Item{ID: 1})
+	writer.Write(Item{ID: 2})
+	writer.Flush()
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], `{"id":2,`) {
+		t.Errorf("Expected one item per line got %q", out.String())
+	}
+	if _, err := NewItemWriter(&out, "xml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
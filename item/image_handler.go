@@ -0,0 +1,158 @@
+package item
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+//ImagesHandler handler for the image galleries of items
+type ImagesHandler struct {
+	useCase ImagesUseCaseInterface
+	logger  *logrus.Logger
+}
+
+//GetImages get the gallery of an item
+func (h *ImagesHandler) GetImages(w http.ResponseWriter, r *http.Request) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	images, err := h.useCase.GetImages(r.Context(), itemID)
+	if err != nil {
+		h.respondWithImageError(w, err, "Error occured while fetching the images")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, images)
+}
+
+//GetImage get an image of the gallery of an item
+func (h *ImagesHandler) GetImage(w http.ResponseWriter, r *http.Request) {
+	itemID, imageID, ok := imageURLParams(w, r)
+	if !ok {
+		return
+	}
+	image, err := h.useCase.GetImage(r.Context(), itemID, imageID)
+	if err != nil {
+		h.respondWithImageError(w, err, "Error occured while fetching the image")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, image)
+}
+
+//AddImage add an image to the gallery of an item
+func (h *ImagesHandler) AddImage(w http.ResponseWriter, r *http.Request) {
+	var request ImageRequest
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	if !h.decodeImageRequest(w, r, &request) {
+		return
+	}
+	image, err := h.useCase.AddImage(r.Context(), itemID, request)
+	if err != nil {
+		h.respondWithImageError(w, err, "Failed to add image")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/item/%d/images/%d", itemID, image.ID))
+	utils.RespondWithJSON(w, http.StatusCreated, image)
+}
+
+//UpdateImage replace an image of the gallery of an item
+func (h *ImagesHandler) UpdateImage(w http.ResponseWriter, r *http.Request) {
+	var request ImageRequest
+	itemID, imageID, ok := imageURLParams(w, r)
+	if !ok {
+		return
+	}
+	if !h.decodeImageRequest(w, r, &request) {
+		return
+	}
+	image, err := h.useCase.UpdateImage(r.Context(), itemID, imageID, request)
+	if err != nil {
+		h.respondWithImageError(w, err, "Failed to update image")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, image)
+}
+
+//DeleteImage remove an image from the gallery of an item
+func (h *ImagesHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	itemID, imageID, ok := imageURLParams(w, r)
+	if !ok {
+		return
+	}
+	err := h.useCase.DeleteImage(r.Context(), itemID, imageID)
+	if err != nil {
+		h.respondWithImageError(w, err, "Failed to delete image")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+//imageURLParams reads the item and image ids of the path, ok is false when a response has already been written
+func imageURLParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return 0, 0, false
+	}
+	imageID, err := strconv.Atoi(chi.URLParam(r, "imageId"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid image id")
+		return 0, 0, false
+	}
+	return itemID, imageID, true
+}
+
+//decodeImageRequest decodes and validates the body, it is false when a response has already been written
+func (h *ImagesHandler) decodeImageRequest(w http.ResponseWriter, r *http.Request, request *ImageRequest) bool {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(request); err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return false
+	}
+	invalidParams := request.Validate()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return false
+	}
+	return true
+}
+
+//respondWithImageError maps the errors of the gallery, message describes any other error
+func (h *ImagesHandler) respondWithImageError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, utils.ErrImageNotFound) {
+		h.logger.Info("Image not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Image not found")
+		return
+	}
+	if errors.Is(err, utils.ErrPrimaryImageRequired) {
+		h.logger.Info("Primary image can not be unset")
+		utils.RespondWithError(w, http.StatusConflict, utils.ErrPrimaryImageRequired.Error())
+		return
+	}
+	h.logger.Info(message)
+	utils.RespondWithError(w, http.StatusInternalServerError, message)
+}
+
+//NewImagesHandler method
+func NewImagesHandler(useCase *ImagesUseCase, log *logrus.Logger) *ImagesHandler {
+	return &ImagesHandler{useCase, log}
+}
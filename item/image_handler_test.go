@@ -0,0 +1,118 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+type MockImageUseCase struct {
+	mock.Mock
+}
+
+func (m *MockImageUseCase) GetImages(ctx context.Context, itemID int) ([]Image, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]Image), args.Error(1)
+}
+
+func (m *MockImageUseCase) GetImage(ctx context.Context, itemID, imageID int) (Image, error) {
+	args := m.Called(ctx, itemID, imageID)
+	return args.Get(0).(Image), args.Error(1)
+}
+
+func (m *MockImageUseCase) AddImage(ctx context.Context, itemID int, request ImageRequest) (Image, error) {
+	args := m.Called(ctx, itemID, request)
+	return args.Get(0).(Image), args.Error(1)
+}
+
+func (m *MockImageUseCase) UpdateImage(ctx context.Context, itemID, imageID int, request ImageRequest) (Image, error) {
+	args := m.Called(ctx, itemID, imageID, request)
+	return args.Get(0).(Image), args.Error(1)
+}
+
+func (m *MockImageUseCase) DeleteImage(ctx context.Context, itemID, imageID int) error {
+	args := m.Called(ctx, itemID, imageID)
+	return args.Error(0)
+}
+
+func TestGetImagesHandler(t *testing.T) {
+	uc := new(MockImageUseCase)
+	mh := ImagesHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/images", "", "id", "1")
+	uc.On("GetImages", req.Context(), 1).Return([]Image{image}, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(mh.GetImages).ServeHTTP(rr, req)
+	var res []Image
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []Image{image}, res)
+	uc.AssertExpectations(t)
+}
+
+func TestAddImageHandler(t *testing.T) {
+	uc := new(MockImageUseCase)
+	mh := ImagesHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/images", `{"url":"http://sc.com/a.jpg","caption":"Lobby"}`, "id", "1")
+	uc.On("AddImage", req.Context(), 1, ImageRequest{URL: "http://sc.com/a.jpg", Caption: "Lobby"}).Return(image, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(mh.AddImage).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/item/1/images/3", rr.Header().Get("Location"))
+	uc.AssertExpectations(t)
+}
+
+func TestAddImageHandlerInvalid(t *testing.T) {
+	uc := new(MockImageUseCase)
+	mh := ImagesHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/images", `{"url":"not a url","position":-1}`, "id", "1")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(mh.AddImage).ServeHTTP(rr, req)
+	var res utils.ErrorModel
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Len(t, res.InvalidParams, 2)
+	uc.AssertExpectations(t)
+}
+
+func TestUpdateImageHandlerPrimaryRequired(t *testing.T) {
+	uc := new(MockImageUseCase)
+	mh := ImagesHandler{uc, logrus.New()}
+	req := newRouteRequest("PUT", "/item/1/images/3", `{"url":"http://sc.com/a.jpg","primary":false}`, "id", "1", "imageId", "3")
+	primary := false
+	uc.On("UpdateImage", req.Context(), 1, 3, ImageRequest{URL: "http://sc.com/a.jpg", Primary: &primary}).Return(Image{}, utils.ErrPrimaryImageRequired)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(mh.UpdateImage).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestDeleteImageHandlerNotFound(t *testing.T) {
+	uc := new(MockImageUseCase)
+	mh := ImagesHandler{uc, logrus.New()}
+	req := newRouteRequest("DELETE", "/item/1/images/9", "", "id", "1", "imageId", "9")
+	uc.On("DeleteImage", req.Context(), 1, 9).Return(utils.ErrImageNotFound)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(mh.DeleteImage).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetImageHandlerBadRequest(t *testing.T) {
+	uc := new(MockImageUseCase)
+	mh := ImagesHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/images/abc", "", "id", "1", "imageId", "abc")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(mh.GetImage).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
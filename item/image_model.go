@@ -0,0 +1,58 @@
+package item
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//Image is a picture of the gallery of an item, the gallery is ordered by Position from 0. The url of
+//the primary image is the image of the item
+type Image struct {
+	ID        uint64    `json:"id"`
+	ItemID    uint64    `json:"item_id"`
+	URL       string    `json:"url"`
+	Position  int       `json:"position"`
+	Caption   string    `json:"caption"`
+	Width     uint      `json:"width,omitempty"`
+	Height    uint      `json:"height,omitempty"`
+	Primary   bool      `json:"primary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//ImageRequest is the body of adding or replacing an image. A missing position appends the image when
+//it is added and keeps its place when it is replaced, a missing primary keeps the primary image
+type ImageRequest struct {
+	URL      string `json:"url"`
+	Position *int   `json:"position"`
+	Caption  string `json:"caption"`
+	Width    uint   `json:"width"`
+	Height   uint   `json:"height"`
+	Primary  *bool  `json:"primary"`
+}
+
+//Validate validates the image request
+func (i ImageRequest) Validate() []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if i.URL == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "url", Reason: "url required"})
+	} else if u, err := url.Parse(i.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "url", Reason: "url should be a valid url"})
+	}
+	if i.Position != nil && *i.Position < 0 {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "position", Reason: "position should be >= 0"})
+	}
+	if utf8.RuneCountInString(i.Caption) > maxImageCaptionLength {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "caption", Reason: fmt.Sprintf("caption should be at most %d characters", maxImageCaptionLength)})
+	}
+	if i.Width > maxImageDimension {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "width", Reason: fmt.Sprintf("width should be <= %d", maxImageDimension)})
+	}
+	if i.Height > maxImageDimension {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "height", Reason: fmt.Sprintf("height should be <= %d", maxImageDimension)})
+	}
+	return validationErr
+}
@@ -0,0 +1,26 @@
+package item
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageRequestValidate(t *testing.T) {
+	position := 2
+	valid := ImageRequest{URL: "http://sc.com/a.jpg", Position: &position, Caption: "Lobby", Width: 1200, Height: 800}
+	assert.Empty(t, valid.Validate())
+	// captions are counted in characters, not bytes
+	assert.Empty(t, ImageRequest{URL: "http://sc.com/a.jpg", Caption: strings.Repeat("é", maxImageCaptionLength)}.Validate())
+
+	negative := -1
+	invalid := ImageRequest{URL: "sc.com/a.jpg", Position: &negative, Caption: string(make([]byte, maxImageCaptionLength+1)), Width: maxImageDimension + 1}
+	names := []string{}
+	for _, param := range invalid.Validate() {
+		names = append(names, param.Name)
+	}
+	assert.Equal(t, []string{"url", "position", "caption", "width"}, names)
+
+	assert.Equal(t, "url", ImageRequest{}.Validate()[0].Name)
+}
@@ -0,0 +1,314 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//ImagesRepositoryInterface interface
+type ImagesRepositoryInterface interface {
+	GetImages(ctx context.Context, itemID int) ([]Image, error)
+	GetImage(ctx context.Context, itemID, imageID int) (Image, error)
+	AddImage(ctx context.Context, itemID int, image ImageRequest) (Image, error)
+	UpdateImage(ctx context.Context, itemID, imageID int, image ImageRequest) (Image, error)
+	DeleteImage(ctx context.Context, itemID, imageID int) error
+}
+
+const imageSelectQuery = `
+	SELECT
+		id,
+		item_id,
+		url,
+		position,
+		caption,
+		width,
+		height,
+		is_primary,
+		created_at
+	FROM
+		item_image
+	`
+
+//primaryImageUpsert makes $2 the url of the primary image of item $1, an item without images gets it
+//as its first image
+const primaryImageUpsert = `INSERT INTO item_image(item_id, url, position, is_primary) VALUES($1 , $2 , 0 , TRUE)
+	ON CONFLICT (item_id) WHERE is_primary DO UPDATE SET url = EXCLUDED.url;`
+
+//ImagesRepository struct
+type ImagesRepository struct {
+	db *sql.DB
+}
+
+//GetImages returns the gallery of an item
+func (r *ImagesRepository) GetImages(ctx context.Context, itemID int) ([]Image, error) {
+	query := imageSelectQuery + `WHERE item_id = $1 ORDER BY position`
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching images %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	images := []Image{}
+	for rows.Next() {
+		image, err := scanImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Error occured while fetching images %w", utils.ErrFetchError)
+		}
+		images = append(images, image)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching images %w", utils.ErrFetchError)
+	}
+	return images, nil
+}
+
+//GetImage gets an image of the gallery of an item
+func (r *ImagesRepository) GetImage(ctx context.Context, itemID, imageID int) (Image, error) {
+	query := imageSelectQuery + `WHERE item_id = $1 AND id = $2`
+	image, err := scanImage(r.db.QueryRowContext(ctx, query, itemID, imageID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Image{}, fmt.Errorf("Image not found %w", utils.ErrImageNotFound)
+		}
+		return Image{}, fmt.Errorf("Failed to fetch image %w", utils.ErrFetchError)
+	}
+	return image, nil
+}
+
+//AddImage adds an image to the gallery of an item at its position, the images from there on move one
+//place down. The first image of a gallery is its primary image
+func (r *ImagesRepository) AddImage(ctx context.Context, itemID int, request ImageRequest) (Image, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Image{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	err = lockItemRow(ctx, tx, itemID, utils.ErrImageNotSaved)
+	if err != nil {
+		return Image{}, err
+	}
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM item_image WHERE item_id = $1;`, itemID).Scan(&count)
+	if err != nil {
+		return Image{}, fmt.Errorf("Error occured while fetching images %w", utils.ErrImageNotSaved)
+	}
+
+	image := Image{ItemID: uint64(itemID), URL: request.URL, Position: count, Caption: request.Caption, Width: request.Width, Height: request.Height}
+	if request.Position != nil && *request.Position < count {
+		image.Position = *request.Position
+	}
+	image.Primary = count == 0 || (request.Primary != nil && *request.Primary)
+
+	_, err = tx.ExecContext(ctx, `UPDATE item_image SET position = position + 1 WHERE item_id = $1 AND position >= $2;`, itemID, image.Position)
+	if err != nil {
+		return Image{}, fmt.Errorf("Error occured while moving images %w", utils.ErrImageNotSaved)
+	}
+	if image.Primary {
+		_, err = tx.ExecContext(ctx, `UPDATE item_image SET is_primary = FALSE WHERE item_id = $1 AND is_primary;`, itemID)
+		if err != nil {
+			return Image{}, fmt.Errorf("Error occured while updating the primary image %w", utils.ErrImageNotSaved)
+		}
+	}
+	insertQry := `INSERT INTO item_image(item_id, url, position, caption, width, height, is_primary) VALUES($1 , $2 , $3 , $4 , $5 , $6 , $7) RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, insertQry, itemID, image.URL, image.Position, image.Caption, nullDimension(image.Width), nullDimension(image.Height), image.Primary).Scan(&image.ID, &image.CreatedAt)
+	if err != nil {
+		return Image{}, fmt.Errorf("Error occured during insertion %w", utils.ErrImageNotSaved)
+	}
+
+	err = syncItemImage(ctx, tx, itemID)
+	if err != nil {
+		return Image{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return Image{}, fmt.Errorf("Error occured during insertion %w", utils.ErrImageNotSaved)
+	}
+	return image, nil
+}
+
+//UpdateImage replaces an image of the gallery of an item, moving it to its position shifts the images in
+//between by one place. The primary image can only change by making another image primary
+func (r *ImagesRepository) UpdateImage(ctx context.Context, itemID, imageID int, request ImageRequest) (Image, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Image{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	err = lockItemRow(ctx, tx, itemID, utils.ErrImageNotSaved)
+	if err != nil {
+		return Image{}, err
+	}
+	image, err := scanImage(tx.QueryRowContext(ctx, imageSelectQuery+`WHERE item_id = $1 AND id = $2 FOR UPDATE`, itemID, imageID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Image{}, fmt.Errorf("Image not found %w", utils.ErrImageNotFound)
+		}
+		return Image{}, fmt.Errorf("Error occured while fetching the image %w", utils.ErrImageNotSaved)
+	}
+	if request.Primary != nil && !*request.Primary && image.Primary {
+		err = utils.ErrPrimaryImageRequired
+		return Image{}, fmt.Errorf("Image %d is the primary image %w", imageID, err)
+	}
+
+	if request.Position != nil && *request.Position != image.Position {
+		var count int
+		err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM item_image WHERE item_id = $1;`, itemID).Scan(&count)
+		if err != nil {
+			return Image{}, fmt.Errorf("Error occured while fetching images %w", utils.ErrImageNotSaved)
+		}
+		position := *request.Position
+		if position > count-1 {
+			position = count - 1
+		}
+		// the images between the old and the new position close the gap the image leaves and open the one it takes
+		moveQry := `UPDATE item_image SET position = position + CASE WHEN $2::INT < $3::INT THEN 1 ELSE -1 END
+			WHERE item_id = $1 AND id <> $4 AND position BETWEEN LEAST($2, $3) AND GREATEST($2, $3);`
+		_, err = tx.ExecContext(ctx, moveQry, itemID, position, image.Position, imageID)
+		if err != nil {
+			return Image{}, fmt.Errorf("Error occured while moving images %w", utils.ErrImageNotSaved)
+		}
+		image.Position = position
+	}
+	if request.Primary != nil && *request.Primary && !image.Primary {
+		_, err = tx.ExecContext(ctx, `UPDATE item_image SET is_primary = FALSE WHERE item_id = $1 AND is_primary;`, itemID)
+		if err != nil {
+			return Image{}, fmt.Errorf("Error occured while updating the primary image %w", utils.ErrImageNotSaved)
+		}
+		image.Primary = true
+	}
+
+	image.URL, image.Caption, image.Width, image.Height = request.URL, request.Caption, request.Width, request.Height
+	updateQry := `UPDATE item_image SET url = $3, position = $4, caption = $5, width = $6, height = $7, is_primary = $8 WHERE item_id = $1 AND id = $2;`
+	_, err = tx.ExecContext(ctx, updateQry, itemID, imageID, image.URL, image.Position, image.Caption, nullDimension(image.Width), nullDimension(image.Height), image.Primary)
+	if err != nil {
+		return Image{}, fmt.Errorf("Error occured while updating the image %w", utils.ErrImageNotSaved)
+	}
+
+	err = syncItemImage(ctx, tx, itemID)
+	if err != nil {
+		return Image{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return Image{}, fmt.Errorf("Error occured while updating the image %w", utils.ErrImageNotSaved)
+	}
+	return image, nil
+}
+
+//DeleteImage removes an image from the gallery of an item, the images after it move one place up. The
+//first image left becomes primary when the primary image is removed
+func (r *ImagesRepository) DeleteImage(ctx context.Context, itemID, imageID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	err = lockItemRow(ctx, tx, itemID, utils.ErrImageNotSaved)
+	if err != nil {
+		return err
+	}
+	var position int
+	var primary bool
+	err = tx.QueryRowContext(ctx, `DELETE FROM item_image WHERE item_id = $1 AND id = $2 RETURNING position, is_primary;`, itemID, imageID).Scan(&position, &primary)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Image not found %w", utils.ErrImageNotFound)
+		}
+		return fmt.Errorf("Error occured while deleting the image %w", utils.ErrImageNotSaved)
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE item_image SET position = position - 1 WHERE item_id = $1 AND position > $2;`, itemID, position)
+	if err != nil {
+		return fmt.Errorf("Error occured while moving images %w", utils.ErrImageNotSaved)
+	}
+	if primary {
+		promoteQry := `UPDATE item_image SET is_primary = TRUE WHERE id = (SELECT id FROM item_image WHERE item_id = $1 ORDER BY position LIMIT 1);`
+		_, err = tx.ExecContext(ctx, promoteQry, itemID)
+		if err != nil {
+			return fmt.Errorf("Error occured while updating the primary image %w", utils.ErrImageNotSaved)
+		}
+	}
+
+	err = syncItemImage(ctx, tx, itemID)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the image %w", utils.ErrImageNotSaved)
+	}
+	return nil
+}
+
+//lockItemRow locks the item so changes to its gallery or room types are made one at a time, notSaved
+//is the error of a failed lock
+func lockItemRow(ctx context.Context, tx queryer, itemID int, notSaved error) error {
+	var id int
+	err := tx.QueryRowContext(ctx, `SELECT item_id FROM item WHERE item_id = $1 AND deleted_at IS NULL FOR UPDATE;`, itemID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		}
+		return fmt.Errorf("Error occured while locking the item %w", notSaved)
+	}
+	return nil
+}
+
+//syncItemImage copies the url of the primary image to the item and bumps its version, the item has no
+//image once its gallery is empty. The change is recorded in the history of the item
+func syncItemImage(ctx context.Context, tx queryer, itemID int) error {
+	before, err := lockItem(ctx, tx, itemID, "")
+	if err != nil {
+		return fmt.Errorf("Error occured while fetching the item %w", utils.ErrImageNotSaved)
+	}
+	after := before
+	query := `UPDATE item SET image = COALESCE((SELECT url FROM item_image WHERE item_id = $1 AND is_primary), ''), version = version + 1 WHERE item_id = $1 RETURNING image, version;`
+	if err := tx.QueryRowContext(ctx, query, itemID).Scan(&after.Image, &after.Version); err != nil {
+		return fmt.Errorf("Error occured while updating the item %w", utils.ErrImageNotSaved)
+	}
+	if err := recordAudit(ctx, tx, before.ID, auditActionUpdate, before, after); err != nil {
+		return fmt.Errorf("Error occured while recording the history %w", utils.ErrImageNotSaved)
+	}
+	return nil
+}
+
+//scanImage scans a row selected with imageSelectQuery
+func scanImage(row rowScanner) (Image, error) {
+	var i Image
+	var width, height sql.NullInt64
+	err := row.Scan(&i.ID, &i.ItemID, &i.URL, &i.Position, &i.Caption, &width, &height, &i.Primary, &i.CreatedAt)
+	if err != nil {
+		return Image{}, err
+	}
+	i.Width, i.Height = uint(width.Int64), uint(height.Int64)
+	return i, nil
+}
+
+//nullDimension stores an unknown width or height as NULL
+func nullDimension(pixels uint) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(pixels), Valid: pixels > 0}
+}
+
+//NewImagesRepository method
+func NewImagesRepository(db *sql.DB) *ImagesRepository {
+	return &ImagesRepository{db}
+}
@@ -0,0 +1,169 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var imageColumns = []string{"id", "item_id", "url", "position", "caption", "width", "height", "is_primary", "created_at"}
+
+// expectSyncItemImage expects the url of the primary image to be copied to item 1 and recorded in its history
+func expectSyncItemImage(mock sqlmock.Sqlmock, url string) {
+	expectLockItem(mock, 1, 1)
+	mock.ExpectQuery(`UPDATE item SET image = COALESCE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"image", "version"}).AddRow(url, 2))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(uint64(1), "update", utils.AnonymousActor, "", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestGetImages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`FROM\s+item_image\s+WHERE item_id = \$1 ORDER BY position`).WithArgs(1).WillReturnRows(sqlmock.NewRows(imageColumns).
+		AddRow(3, 1, "http://sc.com/a.jpg", 0, "Lobby", 1200, 800, true, time.Now()).
+		AddRow(4, 1, "http://sc.com/b.jpg", 1, "", nil, nil, false, time.Now()))
+	repo := NewImagesRepository(db)
+	images, err := repo.GetImages(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+	assert.True(t, images[0].Primary)
+	assert.Equal(t, uint(1200), images[0].Width)
+	assert.Equal(t, uint(0), images[1].Height)
+}
+
+func TestGetImageNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`WHERE item_id = \$1 AND id = \$2`).WithArgs(1, 9).WillReturnRows(sqlmock.NewRows(imageColumns))
+	repo := NewImagesRepository(db)
+	_, err = repo.GetImage(context.Background(), 1, 9)
+	assert.True(t, errors.Is(err, utils.ErrImageNotFound))
+}
+
+func TestAddImageFirstIsPrimary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item .* FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM item_image`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`UPDATE item_image SET position = position \+ 1`).WithArgs(1, 0).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE item_image SET is_primary = FALSE`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO item_image`).WithArgs(1, "http://sc.com/a.jpg", 0, "Lobby", sqlmock.AnyArg(), sqlmock.AnyArg(), true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+	expectSyncItemImage(mock, "http://sc.com/a.jpg")
+	mock.ExpectCommit()
+	repo := NewImagesRepository(db)
+	position := 4
+	image, err := repo.AddImage(context.Background(), 1, ImageRequest{URL: "http://sc.com/a.jpg", Caption: "Lobby", Position: &position})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), image.ID)
+	assert.Equal(t, 0, image.Position)
+	assert.True(t, image.Primary)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddImageItemNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}))
+	mock.ExpectRollback()
+	repo := NewImagesRepository(db)
+	_, err = repo.AddImage(context.Background(), 1, ImageRequest{URL: "http://sc.com/a.jpg"})
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateImageMovesAndMakesPrimary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`WHERE item_id = \$1 AND id = \$2 FOR UPDATE`).WithArgs(1, 4).WillReturnRows(sqlmock.NewRows(imageColumns).
+		AddRow(4, 1, "http://sc.com/b.jpg", 2, "", nil, nil, false, time.Now()))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM item_image`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec(`UPDATE item_image SET position = position \+ CASE`).WithArgs(1, 0, 2, 4).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE item_image SET is_primary = FALSE`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE item_image SET url = \$3`).WithArgs(1, 4, "http://sc.com/b.jpg", 0, "Pool", sqlmock.AnyArg(), sqlmock.AnyArg(), true).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSyncItemImage(mock, "http://sc.com/a.jpg")
+	mock.ExpectCommit()
+	repo := NewImagesRepository(db)
+	position, primary := 0, true
+	image, err := repo.UpdateImage(context.Background(), 1, 4, ImageRequest{URL: "http://sc.com/b.jpg", Caption: "Pool", Position: &position, Primary: &primary})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, image.Position)
+	assert.True(t, image.Primary)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateImageUnsetPrimary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(1, 3).WillReturnRows(sqlmock.NewRows(imageColumns).
+		AddRow(3, 1, "http://sc.com/a.jpg", 0, "", nil, nil, true, time.Now()))
+	mock.ExpectRollback()
+	repo := NewImagesRepository(db)
+	primary := false
+	_, err = repo.UpdateImage(context.Background(), 1, 3, ImageRequest{URL: "http://sc.com/a.jpg", Primary: &primary})
+	assert.True(t, errors.Is(err, utils.ErrPrimaryImageRequired))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteImagePromotesNext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`DELETE FROM item_image`).WithArgs(1, 3).WillReturnRows(sqlmock.NewRows([]string{"position", "is_primary"}).AddRow(0, true))
+	mock.ExpectExec(`UPDATE item_image SET position = position - 1`).WithArgs(1, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE item_image SET is_primary = TRUE`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectSyncItemImage(mock, "http://sc.com/a.jpg")
+	mock.ExpectCommit()
+	repo := NewImagesRepository(db)
+	err = repo.DeleteImage(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteImageNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`DELETE FROM item_image`).WithArgs(1, 9).WillReturnRows(sqlmock.NewRows([]string{"position", "is_primary"}))
+	mock.ExpectRollback()
+	repo := NewImagesRepository(db)
+	err = repo.DeleteImage(context.Background(), 1, 9)
+	assert.True(t, errors.Is(err, utils.ErrImageNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package item
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//ImagesUseCaseInterface interface
+type ImagesUseCaseInterface interface {
+	GetImages(ctx context.Context, itemID int) ([]Image, error)
+	GetImage(ctx context.Context, itemID, imageID int) (Image, error)
+	AddImage(ctx context.Context, itemID int, image ImageRequest) (Image, error)
+	UpdateImage(ctx context.Context, itemID, imageID int, image ImageRequest) (Image, error)
+	DeleteImage(ctx context.Context, itemID, imageID int) error
+}
+
+//ImagesUseCase struct
+type ImagesUseCase struct {
+	imageRepo ImagesRepositoryInterface
+	itemRepo  ItemsRepositoryInterface
+}
+
+//GetImages returns the gallery of an item
+func (u *ImagesUseCase) GetImages(ctx context.Context, itemID int) ([]Image, error) {
+	_, err := u.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+	}
+	return u.imageRepo.GetImages(ctx, itemID)
+}
+
+//GetImage gets an image of the gallery of an item
+func (u *ImagesUseCase) GetImage(ctx context.Context, itemID, imageID int) (Image, error) {
+	_, err := u.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return Image{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+	}
+	return u.imageRepo.GetImage(ctx, itemID, imageID)
+}
+
+//AddImage adds an image to the gallery of an item
+func (u *ImagesUseCase) AddImage(ctx context.Context, itemID int, image ImageRequest) (Image, error) {
+	return u.imageRepo.AddImage(ctx, itemID, image)
+}
+
+//UpdateImage replaces an image of the gallery of an item
+func (u *ImagesUseCase) UpdateImage(ctx context.Context, itemID, imageID int, image ImageRequest) (Image, error) {
+	return u.imageRepo.UpdateImage(ctx, itemID, imageID, image)
+}
+
+//DeleteImage removes an image from the gallery of an item
+func (u *ImagesUseCase) DeleteImage(ctx context.Context, itemID, imageID int) error {
+	return u.imageRepo.DeleteImage(ctx, itemID, imageID)
+}
+
+//NewImagesUseCase method
+func NewImagesUseCase(imageRepo *ImagesRepository, itemRepo *ItemsRepository) *ImagesUseCase {
+	return &ImagesUseCase{imageRepo, itemRepo}
+}
@@ -0,0 +1,77 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+var image = Image{ID: 3, ItemID: 1, URL: "http://sc.com/a.jpg", Position: 0, Caption: "Lobby", Primary: true}
+
+type MockImageRepo struct {
+	mock.Mock
+}
+
+func (m *MockImageRepo) GetImages(ctx context.Context, itemID int) ([]Image, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]Image), args.Error(1)
+}
+
+func (m *MockImageRepo) GetImage(ctx context.Context, itemID, imageID int) (Image, error) {
+	args := m.Called(ctx, itemID, imageID)
+	return args.Get(0).(Image), args.Error(1)
+}
+
+func (m *MockImageRepo) AddImage(ctx context.Context, itemID int, request ImageRequest) (Image, error) {
+	args := m.Called(ctx, itemID, request)
+	return args.Get(0).(Image), args.Error(1)
+}
+
+func (m *MockImageRepo) UpdateImage(ctx context.Context, itemID, imageID int, request ImageRequest) (Image, error) {
+	args := m.Called(ctx, itemID, imageID, request)
+	return args.Get(0).(Image), args.Error(1)
+}
+
+func (m *MockImageRepo) DeleteImage(ctx context.Context, itemID, imageID int) error {
+	args := m.Called(ctx, itemID, imageID)
+	return args.Error(0)
+}
+
+func TestGetImagesSuccess(t *testing.T) {
+	repo := new(MockImageRepo)
+	itemRepo := new(MockRepo)
+	itemRepo.On("GetItem", context.Background(), 1).Return(item, nil)
+	repo.On("GetImages", context.Background(), 1).Return([]Image{image}, nil)
+	uc := ImagesUseCase{repo, itemRepo}
+	res, err := uc.GetImages(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	repo.AssertExpectations(t)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestGetImagesItemNotFound(t *testing.T) {
+	repo := new(MockImageRepo)
+	itemRepo := new(MockRepo)
+	itemRepo.On("GetItem", context.Background(), 1).Return(Item{}, utils.ErrItemNotFound)
+	uc := ImagesUseCase{repo, itemRepo}
+	_, err := uc.GetImages(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	repo.AssertExpectations(t)
+}
+
+func TestAddImageSuccess(t *testing.T) {
+	repo := new(MockImageRepo)
+	request := ImageRequest{URL: "http://sc.com/a.jpg", Caption: "Lobby"}
+	repo.On("AddImage", context.Background(), 1, request).Return(image, nil)
+	uc := ImagesUseCase{repo, new(MockRepo)}
+	res, err := uc.AddImage(context.Background(), 1, request)
+	assert.NoError(t, err)
+	assert.Equal(t, image, res)
+	repo.AssertExpectations(t)
+}
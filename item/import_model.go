@@ -0,0 +1,187 @@
+package item
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+// ImportReport tells what happened to every row of an import
+type ImportReport struct {
+	Accepted int               `json:"accepted"`
+	Rejected int               `json:"rejected"`
+	Failed   int               `json:"failed"`
+	Rows     []ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult is the outcome of a single row, rows are numbered from 1 without the CSV header
+type ImportRowResult struct {
+	Row           int                   `json:"row"`
+	Status        string                `json:"status"`
+	ID            uint64                `json:"id,omitempty"`
+	InvalidParams []utils.InvalidParams `json:"invalid-params,omitempty"`
+}
+
+// ItemReader reads the items of an import one row at a time
+type ItemReader interface {
+	// Read returns the next row or io.EOF after the last one, invalidParams is set for a row that could not be decoded
+	Read() (item Item, invalidParams []utils.InvalidParams, err error)
+}
+
+// NewItemReader returns a reader for an import in the format csv or jsonl
+func NewItemReader(r io.Reader, format string) (ItemReader, error) {
+	switch format {
+	case importFormatCSV:
+		return newCSVItemReader(r)
+	case importFormatJSONL:
+		return &jsonlItemReader{reader: bufio.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("Unknown import format %s %w", format, utils.ErrInvalidImport)
+}
+
+// ImportFormat maps the content type of an upload to its import format, "" when it is not one
+func ImportFormat(contentType string) string {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	switch strings.ToLower(mediaType) {
+	case "text/csv":
+		return importFormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return importFormatJSONL
+	}
+	return ""
+}
+
+// csvItemReader reads items from CSV with a header row naming the columns, the coordinates columns are optional
+type csvItemReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVItemReader(r io.Reader) (*csvItemReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the CSV header %w", utils.ErrInvalidImport)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	missing := []string{}
+	for _, name := range importCSVColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("CSV header misses %s %w", strings.Join(missing, ", "), utils.ErrInvalidImport)
+	}
+	return &csvItemReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvItemReader) Read() (Item, []utils.InvalidParams, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return Item{}, nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return Item{}, []utils.InvalidParams{{Name: "row", Reason: parseErr.Err.Error()}}, nil
+	}
+	if err != nil {
+		return Item{}, nil, err
+	}
+
+	invalidParams := []utils.InvalidParams{}
+	value := func(name string) string {
+		i, ok := c.columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	number := func(name string) uint64 {
+		v := value(name)
+		if v == "" {
+			return 0
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			invalidParams = append(invalidParams, utils.InvalidParams{Name: name, Reason: name + " should be a positive number"})
+		}
+		return n
+	}
+	coordinate := func(name string) *float64 {
+		v := value(name)
+		if v == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			invalidParams = append(invalidParams, utils.InvalidParams{Name: name, Reason: name + " should be a number"})
+			return nil
+		}
+		return &f
+	}
+	item := Item{
+		Name:         value("name"),
+		Rating:       uint(number("rating")),
+		Category:     value("category"),
+		Image:        value("image"),
+		Reputation:   number("reputation"),
+		Price:        number("price"),
+		Currency:     value("currency"),
+		Availability: uint(number("availability")),
+		Location: Location{
+			City:      value("city"),
+			State:     value("state"),
+			Country:   value("country"),
+			ZipCode:   value("zip_code"),
+			Address:   value("address"),
+			Latitude:  coordinate("latitude"),
+			Longitude: coordinate("longitude"),
+		},
+	}
+	if len(invalidParams) > 0 {
+		return Item{}, invalidParams, nil
+	}
+	return item, nil, nil
+}
+
+// jsonlItemReader reads items from JSON Lines, one item object per line, blank lines are skipped
+type jsonlItemReader struct {
+	reader *bufio.Reader
+}
+
+func (j *jsonlItemReader) Read() (Item, []utils.InvalidParams, error) {
+	for {
+		line, err := j.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return Item{}, nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return Item{}, nil, io.EOF
+			}
+			continue
+		}
+		var item Item
+		if jsonErr := json.Unmarshal(line, &item); jsonErr != nil {
+			return Item{}, []utils.InvalidParams{{Name: "row", Reason: "row should be a JSON object of an item"}}, nil
+		}
+		// the id is generated
+		item.ID = 0
+		return item, nil, nil
+	}
+}
@@ -0,0 +1,64 @@
+package item
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSVItemReader(t *testing.T) {
+	upload := "name,rating,category,image,reputation,price,availability,city,state,country,zip_code,address\n" +
+		"hotel abcdefghijk,5,hotel,http://abc.com/img.jpg,800,1000,10,tsr,kerala,india,680001,\"street 1, tsr\"\n" +
+		"hotel abcdefghijk,five,hotel,http://abc.com/img.jpg,800,1000,10,tsr,kerala,india,680001,street 1\n"
+	reader, err := NewItemReader(strings.NewReader(upload), "csv")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	item, invalidParams, err := reader.Read()
+	if err != nil || len(invalidParams) != 0 {
+		t.Fatalf("Expected a valid row got %v %v", err, invalidParams)
+	}
+	if item.Price != 1000 || item.Location.Address != "street 1, tsr" {
+		t.Errorf("Expected the row to be decoded got %+v", item)
+	}
+	_, invalidParams, err = reader.Read()
+	if err != nil || len(invalidParams) != 1 || invalidParams[0].Name != "rating" {
+		t.Errorf("Expected rating to be invalid got %v %v", err, invalidParams)
+	}
+	if _, _, err = reader.Read(); err != io.EOF {
+		t.Errorf("Expected io.EOF got %v", err)
+	}
+}
+
+func TestCSVItemReaderMissingColumns(t *testing.T) {
+	if _, err := NewItemReader(strings.NewReader("name,rating\n"), "csv"); err == nil {
+		t.Errorf("Expected an error for a header without all the columns")
+	}
+}
+
+func TestJSONLItemReader(t *testing.T) {
+	upload := `{"id": 9, "name": "hotel abcdefghijk", "price": 1000, "location": {"city": "tsr"}}` + "\n\n" + `{"name": ` + "\n" + `{"name": "last"}`
+	reader, err := NewItemReader(strings.NewReader(upload), "jsonl")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	item, invalidParams, err := reader.Read()
+	if err != nil || len(invalidParams) != 0 || item.ID != 0 || item.Location.City != "tsr" {
+		t.Errorf("Expected the first line to be decoded without id got %+v %v %v", item, invalidParams, err)
+	}
+	if _, invalidParams, _ = reader.Read(); len(invalidParams) != 1 {
+		t.Errorf("Expected the broken line to be invalid got %v", invalidParams)
+	}
+	if item, _, _ = reader.Read(); item.Name != "last" {
+		t.Errorf("Expected the last line without a newline to be read got %+v", item)
+	}
+	if _, _, err = reader.Read(); err != io.EOF {
+		t.Errorf("Expected io.EOF got %v", err)
+	}
+}
+
+func TestImportFormat(t *testing.T) {
+	if ImportFormat("text/csv; charset=utf-8") != "csv" || ImportFormat("application/x-ndjson") != "jsonl" || ImportFormat("application/json") != "" {
+		t.Errorf("Unexpected import format")
+	}
+}
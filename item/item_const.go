@@ -1,6 +1,225 @@
 package item
 
+import (
+	"regexp"
+	"time"
+)
+
 var (
 	invalidHotelNames = []string{"Free", "Offer", "Book", "Website"}
 	invalidCategory   = []string{"hotel", "alternative", "hostel", "lodge", "resort", "guest-house"}
 )
+
+const (
+	defaultItemsLimit = 20
+	maxItemsLimit     = 100
+	// dateLayout is the format of booking dates
+	dateLayout    = "2006-01-02"
+	maxStayNights = 30
+)
+
+// sortableColumns maps the sort keys accepted by GET /item to item columns
+var sortableColumns = map[string]string{
+	"id":           "item.item_id",
+	"name":         "item.name",
+	"rating":       "item.rating",
+	"reputation":   "item.reputation",
+	"price":        "item.price",
+	"availability": "item.availability",
+}
+
+// reputationBadgeRanges maps a badge to the reputation condition it stands for
+var reputationBadgeRanges = map[string]string{
+	"red":    "item.reputation <= 500",
+	"yellow": "item.reputation > 500 AND item.reputation <= 799",
+	"green":  "item.reputation > 799",
+}
+
+const (
+	bookingStatusPending   = "pending"
+	bookingStatusConfirmed = "confirmed"
+	bookingStatusCheckedIn = "checked_in"
+	bookingStatusCompleted = "completed"
+	bookingStatusCancelled = "cancelled"
+	bookingStatusNoShow    = "no_show"
+)
+
+// activeBookingStatuses are the statuses of bookings that still hold rooms or a guest
+var activeBookingStatuses = []string{bookingStatusPending, bookingStatusConfirmed, bookingStatusCheckedIn}
+
+// bookingTransitions lists the statuses a booking can move to from each status
+var bookingTransitions = map[string][]string{
+	bookingStatusPending:   {bookingStatusConfirmed, bookingStatusCancelled},
+	bookingStatusConfirmed: {bookingStatusCheckedIn, bookingStatusCancelled, bookingStatusNoShow},
+	bookingStatusCheckedIn: {bookingStatusCompleted},
+}
+
+// bookingStatusTimestamps maps a status to the item_booking column recording when it was reached
+var bookingStatusTimestamps = map[string]string{
+	bookingStatusConfirmed: "confirmed_at",
+	bookingStatusCheckedIn: "checked_in_at",
+	bookingStatusCompleted: "completed_at",
+	bookingStatusCancelled: "cancelled_at",
+	bookingStatusNoShow:    "no_show_at",
+}
+
+const (
+	// confirmationCodeAlphabet leaves out characters that are easily misread, like 0/O and 1/I
+	confirmationCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	confirmationCodeLength   = 8
+	confirmationCodeAttempts = 5
+)
+
+// mergePatchMediaType is the media type of a JSON merge patch document
+const mergePatchMediaType = "application/merge-patch+json"
+
+// actions recorded in the item history
+const (
+	auditActionCreate  = "create"
+	auditActionUpdate  = "update"
+	auditActionDelete  = "delete"
+	auditActionRestore = "restore"
+	auditActionPurge   = "purge"
+	auditActionBook    = "book"
+	// auditActionBookingStatus records a booking moving to another status, its snapshots are the booking
+	auditActionBookingStatus = "booking_status"
+	// auditActionReview records a review changing the reputation of the item
+	auditActionReview = "review"
+)
+
+const (
+	importFormatCSV   = "csv"
+	importFormatJSONL = "jsonl"
+	// importBatchSize is the number of rows stored in one transaction
+	importBatchSize = 100
+)
+
+// importCSVColumns are the columns an import CSV header should have
+var importCSVColumns = []string{"name", "rating", "category", "image", "reputation", "price", "availability", "city", "state", "country", "zip_code", "address"}
+
+// importCSVOptionalColumns are the columns an import CSV header may have
+var importCSVOptionalColumns = []string{"latitude", "longitude", "currency"}
+
+// statuses of an import row
+const (
+	importRowAccepted = "accepted"
+	importRowRejected = "rejected"
+	importRowFailed   = "failed"
+)
+
+// exportFormatNDJSON is another name of the jsonl format
+const exportFormatNDJSON = "ndjson"
+
+// operations of a batch
+const (
+	batchOpCreate = "create"
+	batchOpUpdate = "update"
+	batchOpDelete = "delete"
+	// batchMaxOperations is the number of operations a single batch may have
+	batchMaxOperations = 1000
+)
+
+const (
+	// amenitiesMatchAll and amenitiesMatchAny are the values of the amenities_match filter
+	amenitiesMatchAll = "all"
+	amenitiesMatchAny = "any"
+)
+
+const (
+	// maxImageCaptionLength is the length of the item_image caption column
+	maxImageCaptionLength = 250
+	// maxImageDimension is the widest and tallest image in pixels
+	maxImageDimension = 20000
+)
+
+const (
+	// maxRoomTypeNameLength is the length of the room_type name column
+	maxRoomTypeNameLength = 50
+	// maxRoomTypeOccupancy is the most guests a room of a room type can take
+	maxRoomTypeOccupancy = 50
+)
+
+// kinds of pricing rules
+const (
+	pricingRuleNightly      = "nightly"
+	pricingRuleStayDiscount = "stay_discount"
+	// maxPricingRuleNameLength is the length of the pricing_rule name column
+	maxPricingRuleNameLength = 100
+)
+
+const (
+	// reviews are scored from minReviewScore to maxReviewScore, which are a reputation of 0 and 1000
+	minReviewScore = 1
+	maxReviewScore = 10
+	// maxReviewCommentLength is the longest comment a review may have
+	maxReviewCommentLength = 2000
+	// reviewHalfLife is the age difference at which a review weighs half as much as a newer one
+	reviewHalfLife      = 180 * 24 * time.Hour
+	defaultReviewsLimit = 20
+	maxReviewsLimit     = 100
+)
+
+// weekdayNames maps the weekdays of a nightly pricing rule to the weekday of a night
+var weekdayNames = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+const (
+	// maxSearchQueryLength is the longest text GET /item/search accepts
+	maxSearchQueryLength = 200
+	// searchConfig is the text search configuration of the item search_vector column
+	searchConfig = "simple"
+)
+
+// optionalPatchMembers are the item members a merge patch may remove with null
+var optionalPatchMembers = map[string]bool{"location.latitude": true, "location.longitude": true}
+
+const (
+	// earthRadiusKm is the mean radius of the earth used for the haversine distance
+	earthRadiusKm         = 6371.0
+	defaultNearbyRadiusKm = 10.0
+	maxNearbyRadiusKm     = 500.0
+)
+
+// postalCodeRules maps ISO 3166-1 alpha-2 country codes to the format of their postal codes, zip codes
+// are matched in upper case
+var postalCodeRules = map[string]postalCodeRule{
+	"AR": {regexp.MustCompile(`^[A-Z]?\d{4}([A-Z]{3})?$`), "C1425DKF or 1425"},
+	"AT": {regexp.MustCompile(`^\d{4}$`), "1010"},
+	"AU": {regexp.MustCompile(`^\d{4}$`), "2000"},
+	"BE": {regexp.MustCompile(`^\d{4}$`), "1000"},
+	"BR": {regexp.MustCompile(`^\d{5}-?\d{3}$`), "01310-100"},
+	"CA": {regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d$`), "K1A 0B1"},
+	"CH": {regexp.MustCompile(`^\d{4}$`), "8001"},
+	"CZ": {regexp.MustCompile(`^\d{3} ?\d{2}$`), "110 00"},
+	"DE": {regexp.MustCompile(`^\d{5}$`), "01067"},
+	"DK": {regexp.MustCompile(`^\d{4}$`), "1050"},
+	"ES": {regexp.MustCompile(`^\d{5}$`), "08001"},
+	"FI": {regexp.MustCompile(`^\d{5}$`), "00100"},
+	"FR": {regexp.MustCompile(`^\d{5}$`), "75001"},
+	"GB": {regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`), "SW1A 1AA"},
+	"IE": {regexp.MustCompile(`^[A-Z]\d[\dW] ?[\dA-Z]{4}$`), "D02 X285"},
+	"IN": {regexp.MustCompile(`^[1-9]\d{5}$`), "680001"},
+	"IT": {regexp.MustCompile(`^\d{5}$`), "00118"},
+	"JP": {regexp.MustCompile(`^\d{3}-?\d{4}$`), "100-0001"},
+	"MX": {regexp.MustCompile(`^\d{5}$`), "06000"},
+	"NL": {regexp.MustCompile(`^[1-9]\d{3} ?[A-Z]{2}$`), "1012 AB"},
+	"NO": {regexp.MustCompile(`^\d{4}$`), "0150"},
+	"NZ": {regexp.MustCompile(`^\d{4}$`), "6011"},
+	"PL": {regexp.MustCompile(`^\d{2}-\d{3}$`), "00-001"},
+	"PT": {regexp.MustCompile(`^\d{4}-\d{3}$`), "1000-001"},
+	"SE": {regexp.MustCompile(`^\d{3} ?\d{2}$`), "111 22"},
+	"TH": {regexp.MustCompile(`^\d{5}$`), "10200"},
+	"TR": {regexp.MustCompile(`^\d{5}$`), "34000"},
+	"US": {regexp.MustCompile(`^\d{5}(-\d{4})?$`), "02134 or 02134-1234"},
+	"ZA": {regexp.MustCompile(`^\d{4}$`), "8001"},
+}
+
+// defaultPostalCodeRule is the format of the countries without a rule
+var defaultPostalCodeRule = postalCodeRule{regexp.MustCompile(`^[A-Z\d][A-Z\d -]{0,8}[A-Z\d]$`), "2 to 10 letters, digits, spaces or hyphens"}
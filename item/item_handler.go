@@ -3,6 +3,9 @@ package item
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -17,19 +20,107 @@ type ItemsHandler struct {
 	logger  *logrus.Logger
 }
 
-//GetItems get all items
+//GetItems get a page of items, filtered and sorted by the query parameters. ?currency=USD adds the converted
+//price of every item
 func (h *ItemsHandler) GetItems(w http.ResponseWriter, r *http.Request) {
-	products, err := h.useCase.GetItems(r.Context())
+	filter, invalidParams := NewItemFilter(r.URL.Query())
+	to, currencyParams := NewPriceCurrency(r.URL.Query())
+	invalidParams = append(invalidParams, currencyParams...)
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid query parameters")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	products, err := h.useCase.GetItems(r.Context(), filter)
 	if errors.Is(err, utils.ErrFetchError) {
 		h.logger.Info("An error occured while fetching products")
 		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while fetching products")
 		return
 	}
+	if to != "" && !h.convertPrices(w, r, products.Items, to) {
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, products)
 
 }
 
-//GetItem get product based on id
+//SearchItems search the items by name and location, the filters of GetItems narrow the search
+func (h *ItemsHandler) SearchItems(w http.ResponseWriter, r *http.Request) {
+	search, invalidParams := NewItemSearch(r.URL.Query())
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid query parameters")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	results, err := h.useCase.SearchItems(r.Context(), search)
+	if err != nil {
+		h.logger.Info("An error occured while searching products")
+		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while searching products")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, results)
+}
+
+//NearbyItems get the items within radius_km of lat, lng nearest first, the filters of GetItems narrow them
+func (h *ItemsHandler) NearbyItems(w http.ResponseWriter, r *http.Request) {
+	search, invalidParams := NewNearbySearch(r.URL.Query())
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid query parameters")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	results, err := h.useCase.NearbyItems(r.Context(), search)
+	if err != nil {
+		h.logger.Info("An error occured while fetching nearby products")
+		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while fetching nearby products")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, results)
+}
+
+//ExportItems stream every item matching the filters of GetItems as csv, jsonl or ndjson, paging is ignored
+func (h *ItemsHandler) ExportItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, invalidParams := NewItemFilter(query)
+	format := query.Get("format")
+	if format == "" {
+		format = importFormatJSONL
+	}
+	out := &startedWriter{ResponseWriter: w}
+	writer, err := NewItemWriter(out, format)
+	if err != nil {
+		invalidParams = append(invalidParams, utils.InvalidParams{Name: "format", Reason: "format should be csv, jsonl or ndjson"})
+	}
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid query parameters")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	w.Header().Set("Content-Type", ExportContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="items.%s"`, format))
+	err = h.useCase.ExportItems(r.Context(), filter, writer)
+	if err != nil {
+		h.logger.Info("An error occured while exporting products")
+		if !out.started {
+			utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while exporting products")
+		}
+		// the status has been sent, the client sees a cut off body
+		return
+	}
+}
+
+// startedWriter tells whether anything has been written to the response
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (s *startedWriter) Write(b []byte) (int, error) {
+	s.started = true
+	return s.ResponseWriter.Write(b)
+}
+
+//GetItem get product based on id, ?currency=USD adds its converted price
 func (h *ItemsHandler) GetItem(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	itemID, err := strconv.Atoi(id)
@@ -37,6 +128,12 @@ func (h *ItemsHandler) GetItem(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
 		return
 	}
+	to, invalidParams := NewPriceCurrency(r.URL.Query())
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid query parameters")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
 	product, err := h.useCase.GetItem(r.Context(), itemID)
 	if errors.Is(err, utils.ErrFetchError) {
 		h.logger.Info("Error occured while fetching the item")
@@ -48,9 +145,41 @@ func (h *ItemsHandler) GetItem(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
 		return
 	}
+	if to != "" {
+		// the converted price moves with the exchange rates while the version stays, so it is never not modified
+		w.Header().Set("ETag", product.ConvertedETag(to))
+		items := []Item{product}
+		if !h.convertPrices(w, r, items, to) {
+			return
+		}
+		utils.RespondWithJSON(w, http.StatusOK, items[0])
+		return
+	}
+	w.Header().Set("ETag", product.ETag())
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && matchETag(ifNoneMatch, product.ETag(), true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, product)
 }
 
+// convertPrices converts the prices of the items to the currency to, it is false when a response has already
+// been written
+func (h *ItemsHandler) convertPrices(w http.ResponseWriter, r *http.Request, items []Item, to string) bool {
+	err := h.useCase.ConvertPrices(r.Context(), items, to)
+	if errors.Is(err, utils.ErrExchangeRateNotFound) {
+		h.logger.Info(err.Error())
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, err.Error())
+		return false
+	}
+	if err != nil {
+		h.logger.Info("An error occured while converting the prices")
+		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while converting the prices")
+		return false
+	}
+	return true
+}
+
 //AddItem add a item
 func (h *ItemsHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 	var item Item
@@ -72,16 +201,111 @@ func (h *ItemsHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
 		return
 	}
-	err := h.useCase.AddItem(r.Context(), item)
-	if errors.Is(err, utils.ErrItemNotAdded) {
+	created, err := h.useCase.AddItem(r.Context(), item)
+	if errors.Is(err, utils.ErrInvalidLocation) {
+		h.logger.Info("Invalid location")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidLocationParams())
+		return
+	}
+	if errors.Is(err, utils.ErrUnknownAmenity) {
+		h.logger.Info("Unknown amenities")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidAmenitiesParams())
+		return
+	}
+	if err != nil {
 		h.logger.Info("An error occured while adding item to db")
 		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while adding item to db")
 		return
 	}
-	utils.RespondWithJSON(w, http.StatusCreated, nil)
+	w.Header().Set("Location", fmt.Sprintf("/item/%d", created.ID))
+	utils.RespondWithJSON(w, http.StatusCreated, created)
+}
+
+//ImportItems add the items of a CSV or JSON Lines upload, the format comes from the format
+//query parameter or the Content-Type. The report lists the outcome of every row
+func (h *ItemsHandler) ImportItems(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ImportFormat(r.Header.Get("Content-Type"))
+	}
+	if format != importFormatCSV && format != importFormatJSONL {
+		h.logger.Info("Unsupported import format")
+		utils.RespondWithError(w, http.StatusUnsupportedMediaType, "Upload should be text/csv or application/x-ndjson")
+		return
+	}
+	reader, err := NewItemReader(r.Body, format)
+	if err != nil {
+		h.logger.Info("Invalid import file")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, []utils.InvalidParams{{Name: "file", Reason: err.Error()}})
+		return
+	}
+	report, err := h.useCase.ImportItems(r.Context(), reader)
+	if errors.Is(err, utils.ErrInvalidImport) {
+		h.logger.Info("Import stopped, the upload could not be read")
+		utils.RespondWithJSON(w, http.StatusBadRequest, report)
+		return
+	}
+	if err != nil {
+		h.logger.Info("Import stopped, an error occured while adding items to db")
+		utils.RespondWithJSON(w, http.StatusInternalServerError, report)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, report)
 }
 
-//UpdateItem update a item based on id
+//BatchItems run a list of create, update and delete operations, in one transaction when the batch
+//is atomic. The response has the status of every operation
+func (h *ItemsHandler) BatchItems(w http.ResponseWriter, r *http.Request) {
+	var batch BatchRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&batch); err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	invalidParams := batch.Validate()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	result, err := h.useCase.Batch(r.Context(), batch)
+	if err != nil {
+		h.logger.Info("An error occured while running the batch")
+		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while running the batch")
+		return
+	}
+	for i := range result.Results {
+		result.Results[i].Status, result.Results[i].Error = batchOperationStatus(result.Results[i])
+	}
+	utils.RespondWithJSON(w, http.StatusOK, result)
+}
+
+// batchOperationStatus maps the outcome of a batch operation to the status and message of its own endpoint
+func batchOperationStatus(result BatchOperationResult) (int, string) {
+	err := result.err
+	switch {
+	case err == nil && result.Op == batchOpCreate:
+		return http.StatusCreated, ""
+	case err == nil:
+		return http.StatusOK, ""
+	case errors.Is(err, utils.ErrInvalidBatchOperation):
+		return http.StatusBadRequest, "Invalid operation"
+	case errors.Is(err, utils.ErrInvalidLocation):
+		return http.StatusBadRequest, utils.ErrInvalidLocation.Error()
+	case errors.Is(err, utils.ErrUnknownAmenity):
+		return http.StatusBadRequest, utils.ErrUnknownAmenity.Error()
+	case errors.Is(err, utils.ErrItemNotFound):
+		return http.StatusNotFound, "Item not found"
+	case errors.Is(err, utils.ErrItemVersionMismatch):
+		return http.StatusPreconditionFailed, "Item has been modified"
+	case errors.Is(err, utils.ErrBatchRolledBack):
+		return http.StatusFailedDependency, utils.ErrBatchRolledBack.Error()
+	}
+	return http.StatusInternalServerError, "An error occured while running the operation"
+}
+
+//UpdateItem replace a item based on id, the body should be a complete item
 func (h *ItemsHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	var item Item
 	id := chi.URLParam(r, "id")
@@ -91,7 +315,13 @@ func (h *ItemsHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
-	invalidParams := item.ValidateFields()
+	invalidParams := item.ValidateRequiredItem()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	invalidParams = item.ValidateFields()
 	if len(invalidParams) > 0 {
 		h.logger.Info("Invalid request payload")
 		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
@@ -103,8 +333,23 @@ func (h *ItemsHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
 		return
 	}
+	version, ok := h.ifMatchVersion(w, r, itemID)
+	if !ok {
+		return
+	}
 	item.ID = uint64(itemID)
-	err = h.useCase.UpdateItem(r.Context(), item)
+	item.Version = version
+	updated, err := h.useCase.UpdateItem(r.Context(), item)
+	if errors.Is(err, utils.ErrInvalidLocation) {
+		h.logger.Info("Invalid location")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidLocationParams())
+		return
+	}
+	if errors.Is(err, utils.ErrUnknownAmenity) {
+		h.logger.Info("Unknown amenities")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidAmenitiesParams())
+		return
+	}
 	if errors.Is(err, utils.ErrItemNotUpdated) {
 		h.logger.Info("An error occured while updating the product")
 		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while updating the product")
@@ -115,7 +360,106 @@ func (h *ItemsHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
 		return
 	}
-	utils.RespondWithJSON(w, http.StatusOK, nil)
+	if errors.Is(err, utils.ErrItemVersionMismatch) {
+		h.logger.Info("Item has been modified")
+		utils.RespondWithError(w, http.StatusPreconditionFailed, "Item has been modified")
+		return
+	}
+	if err != nil {
+		h.logger.Info("An error occured while updating the product")
+		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while updating the product")
+		return
+	}
+	w.Header().Set("ETag", updated.ETag())
+	utils.RespondWithJSON(w, http.StatusOK, updated)
+}
+
+//PatchItem apply a JSON merge patch (RFC 7396) to a item based on id
+func (h *ItemsHandler) PatchItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		h.logger.Info("Invalid id")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchMediaType && mediaType != "application/json") {
+			h.logger.Info("Unsupported media type")
+			utils.RespondWithError(w, http.StatusUnsupportedMediaType, "Content-Type should be "+mergePatchMediaType)
+			return
+		}
+	}
+	patch, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	current, err := h.useCase.GetItem(r.Context(), itemID)
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if err != nil {
+		h.logger.Info("Error occured while fetching the item")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the item")
+		return
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && !matchETag(ifMatch, current.ETag(), false) {
+		h.logger.Info("Item has been modified")
+		utils.RespondWithError(w, http.StatusPreconditionFailed, "Item has been modified")
+		return
+	}
+	// the patch is applied to the version read above, so a concurrent update fails instead of being overwritten
+	item, invalidParams, err := current.ApplyMergePatch(patch)
+	if err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	invalidParams = item.ValidateFields()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	updated, err := h.useCase.UpdateItem(r.Context(), item)
+	if errors.Is(err, utils.ErrInvalidLocation) {
+		h.logger.Info("Invalid location")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidLocationParams())
+		return
+	}
+	if errors.Is(err, utils.ErrUnknownAmenity) {
+		h.logger.Info("Unknown amenities")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidAmenitiesParams())
+		return
+	}
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, utils.ErrItemVersionMismatch) {
+		h.logger.Info("Item has been modified")
+		utils.RespondWithError(w, http.StatusPreconditionFailed, "Item has been modified")
+		return
+	}
+	if err != nil {
+		h.logger.Info("An error occured while updating the product")
+		utils.RespondWithError(w, http.StatusInternalServerError, "An error occured while updating the product")
+		return
+	}
+	w.Header().Set("ETag", updated.ETag())
+	utils.RespondWithJSON(w, http.StatusOK, updated)
 }
 
 //DeleteItem delete a item based on id
@@ -127,7 +471,16 @@ func (h *ItemsHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
 		return
 	}
-	err = h.useCase.DeleteItem(r.Context(), itemID)
+	version, ok := h.ifMatchVersion(w, r, itemID)
+	if !ok {
+		return
+	}
+	err = h.useCase.DeleteItem(r.Context(), itemID, version)
+	if errors.Is(err, utils.ErrItemVersionMismatch) {
+		h.logger.Info("Item has been modified")
+		utils.RespondWithError(w, http.StatusPreconditionFailed, "Item has been modified")
+		return
+	}
 	if errors.Is(err, utils.ErrItemNotDeleted) {
 		h.logger.Info("Failed to delete product")
 		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to delete product")
@@ -141,6 +494,81 @@ func (h *ItemsHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 	utils.RespondWithJSON(w, http.StatusOK, nil)
 }
 
+//GetItemHistory get the change history of a item based on id
+func (h *ItemsHandler) GetItemHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		h.logger.Info("Invalid id number")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	history, err := h.useCase.GetItemHistory(r.Context(), itemID)
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if err != nil {
+		h.logger.Info("Error occured while fetching the history")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the history")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, history)
+}
+
+//RestoreItem restore a deleted item based on id
+func (h *ItemsHandler) RestoreItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		h.logger.Info("Invalid id number")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	item, err := h.useCase.RestoreItem(r.Context(), itemID)
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Deleted item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Deleted item not found")
+		return
+	}
+	if err != nil {
+		h.logger.Info("Failed to restore product")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to restore product")
+		return
+	}
+	w.Header().Set("ETag", item.ETag())
+	utils.RespondWithJSON(w, http.StatusOK, item)
+}
+
+//PurgeItem remove a item for good based on id, deleted or not
+func (h *ItemsHandler) PurgeItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		h.logger.Info("Invalid id number")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	err = h.useCase.PurgeItem(r.Context(), itemID)
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, utils.ErrItemHasActiveBookings) {
+		h.logger.Info("Item has active bookings")
+		utils.RespondWithError(w, http.StatusConflict, "Item has active bookings")
+		return
+	}
+	if err != nil {
+		h.logger.Info("Failed to purge product")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to purge product")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, nil)
+}
+
 // BookAccommodation func
 func (h *ItemsHandler) BookAccommodation(w http.ResponseWriter, r *http.Request) {
 	var bookingInfo BookAccommodation
@@ -155,16 +583,70 @@ func (h *ItemsHandler) BookAccommodation(w http.ResponseWriter, r *http.Request)
 		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
+	invalidParams := bookingInfo.Validate()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
 	bookingInfo.ItemID = uint64(itemID)
-	err = h.useCase.BookAccommodation(r.Context(), bookingInfo)
+	booking, err := h.useCase.BookAccommodation(r.Context(), bookingInfo)
 	if err != nil {
 		if errors.Is(err, utils.ErrRoomsNotEnough) {
 			utils.RespondWithJSON(w, http.StatusOK, map[string]interface{}{"status": http.StatusOK, "message": "rooms not available"})
 			return
 		}
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to delete product")
+		if errors.Is(err, utils.ErrItemNotFound) {
+			h.logger.Info("Item not found")
+			utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+			return
+		}
+		if errors.Is(err, utils.ErrRoomTypeNotFound) {
+			h.logger.Info("Room type not found")
+			utils.RespondWithError(w, http.StatusNotFound, "Room type not found")
+			return
+		}
+		if errors.Is(err, utils.ErrRoomTypeRequired) {
+			h.logger.Info("Room type required")
+			utils.RespondWithError(w, http.StatusBadRequest, utils.ErrRoomTypeRequired.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrTooManyGuests) {
+			h.logger.Info("Too many guests")
+			utils.RespondWithError(w, http.StatusBadRequest, utils.ErrTooManyGuests.Error())
+			return
+		}
+		h.logger.Info("Booking failed")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Booking failed")
+		return
 	}
-	utils.RespondWithJSON(w, http.StatusOK, nil)
+	utils.RespondWithJSON(w, http.StatusCreated, booking)
+}
+
+// ifMatchVersion evaluates the If-Match header against the item and returns the version the change
+// should be conditional on, 0 without If-Match. ok is false when a response has already been written
+func (h *ItemsHandler) ifMatchVersion(w http.ResponseWriter, r *http.Request, itemID int) (uint64, bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return 0, true
+	}
+	current, err := h.useCase.GetItem(r.Context(), itemID)
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return 0, false
+	}
+	if err != nil {
+		h.logger.Info("Error occured while fetching the item")
+		utils.RespondWithError(w, http.StatusInternalServerError, "Error occured while fetching the item")
+		return 0, false
+	}
+	if !matchETag(ifMatch, current.ETag(), false) {
+		h.logger.Info("Item has been modified")
+		utils.RespondWithError(w, http.StatusPreconditionFailed, "Item has been modified")
+		return 0, false
+	}
+	return current.Version, true
 }
 
 //NewItemsHandler method
@@ -1,17 +1,22 @@
 package item
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/sayooj/trivago/currency"
 	"github.com/sayooj/trivago/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
@@ -30,7 +35,7 @@ var itemInfo = Item{
 		City:    "tsr",
 		State:   "kerala",
 		Country: "india",
-		ZipCode: 56766,
+		ZipCode: "680566",
 		Address: "sdsfsf  df fdf  df ",
 	},
 }
@@ -49,7 +54,7 @@ var itemsList = []Item{
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "680001",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	},
@@ -66,7 +71,7 @@ var itemsList = []Item{
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "680001",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	},
@@ -86,13 +91,23 @@ type MockUseCase struct {
 	mock.Mock
 }
 
-func (m *MockUseCase) AddItem(ctx context.Context, item Item) error {
+func (m *MockUseCase) AddItem(ctx context.Context, item Item) (Item, error) {
 	args := m.Called(ctx, item)
-	return args.Error(0)
+	return args.Get(0).(Item), args.Error(1)
 }
 
-func (m *MockUseCase) DeleteItem(ctx context.Context, id int) error {
-	args := m.Called(ctx, id)
+func (m *MockUseCase) ImportItems(ctx context.Context, reader ItemReader) (ImportReport, error) {
+	args := m.Called(ctx, reader)
+	return args.Get(0).(ImportReport), args.Error(1)
+}
+
+func (m *MockUseCase) Batch(ctx context.Context, batch BatchRequest) (BatchResult, error) {
+	args := m.Called(ctx, batch)
+	return args.Get(0).(BatchResult), args.Error(1)
+}
+
+func (m *MockUseCase) DeleteItem(ctx context.Context, id int, version uint64) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
@@ -101,26 +116,83 @@ func (m *MockUseCase) GetItem(ctx context.Context, id int) (Item, error) {
 	return args.Get(0).(Item), args.Error(1)
 }
 
-func (m *MockUseCase) UpdateItem(ctx context.Context, item Item) error {
+func (m *MockUseCase) UpdateItem(ctx context.Context, item Item) (Item, error) {
 	args := m.Called(ctx, item)
+	return args.Get(0).(Item), args.Error(1)
+}
+
+func (m *MockUseCase) RestoreItem(ctx context.Context, id int) (Item, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Item), args.Error(1)
+}
+
+func (m *MockUseCase) PurgeItem(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockUseCase) GetItems(ctx context.Context) ([]Item, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]Item), args.Error(1)
+func (m *MockUseCase) GetItemHistory(ctx context.Context, id int) ([]AuditEntry, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]AuditEntry), args.Error(1)
 }
 
-func (m *MockUseCase) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) error {
-	args := m.Called(ctx, bookingInfo)
+func (m *MockUseCase) ConvertPrices(ctx context.Context, items []Item, to string) error {
+	args := m.Called(ctx, items, to)
 	return args.Error(0)
 }
 
+func (m *MockUseCase) GetItems(ctx context.Context, filter ItemFilter) (ItemList, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(ItemList), args.Error(1)
+}
+
+func (m *MockUseCase) SearchItems(ctx context.Context, search ItemSearch) (SearchResults, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).(SearchResults), args.Error(1)
+}
+
+func (m *MockUseCase) NearbyItems(ctx context.Context, search NearbySearch) (NearbyResults, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).(NearbyResults), args.Error(1)
+}
+
+func (m *MockUseCase) ExportItems(ctx context.Context, filter ItemFilter, writer ItemWriter) error {
+	args := m.Called(ctx, filter, writer)
+	for _, i := range args.Get(0).([]Item) {
+		if err := writer.Write(i); err != nil {
+			return err
+		}
+	}
+	if err := args.Error(1); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func (m *MockUseCase) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) (Booking, error) {
+	args := m.Called(ctx, bookingInfo)
+	return args.Get(0).(Booking), args.Error(1)
+}
+
+//newRouteRequest builds a request as chi routes it, params are pairs of url param names and values and params
+// left empty are not set
+func newRouteRequest(method, url, body string, params ...string) *http.Request {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i+1] != "" {
+			rctx.URLParams.Add(params[i], params[i+1])
+		}
+	}
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
 func TestGetItemsHandler(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
 	ih := ItemsHandler{uc, log}
-	uc.On("GetItems", context.Background()).Return(itemsList, nil)
+	total := 2
+	uc.On("GetItems", context.Background(), ItemFilter{Limit: defaultItemsLimit}).Return(ItemList{Items: itemsList, Meta: ListMeta{Total: &total, Limit: defaultItemsLimit}}, nil)
 	req, err := http.NewRequest("GET", "/item", nil)
 	if err != nil {
 		t.Fatal(err)
@@ -130,20 +202,123 @@ func TestGetItemsHandler(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 	status := rr.Code
 	assert.NoError(t, err)
-	var item []Item
+	var list ItemList
 	decoder := json.NewDecoder(rr.Body)
-	err = decoder.Decode(&item)
+	err = decoder.Decode(&list)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, status)
-	assert.Equal(t, uint64(1), item[0].ID)
+	assert.Equal(t, uint64(1), list.Items[0].ID)
+	assert.Equal(t, &total, list.Meta.Total)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemsHandlerFilter(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	filter := ItemFilter{
+		Limit:           5,
+		Offset:          10,
+		Category:        "hotel",
+		City:            "abcs",
+		ReputationBadge: "green",
+		MinPrice:        500,
+		MaxPrice:        2000,
+		PriceCurrency:   "USD",
+		Sort:            []SortField{{Field: "price"}, {Field: "rating", Desc: true}},
+	}
+	uc.On("GetItems", context.Background(), filter).Return(ItemList{Items: itemsList}, nil)
+	req, err := http.NewRequest("GET", "/item?limit=5&offset=10&category=hotel&city=abcs&reputation_badge=green&min_price=500&max_price=2000&price_currency=usd&sort=price,-rating", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemsHandlerCursor(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	cursor := ItemCursor{Sort: "name", Values: []string{"hotel abcd"}, ID: 2}
+	filter := ItemFilter{Limit: defaultItemsLimit, Sort: []SortField{{Field: "name"}}, Cursor: &cursor}
+	uc.On("GetItems", context.Background(), filter).Return(ItemList{Items: itemsList}, nil)
+	req, _ := http.NewRequest("GET", "/item?sort=name&cursor="+cursor.Encode(), nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
 	uc.AssertExpectations(t)
 }
 
+func TestGetItemsHandlerCursorSortMismatch(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	cursor := ItemCursor{Sort: "name", Values: []string{"hotel abcd"}, ID: 2}
+	req, _ := http.NewRequest("GET", "/item?sort=-price&cursor="+cursor.Encode(), nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetItemsHandlerCursorTampered(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	cursor := ItemCursor{Sort: "-price", Values: []string{"cheap"}, ID: 2}
+	req, _ := http.NewRequest("GET", "/item?sort=-price&cursor="+cursor.Encode(), nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertNotCalled(t, "GetItems", mock.Anything, mock.Anything)
+}
+
+func TestGetItemsHandlerNoMatches(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	total := 0
+	uc.On("GetItems", context.Background(), ItemFilter{Limit: defaultItemsLimit, Category: "hostel"}).Return(ItemList{Items: []Item{}, Meta: ListMeta{Total: &total, Limit: defaultItemsLimit}}, nil)
+	req, _ := http.NewRequest("GET", "/item?category=hostel", nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// a first page without matches is counted, unlike the pages reached with a cursor
+	assert.Contains(t, rr.Body.String(), `"total":0`)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemsHandlerBadRequest(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, err := http.NewRequest("GET", "/item?limit=1000&sort=image", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var errModel utils.ErrorModel
+	err = json.NewDecoder(rr.Body).Decode(&errModel)
+	assert.NoError(t, err)
+	assert.Equal(t, "limit", errModel.InvalidParams[0].Name)
+	assert.Equal(t, "sort", errModel.InvalidParams[1].Name)
+}
+
 func TestGetItemsHandlerError(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
 	ih := ItemsHandler{uc, log}
-	uc.On("GetItems", context.Background()).Return([]Item{}, utils.ErrFetchError)
+	uc.On("GetItems", context.Background(), ItemFilter{Limit: defaultItemsLimit}).Return(ItemList{}, utils.ErrFetchError)
 	req, err := http.NewRequest("GET", "/item", nil)
 	if err != nil {
 		t.Fatal(err)
@@ -156,6 +331,114 @@ func TestGetItemsHandlerError(t *testing.T) {
 	uc.AssertExpectations(t)
 }
 
+func TestSearchItemsHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/search?q=+hilton+berlin&rating=5", nil)
+	search := ItemSearch{Query: "hilton berlin", Filter: ItemFilter{Limit: 20, Rating: 5}}
+	total := 2
+	results := SearchResults{ItemList: ItemList{Items: items, Meta: ListMeta{Total: &total, Limit: 20}}}
+	uc.On("SearchItems", req.Context(), search).Return(results, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.SearchItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res SearchResults
+	json.NewDecoder(rr.Body).Decode(&res)
+	assert.Equal(t, 2, len(res.Items))
+	assert.False(t, res.Fuzzy)
+	uc.AssertExpectations(t)
+}
+
+func TestSearchItemsHandlerBadRequest(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/search?sort=price", nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.SearchItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var errModel utils.ErrorModel
+	json.NewDecoder(rr.Body).Decode(&errModel)
+	assert.Equal(t, "q", errModel.InvalidParams[0].Name)
+	assert.Equal(t, "sort", errModel.InvalidParams[1].Name)
+	uc.AssertNotCalled(t, "SearchItems", mock.Anything, mock.Anything)
+}
+
+func TestNearbyItemsHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/nearby?lat=10.52&lng=76.21&radius_km=2.5", nil)
+	search := NearbySearch{Latitude: 10.52, Longitude: 76.21, RadiusKm: 2.5, Filter: ItemFilter{Limit: 20}}
+	total := 1
+	results := NearbyResults{Items: []NearbyItem{{Item: item, DistanceKm: 1.2}}, Meta: ListMeta{Total: &total, Limit: 20}}
+	uc.On("NearbyItems", req.Context(), search).Return(results, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.NearbyItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res map[string]interface{}
+	json.NewDecoder(rr.Body).Decode(&res)
+	first := res["items"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, 1.2, first["distance_km"])
+	assert.Equal(t, "hotel abcd", first["name"])
+	uc.AssertExpectations(t)
+}
+
+func TestNearbyItemsHandlerBadRequest(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/nearby?lat=10.52", nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.NearbyItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertNotCalled(t, "NearbyItems", mock.Anything, mock.Anything)
+}
+
+func TestExportItemsHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/export?format=csv&country=india&sort=-price", nil)
+	filter := ItemFilter{Limit: defaultItemsLimit, Country: "india", PriceCurrency: "EUR", Sort: []SortField{{Field: "price", Desc: true}}}
+	uc.On("ExportItems", req.Context(), filter, mock.Anything).Return(itemsList, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.ExportItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	assert.Equal(t, len(itemsList)+1, strings.Count(rr.Body.String(), "\n"))
+	uc.AssertExpectations(t)
+}
+
+func TestExportItemsHandlerBadFormat(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/export?format=xml", nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.ExportItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestExportItemsHandlerError(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/export", nil)
+	uc.On("ExportItems", req.Context(), ItemFilter{Limit: defaultItemsLimit}, mock.Anything).Return([]Item{}, utils.ErrFetchError)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.ExportItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
 func ItemsCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		articleParam := chi.URLParam(r, "id")
@@ -193,6 +476,84 @@ func TestGetItemHandler(t *testing.T) {
 	uc.AssertExpectations(t)
 }
 
+func TestGetItemHandlerConvertedPrice(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/1?currency=usd", nil)
+	req.Header.Set("If-None-Match", `"1"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	current := itemInfo
+	current.Version = 1
+	conversion := currency.Conversion{Currency: "USD", Amount: 1085, MinorUnits: 2, Rate: 1.0853, Rounding: currency.RoundingHalfUp}
+	uc.On("GetItem", req.Context(), 1).Return(current, nil)
+	uc.On("ConvertPrices", req.Context(), []Item{current}, "USD").Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).([]Item)[0].ConvertedPrice = &conversion
+	})
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ih.GetItem).ServeHTTP(rr, req)
+	var res Item
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	// a converted price is sent even though the version matches
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, uint64(1085), res.ConvertedPrice.Amount)
+	assert.Equal(t, "half_up", res.ConvertedPrice.Rounding)
+	// the converted representation does not share the entity tag of the item
+	assert.Equal(t, `W/"1-USD"`, rr.Header().Get("ETag"))
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemsHandlerConvertedPriceNoRate(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	uc.On("GetItems", context.Background(), ItemFilter{Limit: defaultItemsLimit}).Return(ItemList{Items: itemsList}, nil)
+	uc.On("ConvertPrices", context.Background(), itemsList, "JPY").Return(fmt.Errorf("%w %s", utils.ErrExchangeRateNotFound, "JPY"))
+	req, _ := http.NewRequest("GET", "/item?currency=JPY", nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ih.GetItems).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemsHandlerUnknownCurrency(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item?currency=XYZ", nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ih.GetItems).ServeHTTP(rr, req)
+	var errModel utils.ErrorModel
+	err := json.NewDecoder(rr.Body).Decode(&errModel)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "currency", errModel.InvalidParams[0].Name)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemHandlerNotModified(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/1", nil)
+	req.Header.Set("If-None-Match", `W/"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	current := itemInfo
+	current.Version = 3
+	uc.On("GetItem", req.Context(), 1).Return(current, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	assert.Empty(t, rr.Body.String())
+}
+
 func TestGetItemHandlerBadRequest(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
@@ -233,7 +594,7 @@ func TestDeleteItemHandler(t *testing.T) {
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
-	uc.On("DeleteItem", req.Context(), 1).Return(nil)
+	uc.On("DeleteItem", req.Context(), 1, uint64(0)).Return(nil)
 	rr := httptest.NewRecorder()
 	handler := http.HandlerFunc(ih.DeleteItem)
 	handler.ServeHTTP(rr, req)
@@ -242,6 +603,26 @@ func TestDeleteItemHandler(t *testing.T) {
 	uc.AssertExpectations(t)
 }
 
+func TestDeleteItemHandlerIfMatch(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("DELETE", "/item/1", nil)
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	current := itemInfo
+	current.Version = 3
+	uc.On("GetItem", req.Context(), 1).Return(current, nil)
+	uc.On("DeleteItem", req.Context(), 1, uint64(3)).Return(utils.ErrItemVersionMismatch)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.DeleteItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	uc.AssertExpectations(t)
+}
+
 func TestDeleteItemHandlerBadRequest(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
@@ -265,7 +646,7 @@ func TestDeleteItemHandlerInternalError(t *testing.T) {
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
-	uc.On("DeleteItem", req.Context(), 1).Return(utils.ErrItemNotDeleted)
+	uc.On("DeleteItem", req.Context(), 1, uint64(0)).Return(utils.ErrItemNotDeleted)
 	rr := httptest.NewRecorder()
 	handler := http.HandlerFunc(ih.DeleteItem)
 	handler.ServeHTTP(rr, req)
@@ -274,11 +655,160 @@ func TestDeleteItemHandlerInternalError(t *testing.T) {
 	uc.AssertExpectations(t)
 }
 
+func TestRestoreItemHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("POST", "/item/1/restore", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	uc.On("RestoreItem", req.Context(), 1).Return(itemInfo, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.RestoreItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestRestoreItemHandlerNotFound(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("POST", "/item/1/restore", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	uc.On("RestoreItem", req.Context(), 1).Return(Item{}, utils.ErrItemNotFound)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.RestoreItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPurgeItemHandlerActiveBookings(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("DELETE", "/item/1/purge", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	uc.On("PurgeItem", req.Context(), 1).Return(utils.ErrItemHasActiveBookings)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.PurgeItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetItemHistoryHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("GET", "/item/1/history", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	history := []AuditEntry{{ID: 1, ItemID: 1, Action: "update", Actor: "svr", Changes: []FieldChange{{Field: "price", From: 1000, To: 1200}}}}
+	uc.On("GetItemHistory", req.Context(), 1).Return(history, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.GetItemHistory)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res []AuditEntry
+	json.NewDecoder(rr.Body).Decode(&res)
+	assert.Equal(t, "svr", res[0].Actor)
+	assert.Equal(t, "price", res[0].Changes[0].Field)
+	uc.AssertExpectations(t)
+}
+
+func TestImportItemsHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("POST", "/item/import", bytes.NewBufferString(`{"name": "hotel abcdefghijk"}`))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	report := ImportReport{Rejected: 1, Rows: []ImportRowResult{{Row: 1, Status: "rejected"}}}
+	uc.On("ImportItems", req.Context(), mock.Anything).Return(report, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.ImportItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res ImportReport
+	json.NewDecoder(rr.Body).Decode(&res)
+	assert.Equal(t, report, res)
+	uc.AssertExpectations(t)
+}
+
+func TestImportItemsHandlerUnsupportedFormat(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("POST", "/item/import", bytes.NewBufferString(`[]`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.ImportItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+}
+
+func TestImportItemsHandlerBadHeader(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("POST", "/item/import?format=csv", bytes.NewBufferString("name\nhotel\n"))
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.ImportItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertNotCalled(t, "ImportItems", mock.Anything, mock.Anything)
+}
+
+func TestBatchItemsHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("POST", "/item/batch", bytes.NewBufferString(`{"atomic": true, "operations": [{"ref": "a", "op": "delete", "id": 1}, {"ref": "b", "op": "delete", "id": 2, "version": 3}]}`))
+	batch := BatchRequest{Atomic: true, Operations: []BatchOperation{{Ref: "a", Op: "delete", ID: 1}, {Ref: "b", Op: "delete", ID: 2, Version: 3}}}
+	result := BatchResult{Atomic: true, Results: []BatchOperationResult{
+		{Ref: "a", Op: "delete", err: utils.ErrBatchRolledBack},
+		{Ref: "b", Op: "delete", err: utils.ErrItemVersionMismatch},
+	}}
+	uc.On("Batch", req.Context(), batch).Return(result, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.BatchItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res BatchResult
+	json.NewDecoder(rr.Body).Decode(&res)
+	assert.False(t, res.Committed)
+	assert.Equal(t, http.StatusFailedDependency, res.Results[0].Status)
+	assert.Equal(t, http.StatusPreconditionFailed, res.Results[1].Status)
+	assert.Equal(t, "Item has been modified", res.Results[1].Error)
+	uc.AssertExpectations(t)
+}
+
+func TestBatchItemsHandlerDuplicateRef(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("POST", "/item/batch", bytes.NewBufferString(`{"operations": [{"ref": "a", "op": "delete", "id": 1}, {"ref": "a", "op": "delete", "id": 2}]}`))
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.BatchItems)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertNotCalled(t, "Batch", mock.Anything, mock.Anything)
+}
+
 func TestAddItemHandler(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
 	ih := ItemsHandler{uc, log}
-	uc.On("AddItem", context.Background(), itemInfo).Return(nil)
+	created := itemInfo
+	created.ID = 42
+	created.ReputationBadge = "green"
+	uc.On("AddItem", context.Background(), itemInfo).Return(created, nil)
 	body, _ := os.Open("valid_mock.json")
 	req, _ := http.NewRequest("POST", "/item", body)
 	rr := httptest.NewRecorder()
@@ -286,9 +816,30 @@ func TestAddItemHandler(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 	status := rr.Code
 	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "/item/42", rr.Header().Get("Location"))
+	var res Item
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, created, res)
 	uc.AssertExpectations(t)
 }
 
+func TestAddItemHandlerInvalidLocation(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	uc.On("AddItem", context.Background(), itemInfo).Return(Item{}, utils.ErrInvalidLocation)
+	body, _ := os.Open("valid_mock.json")
+	req, _ := http.NewRequest("POST", "/item", body)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.AddItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var errModel utils.ErrorModel
+	json.NewDecoder(rr.Body).Decode(&errModel)
+	assert.Equal(t, "zip code", errModel.InvalidParams[0].Name)
+}
+
 func TestAddItemHandlerBadReq(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
@@ -306,7 +857,7 @@ func TestAddItemHandlerInternalError(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
 	ih := ItemsHandler{uc, log}
-	uc.On("AddItem", context.Background(), itemInfo).Return(utils.ErrItemNotAdded)
+	uc.On("AddItem", context.Background(), itemInfo).Return(Item{}, utils.ErrItemNotAdded)
 	body, _ := os.Open("valid_mock.json")
 	req, _ := http.NewRequest("POST", "/item", body)
 	rr := httptest.NewRecorder()
@@ -326,7 +877,7 @@ func TestUpdateItemandler(t *testing.T) {
 	rctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 	rr := httptest.NewRecorder()
-	uc.On("UpdateItem", req.Context(), itemInfo).Return(nil)
+	uc.On("UpdateItem", req.Context(), itemInfo).Return(itemInfo, nil)
 	handler := http.HandlerFunc(ih.UpdateItem)
 	handler.ServeHTTP(rr, req)
 	status := rr.Code
@@ -334,6 +885,54 @@ func TestUpdateItemandler(t *testing.T) {
 	uc.AssertExpectations(t)
 }
 
+func TestUpdateItemandlerIfMatch(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	body, _ := os.Open("valid_mock.json")
+	req, _ := http.NewRequest("PUT", "/item/1", body)
+	req.Header.Set("If-Match", `"3"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	expected := itemInfo
+	expected.Version = 3
+	updated := expected
+	updated.Version = 4
+	uc.On("GetItem", req.Context(), 1).Return(expected, nil)
+	uc.On("UpdateItem", req.Context(), expected).Return(updated, nil)
+	handler := http.HandlerFunc(ih.UpdateItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+	var res Item
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, itemInfo.Name, res.Name)
+	uc.AssertExpectations(t)
+}
+
+func TestUpdateItemandlerPreconditionFailed(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	body, _ := os.Open("valid_mock.json")
+	req, _ := http.NewRequest("PUT", "/item/1", body)
+	req.Header.Set("If-Match", `"2"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	current := itemInfo
+	current.Version = 3
+	uc.On("GetItem", req.Context(), 1).Return(current, nil)
+	handler := http.HandlerFunc(ih.UpdateItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	uc.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
 func TestUpdateItemandlerBadRequest(t *testing.T) {
 	log := logrus.New()
 	uc := new(MockUseCase)
@@ -360,10 +959,178 @@ func TestUpdateItemandlerInernalError(t *testing.T) {
 	rctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 	rr := httptest.NewRecorder()
-	uc.On("UpdateItem", req.Context(), itemInfo).Return(utils.ErrItemNotUpdated)
+	uc.On("UpdateItem", req.Context(), itemInfo).Return(Item{}, utils.ErrItemNotUpdated)
 	handler := http.HandlerFunc(ih.UpdateItem)
 	handler.ServeHTTP(rr, req)
 	status := rr.Code
 	assert.Equal(t, http.StatusInternalServerError, status)
 	uc.AssertExpectations(t)
 }
+
+func TestUpdateItemandlerGeocodeError(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	body, _ := os.Open("valid_mock.json")
+	req, _ := http.NewRequest("PUT", "/item/1", body)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	uc.On("UpdateItem", req.Context(), itemInfo).Return(Item{}, fmt.Errorf("looking up the location %w", utils.ErrFetchError))
+	handler := http.HandlerFunc(ih.UpdateItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Empty(t, rr.Header().Get("ETag"))
+	uc.AssertExpectations(t)
+}
+
+func TestPatchItemHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	body := bytes.NewBufferString(`{"availability": 0, "location": {"city": "kochi"}}`)
+	req, _ := http.NewRequest("PATCH", "/item/1", body)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	patched := itemInfo
+	patched.Availability = 0
+	patched.Location.City = "kochi"
+	uc.On("GetItem", req.Context(), 1).Return(itemInfo, nil)
+	uc.On("UpdateItem", req.Context(), patched).Return(patched, nil)
+	handler := http.HandlerFunc(ih.PatchItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res Item
+	json.NewDecoder(rr.Body).Decode(&res)
+	assert.Equal(t, patched, res)
+	uc.AssertExpectations(t)
+}
+
+func TestPatchItemHandlerNull(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	body := bytes.NewBufferString(`{"name": null}`)
+	req, _ := http.NewRequest("PATCH", "/item/1", body)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	uc.On("GetItem", req.Context(), 1).Return(itemInfo, nil)
+	handler := http.HandlerFunc(ih.PatchItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
+func TestPatchItemHandlerUnsupportedMediaType(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("PATCH", "/item/1", bytes.NewBufferString(`name=abc`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.PatchItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+}
+
+func TestPatchItemHandlerNotFound(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("PATCH", "/item/1", bytes.NewBufferString(`{"price": 10}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	uc.On("GetItem", req.Context(), 1).Return(Item{}, utils.ErrItemNotFound)
+	handler := http.HandlerFunc(ih.PatchItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestUpdateItemandlerMissingFields(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	req, _ := http.NewRequest("PUT", "/item/1", bytes.NewBufferString(`{"name": "hotel abcdefghijk"}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.UpdateItem)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
+func TestBookAccommodationHandler(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	checkIn := time.Now().UTC().AddDate(0, 0, 7)
+	booking := bookingInfos
+	booking.CheckIn = checkIn.Format("2006-01-02")
+	booking.CheckOut = checkIn.AddDate(0, 0, 2).Format("2006-01-02")
+	body, _ := json.Marshal(booking)
+	req, _ := http.NewRequest("POST", "/item/1/book", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	created := Booking{ID: 7, ConfirmationCode: "ABCD-EF23", Status: "pending"}
+	uc.On("BookAccommodation", req.Context(), booking).Return(created, nil)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.BookAccommodation)
+	handler.ServeHTTP(rr, req)
+	var res Booking
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "ABCD-EF23", res.ConfirmationCode)
+	assert.Equal(t, "pending", res.Status)
+	uc.AssertExpectations(t)
+}
+
+func TestBookAccommodationHandlerRoomTypeRequired(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	checkIn := time.Now().UTC().AddDate(0, 0, 7)
+	booking := bookingInfos
+	booking.CheckIn = checkIn.Format("2006-01-02")
+	booking.CheckOut = checkIn.AddDate(0, 0, 1).Format("2006-01-02")
+	body, _ := json.Marshal(booking)
+	req, _ := http.NewRequest("POST", "/item/1/book", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	uc.On("BookAccommodation", req.Context(), booking).Return(Booking{}, utils.ErrRoomTypeRequired)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.BookAccommodation)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestBookAccommodationHandlerBadRequest(t *testing.T) {
+	log := logrus.New()
+	uc := new(MockUseCase)
+	ih := ItemsHandler{uc, log}
+	body, _ := json.Marshal(bookingInfos)
+	req, _ := http.NewRequest("POST", "/item/1/book", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(ih.BookAccommodation)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -1,25 +1,48 @@
 package item
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/sayooj/trivago/amenity"
+	"github.com/sayooj/trivago/currency"
+	"github.com/sayooj/trivago/geocode"
 	"github.com/sayooj/trivago/utils"
 )
 
 // Item struct
 type Item struct {
-	ID              uint64   `json:"id"`
-	Name            string   `json:"name"`
-	Rating          uint     `json:"rating"`
-	Category        string   `json:"category"`
-	Location        Location `json:"location"`
-	Image           string   `json:"image"`
-	Reputation      uint64   `json:"reputation"`
-	ReputationBadge string   `json:"reputationBadge"`
-	Price           uint64   `json:"price"`
-	Availability    uint     `json:"availability"`
+	ID       uint64   `json:"id"`
+	Name     string   `json:"name"`
+	Rating   uint     `json:"rating"`
+	Category string   `json:"category"`
+	Location Location `json:"location"`
+	Image    string   `json:"image"`
+	// Reputation is typed in until the item is reviewed, from then on it is computed from the reviews
+	// and ReviewCount counts them
+	Reputation      uint64 `json:"reputation"`
+	ReputationBadge string `json:"reputationBadge"`
+	ReviewCount     uint   `json:"review_count"`
+	// Price is in minor units of Currency, an ISO 4217 code, like cents of EUR. An item stored without a
+	// currency is in EUR, an update without one keeps it
+	Price        uint64 `json:"price"`
+	Currency     string `json:"currency"`
+	Availability uint   `json:"availability"`
+	// ConvertedPrice is the price in the currency asked for with ?currency=, it is never stored
+	ConvertedPrice *currency.Conversion `json:"converted_price,omitempty"`
+	// Amenities are the codes of the amenity catalogue the item offers, an update without them keeps them
+	Amenities []string `json:"amenities"`
+	// Version is bumped on every update and exposed as the ETag
+	Version uint64 `json:"-"`
 }
 
 // Location struct
@@ -27,15 +50,514 @@ type Location struct {
 	City    string `json:"city"`
 	State   string `json:"state"`
 	Country string `json:"country"`
-	ZipCode uint64 `json:"zip_code"`
+	ZipCode string `json:"zip_code"`
 	Address string `json:"address"`
+	// Latitude and Longitude are in degrees, items stored before coordinates were known have none
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	// CityMismatch is set when the city is not a place of the zip code in the postal code reference data
+	CityMismatch bool `json:"city_mismatch"`
 }
 
-// BookAccommodation struct
+// BookAccommodation struct, items selling room types are booked for one of them with RoomTypeID
 type BookAccommodation struct {
 	ItemID     uint64 `json:"item_id"`
+	RoomTypeID uint64 `json:"room_type_id"`
 	PersonName string `json:"person_name"`
 	NoOfRooms  uint   `json:"no_of_rooms"`
+	Guests     uint   `json:"guests"`
+	CheckIn    string `json:"check_in"`
+	CheckOut   string `json:"check_out"`
+}
+
+// Validate validates the booking request, the stay covers the nights from check in up to the day before check out
+func (b BookAccommodation) Validate() []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if b.PersonName == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "person_name", Reason: "person_name required"})
+	}
+	if b.NoOfRooms == 0 {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "no_of_rooms", Reason: "no_of_rooms required"})
+	}
+	if b.Guests > 0 && b.Guests < b.NoOfRooms {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "guests", Reason: "guests should be at least one per room"})
+	}
+	return append(validationErr, validateStay(b.CheckIn, b.CheckOut, "check_in", "check_out")...)
+}
+
+// Nights returns the number of nights between check in and check out
+func (b BookAccommodation) Nights() int {
+	return stayNights(b.CheckIn, b.CheckOut)
+}
+
+// validateStay validates the check in and check out dates of a stay, checkInName and checkOutName are the
+// names the dates are reported with
+func validateStay(checkInDate, checkOutDate, checkInName, checkOutName string) []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	checkIn, err := time.Parse(dateLayout, checkInDate)
+	if err != nil {
+		validationErr = append(validationErr, utils.InvalidParams{Name: checkInName, Reason: checkInName + " should be a date in YYYY-MM-DD format"})
+	}
+	checkOut, err := time.Parse(dateLayout, checkOutDate)
+	if err != nil {
+		validationErr = append(validationErr, utils.InvalidParams{Name: checkOutName, Reason: checkOutName + " should be a date in YYYY-MM-DD format"})
+	}
+	if checkIn.IsZero() || checkOut.IsZero() {
+		return validationErr
+	}
+	today, _ := time.Parse(dateLayout, time.Now().UTC().Format(dateLayout))
+	if checkIn.Before(today) {
+		validationErr = append(validationErr, utils.InvalidParams{Name: checkInName, Reason: checkInName + " should not be in the past"})
+	}
+	if !checkOut.After(checkIn) {
+		validationErr = append(validationErr, utils.InvalidParams{Name: checkOutName, Reason: checkOutName + " should be after " + checkInName})
+	} else if stayNights(checkInDate, checkOutDate) > maxStayNights {
+		validationErr = append(validationErr, utils.InvalidParams{Name: checkOutName, Reason: fmt.Sprintf("stay should not be longer than %d nights", maxStayNights)})
+	}
+	return validationErr
+}
+
+// stayNights returns the number of nights between check in and check out
+func stayNights(checkInDate, checkOutDate string) int {
+	checkIn, err := time.Parse(dateLayout, checkInDate)
+	if err != nil {
+		return 0
+	}
+	checkOut, err := time.Parse(dateLayout, checkOutDate)
+	if err != nil {
+		return 0
+	}
+	return int(checkOut.Sub(checkIn).Hours() / 24)
+}
+
+// NewPriceCurrency reads the currency of ?currency=EUR prices are converted to, it is "" when they are not
+func NewPriceCurrency(query url.Values) (string, []utils.InvalidParams) {
+	code := strings.ToUpper(strings.TrimSpace(query.Get("currency")))
+	if code != "" && !currency.Known(code) {
+		return "", []utils.InvalidParams{{Name: "currency", Reason: "currency should be an ISO 4217 code like EUR"}}
+	}
+	return code, nil
+}
+
+// ItemFilter holds the paging, filtering and sorting options of an item listing
+type ItemFilter struct {
+	Limit           int
+	Offset          int
+	Category        string
+	City            string
+	Country         string
+	Rating          uint
+	ReputationBadge string
+	MinPrice        uint64
+	MaxPrice        uint64
+	// PriceCurrency narrows the listing to the items priced in it. Prices in different currencies do not
+	// compare, so min_price, max_price and sorting by price use it, EUR when it is not given
+	PriceCurrency string
+	CityMismatch  bool
+	// Amenities narrows the listing to items offering all of them, or any of them with AnyAmenity
+	Amenities  []string
+	AnyAmenity bool
+	Sort       []SortField
+	Cursor     *ItemCursor
+}
+
+// SortField struct
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ItemCursor marks the last item of a page for keyset pagination
+type ItemCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     uint64   `json:"id"`
+}
+
+// ItemList is a page of items along with the listing metadata
+type ItemList struct {
+	Items      []Item   `json:"items"`
+	Meta       ListMeta `json:"meta"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// ListMeta struct, Total is not counted for the pages of a listing reached with a cursor, which leave it out
+// so that deep pages cost the same as the first one. A counted listing without matches has a total of 0
+type ListMeta struct {
+	Total  *int `json:"total,omitempty"`
+	Limit  int  `json:"limit"`
+	Offset int  `json:"offset"`
+}
+
+// NewItemFilter builds an ItemFilter from the query parameters of GET /item
+func NewItemFilter(query url.Values) (ItemFilter, []utils.InvalidParams) {
+	validationErr := []utils.InvalidParams{}
+	filter := ItemFilter{Limit: defaultItemsLimit}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxItemsLimit {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "limit", Reason: fmt.Sprintf("limit should be between 1 and %d", maxItemsLimit)})
+		}
+		filter.Limit = limit
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "offset", Reason: "offset should be >= 0"})
+		}
+		filter.Offset = offset
+	}
+
+	filter.Category = query.Get("category")
+	filter.City = query.Get("city")
+	filter.Country = query.Get("country")
+
+	if v := query.Get("rating"); v != "" {
+		rating, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || rating < 1 || rating > 5 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "rating", Reason: "rating should be between 1 and 5"})
+		}
+		filter.Rating = uint(rating)
+	}
+	if v := query.Get("reputation_badge"); v != "" {
+		if _, ok := reputationBadgeRanges[v]; !ok {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "reputation_badge", Reason: "reputation_badge should be any of [red, yellow, green]"})
+		}
+		filter.ReputationBadge = v
+	}
+	if v := query.Get("min_price"); v != "" {
+		price, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "min_price", Reason: "min_price should be a positive number"})
+		}
+		filter.MinPrice = price
+	}
+	if v := query.Get("max_price"); v != "" {
+		price, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "max_price", Reason: "max_price should be a positive number"})
+		}
+		filter.MaxPrice = price
+	}
+	if v := query.Get("price_currency"); v != "" {
+		filter.PriceCurrency = strings.ToUpper(strings.TrimSpace(v))
+		if !currency.Known(filter.PriceCurrency) {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "price_currency", Reason: "price_currency should be an ISO 4217 code like EUR"})
+		}
+	}
+	if v := query.Get("city_mismatch"); v != "" {
+		mismatch, err := strconv.ParseBool(v)
+		if err != nil {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "city_mismatch", Reason: "city_mismatch should be true or false"})
+		}
+		filter.CityMismatch = mismatch
+	}
+	if v := query.Get("amenities"); v != "" {
+		filter.Amenities = amenityCodes(strings.Split(v, ","))
+		for _, code := range filter.Amenities {
+			if !amenity.ValidCode(code) {
+				validationErr = append(validationErr, utils.InvalidParams{Name: "amenities", Reason: "amenities should be a comma separated list of amenity codes"})
+				break
+			}
+		}
+	}
+	switch query.Get("amenities_match") {
+	case "", amenitiesMatchAll:
+	case amenitiesMatchAny:
+		filter.AnyAmenity = true
+	default:
+		validationErr = append(validationErr, utils.InvalidParams{Name: "amenities_match", Reason: "amenities_match should be any of [all, any]"})
+	}
+	if filter.MaxPrice != 0 && filter.MinPrice > filter.MaxPrice {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "max_price", Reason: "max_price should be >= min_price"})
+	}
+
+	if v := query.Get("sort"); v != "" {
+		for _, key := range strings.Split(v, ",") {
+			field := SortField{Field: strings.TrimSpace(key)}
+			if strings.HasPrefix(field.Field, "-") {
+				field.Field = field.Field[1:]
+				field.Desc = true
+			}
+			if _, ok := sortableColumns[field.Field]; !ok {
+				validationErr = append(validationErr, utils.InvalidParams{Name: "sort", Reason: "sort should be a comma separated list of [id, name, rating, reputation, price, availability], prefixed with - for descending order"})
+				break
+			}
+			filter.Sort = append(filter.Sort, field)
+		}
+	}
+
+	// prices were all in EUR before items had a currency, so price filters and sorts without one keep meaning EUR
+	if filter.PriceCurrency == "" && (filter.MinPrice != 0 || filter.MaxPrice != 0 || sortsByPrice(filter.Sort)) {
+		filter.PriceCurrency = currency.DefaultCurrency
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		cursor, err := DecodeItemCursor(v)
+		switch {
+		case err != nil:
+			validationErr = append(validationErr, utils.InvalidParams{Name: "cursor", Reason: "cursor is invalid"})
+		case cursor.Sort != SortSpec(filter.Sort) || len(cursor.Values) != len(keysetFields(filter.Sort))-1:
+			validationErr = append(validationErr, utils.InvalidParams{Name: "cursor", Reason: "cursor was issued for a different sort"})
+		case !validCursorValues(filter.Sort, cursor):
+			validationErr = append(validationErr, utils.InvalidParams{Name: "cursor", Reason: "cursor is invalid"})
+		case filter.Offset != 0:
+			validationErr = append(validationErr, utils.InvalidParams{Name: "offset", Reason: "offset can not be combined with cursor"})
+		default:
+			filter.Cursor = &cursor
+		}
+	}
+	return filter, validationErr
+}
+
+// validCursorValues tells whether the values of the cursor fit the columns of the sort, every sortable
+// column but the name is a whole number
+func validCursorValues(sort []SortField, cursor ItemCursor) bool {
+	for i, field := range keysetFields(sort) {
+		if field.Field == "id" || field.Field == "name" {
+			continue
+		}
+		if _, err := strconv.ParseUint(cursor.Values[i], 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// sortsByPrice tells whether the listing is ordered by price
+func sortsByPrice(sort []SortField) bool {
+	for _, field := range sort {
+		if field.Field == "price" {
+			return true
+		}
+	}
+	return false
+}
+
+// SortSpec formats the sort fields the way the sort query parameter accepts them
+func SortSpec(sort []SortField) string {
+	keys := []string{}
+	for _, field := range sort {
+		if field.Desc {
+			keys = append(keys, "-"+field.Field)
+			continue
+		}
+		keys = append(keys, field.Field)
+	}
+	return strings.Join(keys, ",")
+}
+
+// keysetFields returns the fields a listing is ordered by, the item id always
+// ends the list so that every item has a unique position
+func keysetFields(sort []SortField) []SortField {
+	fields := []SortField{}
+	for _, field := range sort {
+		fields = append(fields, field)
+		if field.Field == "id" {
+			return fields
+		}
+	}
+	return append(fields, SortField{Field: "id"})
+}
+
+// NewItemCursor creates the cursor pointing right after the item
+func NewItemCursor(sort []SortField, i Item) ItemCursor {
+	cursor := ItemCursor{Sort: SortSpec(sort), Values: []string{}, ID: i.ID}
+	for _, field := range keysetFields(sort) {
+		switch field.Field {
+		case "name":
+			cursor.Values = append(cursor.Values, i.Name)
+		case "rating":
+			cursor.Values = append(cursor.Values, fmt.Sprint(i.Rating))
+		case "reputation":
+			cursor.Values = append(cursor.Values, fmt.Sprint(i.Reputation))
+		case "price":
+			cursor.Values = append(cursor.Values, fmt.Sprint(i.Price))
+		case "availability":
+			cursor.Values = append(cursor.Values, fmt.Sprint(i.Availability))
+		}
+	}
+	return cursor
+}
+
+// Encode returns the opaque form of the cursor handed out to clients
+func (c ItemCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeItemCursor parses a cursor created by Encode
+func DecodeItemCursor(s string) (ItemCursor, error) {
+	var cursor ItemCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ItemCursor{}, err
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return ItemCursor{}, err
+	}
+	return cursor, nil
+}
+
+// ETag returns the entity tag of the current version of the item
+func (i Item) ETag() string {
+	return fmt.Sprintf(`"%d"`, i.Version)
+}
+
+// ConvertedETag returns the weak entity tag of the item with its price converted to the currency to, it is
+// weak because the converted price moves with the exchange rates
+func (i Item) ConvertedETag(to string) string {
+	return fmt.Sprintf(`W/"%d-%s"`, i.Version, to)
+}
+
+// matchETag reports whether an If-Match or If-None-Match header value matches etag.
+// If-None-Match compares weakly, so weak should be true for it
+func matchETag(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyMergePatch applies an RFC 7396 merge patch document to the item. Every
+// item field is required, so a null member is reported instead of removing it
+func (i Item) ApplyMergePatch(doc []byte) (Item, []utils.InvalidParams, error) {
+	var patch interface{}
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+	if err := decoder.Decode(&patch); err != nil {
+		return Item{}, nil, err
+	}
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return Item{}, nil, errors.New("merge patch should be a JSON object")
+	}
+	if invalidParams := nullMembers("", patchObject); len(invalidParams) > 0 {
+		return Item{}, invalidParams, nil
+	}
+
+	current, err := json.Marshal(i)
+	if err != nil {
+		return Item{}, nil, err
+	}
+	var target interface{}
+	decoder = json.NewDecoder(bytes.NewReader(current))
+	decoder.UseNumber()
+	if err := decoder.Decode(&target); err != nil {
+		return Item{}, nil, err
+	}
+	merged, err := json.Marshal(mergePatch(target, patchObject))
+	if err != nil {
+		return Item{}, nil, err
+	}
+
+	var patched Item
+	decoder = json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return Item{}, nil, err
+	}
+	// id, badge, review count and converted price are read only
+	patched.ID = i.ID
+	patched.ReputationBadge = i.ReputationBadge
+	patched.ReviewCount = i.ReviewCount
+	patched.ConvertedPrice = nil
+	patched.Version = i.Version
+	return patched, nil, nil
+}
+
+// mergePatch implements the MergePatch function of RFC 7396
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// nullMembers lists the members of the patch that are set to null, but for the optional ones
+func nullMembers(prefix string, patch map[string]interface{}) []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	names := make([]string, 0, len(patch))
+	for name := range patch {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch value := patch[name].(type) {
+		case nil:
+			if optionalPatchMembers[prefix+name] {
+				continue
+			}
+			validationErr = append(validationErr, utils.InvalidParams{Name: prefix + name, Reason: prefix + name + " can not be removed"})
+		case map[string]interface{}:
+			validationErr = append(validationErr, nullMembers(prefix+name+".", value)...)
+		}
+	}
+	return validationErr
+}
+
+// postalCodeRule is the format of the postal codes of a country
+type postalCodeRule struct {
+	pattern *regexp.Regexp
+	example string
+}
+
+// postalCodeRuleOf returns the postal code format of a country name or ISO 3166-1 alpha-2 code,
+// a country without a rule accepts any short code of letters, digits, spaces and hyphens
+func postalCodeRuleOf(country string) postalCodeRule {
+	if code, ok := geocode.CountryCode(country); ok {
+		if rule, ok := postalCodeRules[code]; ok {
+			return rule
+		}
+	}
+	return defaultPostalCodeRule
+}
+
+// invalidLocationParams is the validation error of a zip code that is not a postal code of its country
+func invalidLocationParams() []utils.InvalidParams {
+	return []utils.InvalidParams{{Name: "zip code", Reason: utils.ErrInvalidLocation.Error()}}
+}
+
+// invalidAmenitiesParams is the validation error of amenities that are not in the catalogue
+func invalidAmenitiesParams() []utils.InvalidParams {
+	return []utils.InvalidParams{{Name: "amenities", Reason: utils.ErrUnknownAmenity.Error()}}
+}
+
+// amenityCodes trims the codes and drops empty and repeated ones, keeping their order
+func amenityCodes(codes []string) []string {
+	unique := make([]string, 0, len(codes))
+	seen := map[string]bool{}
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		unique = append(unique, code)
+	}
+	return unique
 }
 
 // ValidateRequiredItem validates the item
@@ -142,15 +664,40 @@ func (i Item) ValidateFields() []utils.InvalidParams {
 		validationErr = append(validationErr, invalidItem)
 	}
 
-	if i.Location.ZipCode != 0 {
-		// re := regexp.MustCompile(`\[0-9][0-9][0-9][0-9][0-9]`)
-		strZip := fmt.Sprint(i.Location.ZipCode)
-		// valid := re.MatchString(strZip)
-		valid := len(strZip) == 5
-		if !valid {
+	if i.Location.ZipCode != "" {
+		rule := postalCodeRuleOf(i.Location.Country)
+		if !rule.pattern.MatchString(strings.ToUpper(strings.TrimSpace(i.Location.ZipCode))) {
 			invalidItem.Name = "zip code"
-			invalidItem.Reason = `zip code  should be 5 digits`
+			invalidItem.Reason = `zip code should be like ` + rule.example
+			validationErr = append(validationErr, invalidItem)
+		}
+	}
+	if (i.Location.Latitude == nil) != (i.Location.Longitude == nil) {
+		invalidItem.Name = "location"
+		invalidItem.Reason = `latitude and longitude should be given together`
+		validationErr = append(validationErr, invalidItem)
+	}
+	if i.Location.Latitude != nil && (*i.Location.Latitude < -90 || *i.Location.Latitude > 90) {
+		invalidItem.Name = "latitude"
+		invalidItem.Reason = `latitude should be >= -90 and <= 90`
+		validationErr = append(validationErr, invalidItem)
+	}
+	if i.Location.Longitude != nil && (*i.Location.Longitude < -180 || *i.Location.Longitude > 180) {
+		invalidItem.Name = "longitude"
+		invalidItem.Reason = `longitude should be >= -180 and <= 180`
+		validationErr = append(validationErr, invalidItem)
+	}
+	if i.Currency != "" && !currency.Known(i.Currency) {
+		invalidItem.Name = "currency"
+		invalidItem.Reason = `currency should be an ISO 4217 code like EUR`
+		validationErr = append(validationErr, invalidItem)
+	}
+	for _, code := range i.Amenities {
+		if !amenity.ValidCode(code) {
+			invalidItem.Name = "amenities"
+			invalidItem.Reason = `amenities should be amenity codes like pet-friendly`
 			validationErr = append(validationErr, invalidItem)
+			break
 		}
 	}
 	return validationErr
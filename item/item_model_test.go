@@ -1,7 +1,10 @@
 package item
 
 import (
+	"net/url"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestValidateRequiredItem(t *testing.T) {
@@ -46,3 +49,211 @@ func TestValidateFields(t *testing.T) {
 	}
 
 }
+
+func TestValidateFieldsCoordinates(t *testing.T) {
+	lat, lng := 91.0, 76.2
+	item := Item{Location: Location{Latitude: &lat, Longitude: &lng}}
+	validateErr := item.ValidateFields()
+	if len(validateErr) != 1 || validateErr[0].Name != "latitude" {
+		t.Errorf("Expected latitude got %v", validateErr)
+	}
+	item.Location.Latitude = nil
+	validateErr = item.ValidateFields()
+	if len(validateErr) != 1 || validateErr[0].Name != "location" {
+		t.Errorf("Expected location got %v", validateErr)
+	}
+}
+
+func TestValidateFieldsZipCode(t *testing.T) {
+	valid := map[string]string{"GB": "SW1A 1AA", "Canada": "k1a 0b1", "netherlands": "1012 AB", "Germany": "01067", "US": "02134-1234", "India": "680001", "atlantis": "AB-12"}
+	for country, zip := range valid {
+		item := Item{Location: Location{Country: country, ZipCode: zip}}
+		if validateErr := item.ValidateFields(); len(validateErr) != 0 {
+			t.Errorf("Expected %s to be a zip code of %s got %v", zip, country, validateErr)
+		}
+	}
+	invalid := map[string]string{"DE": "1067", "india": "68001", "NL": "0123 AB", "GB": "12345", "atlantis": "#1"}
+	for country, zip := range invalid {
+		item := Item{Location: Location{Country: country, ZipCode: zip}}
+		if validateErr := item.ValidateFields(); len(validateErr) != 1 || validateErr[0].Name != "zip code" {
+			t.Errorf("Expected %s not to be a zip code of %s got %v", zip, country, validateErr)
+		}
+	}
+}
+
+func TestValidateFieldsAmenities(t *testing.T) {
+	item := Item{Amenities: []string{"wifi", "pet-friendly"}}
+	if validateErr := item.ValidateFields(); len(validateErr) != 0 {
+		t.Errorf("Expected no error got %v", validateErr)
+	}
+	item.Amenities = []string{"wifi", "Free WiFi"}
+	if validateErr := item.ValidateFields(); len(validateErr) != 1 || validateErr[0].Name != "amenities" {
+		t.Errorf("Expected amenities got %v", validateErr)
+	}
+}
+
+func TestValidateFieldsCurrency(t *testing.T) {
+	item := Item{Currency: "JPY"}
+	if validateErr := item.ValidateFields(); len(validateErr) != 0 {
+		t.Errorf("Expected no error got %v", validateErr)
+	}
+	item.Currency = "yen"
+	if validateErr := item.ValidateFields(); len(validateErr) != 1 || validateErr[0].Name != "currency" {
+		t.Errorf("Expected currency got %v", validateErr)
+	}
+}
+
+func TestNewPriceCurrency(t *testing.T) {
+	if code, validateErr := NewPriceCurrency(url.Values{"currency": {"usd"}}); code != "USD" || len(validateErr) != 0 {
+		t.Errorf("Expected USD got %s %v", code, validateErr)
+	}
+	if code, validateErr := NewPriceCurrency(url.Values{}); code != "" || len(validateErr) != 0 {
+		t.Errorf("Expected no currency got %s %v", code, validateErr)
+	}
+	if _, validateErr := NewPriceCurrency(url.Values{"currency": {"XYZ"}}); len(validateErr) != 1 || validateErr[0].Name != "currency" {
+		t.Errorf("Expected currency got %v", validateErr)
+	}
+}
+
+func TestNewItemFilterAmenities(t *testing.T) {
+	filter, validateErr := NewItemFilter(url.Values{"amenities": {"wifi, pool,,wifi"}, "amenities_match": {"any"}})
+	if len(validateErr) != 0 {
+		t.Errorf("Expected no error got %v", validateErr)
+	}
+	if !reflect.DeepEqual(filter.Amenities, []string{"wifi", "pool"}) || !filter.AnyAmenity {
+		t.Errorf("Expected any of [wifi pool] got %v %v", filter.Amenities, filter.AnyAmenity)
+	}
+	_, validateErr = NewItemFilter(url.Values{"amenities": {"wifi,Pool"}, "amenities_match": {"some"}})
+	if len(validateErr) != 2 || validateErr[0].Name != "amenities" || validateErr[1].Name != "amenities_match" {
+		t.Errorf("Expected amenities and amenities_match got %v", validateErr)
+	}
+}
+
+func TestNewItemFilterPriceCurrency(t *testing.T) {
+	filter, validateErr := NewItemFilter(url.Values{"min_price": {"500"}})
+	if len(validateErr) != 0 || filter.PriceCurrency != "EUR" {
+		t.Errorf("Expected EUR got %s %v", filter.PriceCurrency, validateErr)
+	}
+	filter, validateErr = NewItemFilter(url.Values{"sort": {"-price"}})
+	if len(validateErr) != 0 || filter.PriceCurrency != "EUR" {
+		t.Errorf("Expected EUR got %s %v", filter.PriceCurrency, validateErr)
+	}
+	filter, validateErr = NewItemFilter(url.Values{"sort": {"name"}})
+	if len(validateErr) != 0 || filter.PriceCurrency != "" {
+		t.Errorf("Expected no price currency got %s %v", filter.PriceCurrency, validateErr)
+	}
+	_, validateErr = NewItemFilter(url.Values{"max_price": {"9000"}, "price_currency": {"XYZ"}})
+	if len(validateErr) != 1 || validateErr[0].Name != "price_currency" {
+		t.Errorf("Expected price_currency got %v", validateErr)
+	}
+	filter, validateErr = NewItemFilter(url.Values{"max_price": {"9000"}, "sort": {"price"}, "price_currency": {"jpy"}})
+	if len(validateErr) != 0 || filter.PriceCurrency != "JPY" {
+		t.Errorf("Expected JPY got %s %v", filter.PriceCurrency, validateErr)
+	}
+}
+
+func TestValidateBooking(t *testing.T) {
+	checkIn := time.Now().UTC().AddDate(0, 0, 1)
+	booking := BookAccommodation{
+		PersonName: "SVR",
+		NoOfRooms:  2,
+		CheckIn:    checkIn.Format("2006-01-02"),
+		CheckOut:   checkIn.AddDate(0, 0, 3).Format("2006-01-02"),
+	}
+	if invalidFields := booking.Validate(); len(invalidFields) != 0 {
+		t.Errorf("Expected no errors got %v", invalidFields)
+	}
+	if booking.Nights() != 3 {
+		t.Errorf("Expected 3 nights got %d", booking.Nights())
+	}
+}
+
+func TestValidateBookingGuests(t *testing.T) {
+	checkIn := time.Now().UTC().AddDate(0, 0, 1)
+	booking := BookAccommodation{
+		RoomTypeID: 2,
+		PersonName: "SVR",
+		NoOfRooms:  3,
+		Guests:     2,
+		CheckIn:    checkIn.Format("2006-01-02"),
+		CheckOut:   checkIn.AddDate(0, 0, 1).Format("2006-01-02"),
+	}
+	invalidFields := booking.Validate()
+	if len(invalidFields) != 1 || invalidFields[0].Name != "guests" {
+		t.Errorf("Expected guests got %v", invalidFields)
+	}
+}
+
+func TestValidateBookingDates(t *testing.T) {
+	booking := BookAccommodation{
+		PersonName: "SVR",
+		NoOfRooms:  2,
+		CheckIn:    "2000-01-10",
+		CheckOut:   "2000-01-10",
+	}
+	invalidFields := booking.Validate()
+	if len(invalidFields) != 2 {
+		t.Fatalf("Expected 2 got %d", len(invalidFields))
+	}
+	if invalidFields[0].Name != "check_in" {
+		t.Errorf("Expected check_in got %s", invalidFields[0].Name)
+	}
+	if invalidFields[1].Name != "check_out" {
+		t.Errorf("Expected check_out got %s", invalidFields[1].Name)
+	}
+	booking.CheckOut = "10-01-2000"
+	invalidFields = booking.Validate()
+	if len(invalidFields) != 1 || invalidFields[0].Name != "check_out" {
+		t.Errorf("Expected check_out format error got %v", invalidFields)
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	item := Item{ID: 1, Name: "hotel abcd", Availability: 10, Price: 5000, Location: Location{City: "tsr", Country: "india"}}
+	patched, invalidFields, err := item.ApplyMergePatch([]byte(`{"availability": 0, "location": {"city": "kochi"}, "id": 7}`))
+	if err != nil || len(invalidFields) != 0 {
+		t.Fatalf("Expected no errors got %v %v", err, invalidFields)
+	}
+	if patched.Availability != 0 {
+		t.Errorf("Expected availability 0 got %d", patched.Availability)
+	}
+	if patched.Location.City != "kochi" || patched.Location.Country != "india" {
+		t.Errorf("Expected location to be merged got %+v", patched.Location)
+	}
+	if patched.ID != 1 || patched.Name != "hotel abcd" || patched.Price != 5000 {
+		t.Errorf("Expected untouched fields to be kept got %+v", patched)
+	}
+}
+
+func TestApplyMergePatchNull(t *testing.T) {
+	item := Item{ID: 1, Name: "hotel abcd"}
+	_, invalidFields, err := item.ApplyMergePatch([]byte(`{"name": null, "location": {"city": null}}`))
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(invalidFields) != 2 || invalidFields[0].Name != "location.city" || invalidFields[1].Name != "name" {
+		t.Errorf("Expected location.city and name got %v", invalidFields)
+	}
+}
+
+func TestApplyMergePatchInvalid(t *testing.T) {
+	item := Item{ID: 1}
+	for _, doc := range []string{`[]`, `{"price": "cheap"}`, `{"unknown": 1}`, `{`} {
+		if _, _, err := item.ApplyMergePatch([]byte(doc)); err == nil {
+			t.Errorf("Expected error for %s", doc)
+		}
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	etag := Item{Version: 3}.ETag()
+	if !matchETag(`"2", "3"`, etag, false) || !matchETag("*", etag, false) {
+		t.Errorf("Expected %s to match", etag)
+	}
+	if matchETag(`W/"3"`, etag, false) {
+		t.Errorf("Expected a weak tag not to match strongly")
+	}
+	if !matchETag(`W/"3"`, etag, true) {
+		t.Errorf("Expected a weak tag to match weakly")
+	}
+}
@@ -3,19 +3,118 @@ package item
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/lib/pq"
+	"github.com/sayooj/trivago/currency"
 	"github.com/sayooj/trivago/utils"
 )
 
 //ItemsRepositoryInterface interface
 type ItemsRepositoryInterface interface {
-	AddItem(ctx context.Context, p Item) error
-	DeleteItem(ctx context.Context, id int) error
+	AddItem(ctx context.Context, p Item) (Item, error)
+	AddItems(ctx context.Context, items []Item) ([]Item, error)
+	DeleteItem(ctx context.Context, id int, version uint64) error
 	GetItem(ctx context.Context, id int) (Item, error)
-	UpdateItem(ctx context.Context, item Item) error
-	GetItems(ctx context.Context) ([]Item, error)
-	BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) error
+	ItemExists(ctx context.Context, id int) (bool, error)
+	UpdateItem(ctx context.Context, item Item) (Item, error)
+	RestoreItem(ctx context.Context, id int) error
+	PurgeItem(ctx context.Context, id int) error
+	GetItemHistory(ctx context.Context, id int) ([]AuditEntry, error)
+	GetItems(ctx context.Context, filter ItemFilter) (ItemList, error)
+	SearchItems(ctx context.Context, search ItemSearch) (SearchResults, error)
+	NearbyItems(ctx context.Context, search NearbySearch) (NearbyResults, error)
+	ExportItems(ctx context.Context, filter ItemFilter, each func(Item) error) error
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	UnknownAmenities(ctx context.Context, codes []string) ([]string, error)
+	BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) (Booking, error)
+}
+
+// reputationBadgeColumn derives the badge of an item from its reputation
+const reputationBadgeColumn = `
+		CASE
+			WHEN item.reputation <= 500 THEN 'red'
+			WHEN item.reputation <= 799 THEN 'yellow'
+        	ELSE 'green'
+		END AS reputation_badge`
+
+// itemSelectColumns are the columns scanItem reads
+const itemSelectColumns = `
+	SELECT
+		item.item_id,
+		item.name,
+		item.rating,
+		item.category,
+		item.reputation,` + reputationBadgeColumn + `,
+		item.review_count,
+		item.price,
+		item.currency,
+		item.availability,
+		item.image,
+		item_location.city,
+		item_location.state,
+		item_location.country,
+		item_location.zip_code,
+		item_location.address,
+		item_location.latitude,
+		item_location.longitude,
+		COALESCE(item_location.city_mismatch, FALSE),
+		item.version,
+		ARRAY(
+			SELECT amenity.code FROM item_amenity JOIN amenity ON amenity.id = item_amenity.amenity_id
+			WHERE item_amenity.item_id = item.item_id ORDER BY amenity.code
+		) AS amenities`
+
+const itemSelectFrom = `
+	FROM
+		item
+	LEFT JOIN
+		item_location
+	ON
+		item.item_id = item_location.item_id
+	`
+
+const itemSelectQuery = itemSelectColumns + itemSelectFrom
+
+// haversineDistance is the great circle distance in km between the item and the point $%[1]d, $%[2]d,
+// %[3]v is the radius of the earth
+const haversineDistance = `(2 * %[3]v * ASIN(LEAST(1, SQRT(
+		POWER(SIN(RADIANS(item_location.latitude - $%[1]d) / 2), 2) +
+		COS(RADIANS($%[1]d)) * COS(RADIANS(item_location.latitude)) * POWER(SIN(RADIANS(item_location.longitude - $%[2]d) / 2), 2)))))`
+
+// nights are added to the inventory the first time they are booked, with the item availability or the room
+// type inventory as room count. $1 is the item or the room type
+const (
+	itemInventoryQuery = `
+	INSERT INTO item_inventory(item_id, date, rooms_total, rooms_booked)
+	SELECT item.item_id, night::date, item.availability, 0
+	FROM item, generate_series($2::date, $3::date - 1, interval '1 day') AS night
+	WHERE item.item_id = $1
+	ON CONFLICT (item_id, date) DO NOTHING;`
+	roomTypeInventoryQuery = `
+	INSERT INTO room_type_inventory(room_type_id, date, rooms_total, rooms_booked)
+	SELECT room_type.id, night::date, room_type.inventory, 0
+	FROM room_type, generate_series($2::date, $3::date - 1, interval '1 day') AS night
+	WHERE room_type.id = $1
+	ON CONFLICT (room_type_id, date) DO NOTHING;`
+)
+
+// the rooms are reserved only on the nights that still have enough of them
+const (
+	itemReserveQuery = `
+	UPDATE item_inventory SET rooms_booked = rooms_booked + $4
+	WHERE item_id = $1 AND date >= $2 AND date < $3 AND rooms_total - rooms_booked >= $4;`
+	roomTypeReserveQuery = `
+	UPDATE room_type_inventory SET rooms_booked = rooms_booked + $4
+	WHERE room_type_id = $1 AND date >= $2 AND date < $3 AND rooms_total - rooms_booked >= $4;`
+)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
 //ItemsRepository struct
@@ -23,81 +122,284 @@ type ItemsRepository struct {
 	db *sql.DB
 }
 
-//AddItem adds a Item to db
-func (r *ItemsRepository) AddItem(ctx context.Context, item Item) error {
-	tx, err := r.db.Begin()
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txn is a transaction the repository writes in
+type txn interface {
+	queryer
+	Commit() error
+	Rollback() error
+}
+
+type txKey struct{}
+
+// joinedTx is a transaction started by WithTransaction, only WithTransaction commits or rolls it back
+type joinedTx struct {
+	*sql.Tx
+}
+
+func (joinedTx) Commit() error {
+	return nil
+}
+
+func (joinedTx) Rollback() error {
+	return nil
+}
+
+//WithTransaction runs fn in a single transaction, the repository calls made with the context given to fn
+//join it. It is committed when fn returns nil and rolled back otherwise
+func (r *ItemsRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit transaction %w", utils.ErrTransactionCommitFailed)
+	}
+	return nil
+}
+
+// begin starts a transaction, or joins the one of WithTransaction
+func (r *ItemsRepository) begin(ctx context.Context) (txn, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return joinedTx{tx}, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// conn returns the transaction of WithTransaction, or the db outside of one
+func (r *ItemsRepository) conn(ctx context.Context) queryer {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return r.db
+}
+
+//AddItem adds a Item to db and returns it with the generated id and badge
+func (r *ItemsRepository) AddItem(ctx context.Context, item Item) (Item, error) {
+	created, err := r.AddItems(ctx, []Item{item})
+	if err != nil {
+		return Item{}, err
+	}
+	return created[0], nil
+}
+
+//AddItems adds Items to db in a single transaction, either all of them are stored or none
+func (r *ItemsRepository) AddItems(ctx context.Context, items []Item) ([]Item, error) {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
 	defer func() {
 		if err != nil {
+			// rolling back if error occured
 			tx.Rollback()
 		}
 	}()
+	created := make([]Item, 0, len(items))
+	for _, item := range items {
+		item, err = insertItem(ctx, tx, item)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, item)
+	}
+	err = tx.Commit()
 	if err != nil {
-		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+		return nil, fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
+	}
+	return created, nil
+}
+
+// insertItem inserts an item with its location and history inside tx
+func insertItem(ctx context.Context, tx queryer, item Item) (Item, error) {
+	if item.Currency == "" {
+		item.Currency = currency.DefaultCurrency
 	}
-	itemQuery := `INSERT INTO item(name, rating, category, image, reputation , price , availability) VALUES($1 , $2 , $3 , $4 , $5 , $6 ,$7) RETURNING item_id`
-	err = tx.QueryRowContext(ctx, itemQuery, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Availability).Scan(&item.ID)
+	itemQuery := `INSERT INTO item(name, rating, category, image, reputation , price , currency , availability) VALUES($1 , $2 , $3 , $4 , $5 , $6 , $7 ,$8) RETURNING item_id, version,` + reputationBadgeColumn
+	err := tx.QueryRowContext(ctx, itemQuery, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Currency, item.Availability).Scan(&item.ID, &item.Version, &item.ReputationBadge)
 	if err != nil {
-		return fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
+		return Item{}, fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
 	}
-	locationQry := `INSERT INTO item_location(item_id , city, state, country, zip_code, address ) VALUES($1 , $2 , $3 , $4 , $5 , $6 )`
-	result, err := tx.ExecContext(ctx, locationQry, item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address)
+	locationQry := `INSERT INTO item_location(item_id , city, state, country, zip_code, address, latitude, longitude, city_mismatch ) VALUES($1 , $2 , $3 , $4 , $5 , $6 , $7 , $8 , $9 )`
+	result, err := tx.ExecContext(ctx, locationQry, item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address, item.Location.Latitude, item.Location.Longitude, item.Location.CityMismatch)
 	if err != nil {
-		return fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
+		return Item{}, fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
 	}
 	rows, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
+		return Item{}, fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
 	}
 	if rows == 0 {
-		return fmt.Errorf("Error occured during insertion %w", sql.ErrNoRows)
+		return Item{}, fmt.Errorf("Error occured during insertion %w", utils.ErrItemNotAdded)
 	}
-	tx.Commit()
-	return nil
+	// an item without an image starts with an empty gallery
+	if item.Image != "" {
+		_, err = tx.ExecContext(ctx, primaryImageUpsert, item.ID, item.Image)
+		if err != nil {
+			return Item{}, fmt.Errorf("Error occured while saving the image %w", utils.ErrItemNotAdded)
+		}
+	}
+	item.Amenities, err = saveItemAmenities(ctx, tx, item.ID, item.Amenities)
+	if errors.Is(err, utils.ErrUnknownAmenity) {
+		return Item{}, err
+	}
+	if err != nil {
+		return Item{}, fmt.Errorf("Error occured while saving the amenities %w", utils.ErrItemNotAdded)
+	}
+	err = recordAudit(ctx, tx, item.ID, auditActionCreate, nil, item)
+	if err != nil {
+		return Item{}, fmt.Errorf("Error occured while recording the history %w", utils.ErrItemNotAdded)
+	}
+	return item, nil
 }
 
-//DeleteItem delete Item from db
-func (r *ItemsRepository) DeleteItem(ctx context.Context, id int) error {
-	query := "DELETE FROM item WHERE item_id=$1"
-	_, err := r.db.ExecContext(ctx, query, id)
+//DeleteItem soft deletes a Item, it is kept with its bookings until it is purged. A non zero
+//version deletes it only while it is still at that version
+func (r *ItemsRepository) DeleteItem(ctx context.Context, id int, version uint64) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	before, err := lockItem(ctx, tx, id, "AND item.deleted_at IS NULL")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		}
+		return fmt.Errorf("Error occured while fetching the Item %w", utils.ErrItemNotDeleted)
+	}
+	if version != 0 && version != before.Version {
+		err = utils.ErrItemVersionMismatch
+		return fmt.Errorf("Item has been modified %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE item SET deleted_at = NOW(), version = version + 1 WHERE item_id=$1", id)
 	if err != nil {
 		return fmt.Errorf("Failed to delete product %w", utils.ErrItemNotDeleted)
 	}
+	err = recordAudit(ctx, tx, before.ID, auditActionDelete, before, nil)
+	if err != nil {
+		return fmt.Errorf("Error occured while recording the history %w", utils.ErrItemNotDeleted)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Failed to delete product %w", utils.ErrItemNotDeleted)
+	}
+	return nil
+}
+
+//RestoreItem brings back a soft deleted Item
+func (r *ItemsRepository) RestoreItem(ctx context.Context, id int) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	item, err := lockItem(ctx, tx, id, "AND item.deleted_at IS NOT NULL")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Deleted item not found %w", utils.ErrItemNotFound)
+		}
+		return fmt.Errorf("Error occured while fetching the Item %w", utils.ErrItemNotUpdated)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE item SET deleted_at = NULL, version = version + 1 WHERE item_id=$1", id)
+	if err != nil {
+		return fmt.Errorf("Failed to restore product %w", utils.ErrItemNotUpdated)
+	}
+	err = recordAudit(ctx, tx, item.ID, auditActionRestore, nil, item)
+	if err != nil {
+		return fmt.Errorf("Error occured while recording the history %w", utils.ErrItemNotUpdated)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Failed to restore product %w", utils.ErrItemNotUpdated)
+	}
+	return nil
+}
+
+//PurgeItem removes a Item from db for good, its past bookings and history are kept without the item.
+//Items with pending, confirmed or checked in bookings can not be purged
+func (r *ItemsRepository) PurgeItem(ctx context.Context, id int) error {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	// the lock keeps new bookings out until the item is gone
+	before, err := lockItem(ctx, tx, id, "")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		}
+		return fmt.Errorf("Error occured while fetching the Item %w", utils.ErrItemNotDeleted)
+	}
+
+	var active bool
+	activeQry := `SELECT EXISTS (SELECT 1 FROM item_booking WHERE item_id = $1 AND status = ANY($2));`
+	err = tx.QueryRowContext(ctx, activeQry, id, pq.Array(activeBookingStatuses)).Scan(&active)
+	if err != nil {
+		return fmt.Errorf("Error occured while fetching the bookings %w", utils.ErrItemNotDeleted)
+	}
+	if active {
+		err = utils.ErrItemHasActiveBookings
+		return fmt.Errorf("Item can not be purged %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM item WHERE item_id = $1;`, id)
+	if err != nil {
+		return fmt.Errorf("Failed to purge product %w", utils.ErrItemNotDeleted)
+	}
+	err = recordAudit(ctx, tx, before.ID, auditActionPurge, before, nil)
+	if err != nil {
+		return fmt.Errorf("Error occured while recording the history %w", utils.ErrItemNotDeleted)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Failed to purge product %w", utils.ErrItemNotDeleted)
+	}
 	return nil
 }
 
 //GetItem gets a Item based on id
 func (r *ItemsRepository) GetItem(ctx context.Context, id int) (Item, error) {
-	var item Item
-	query := `
-	SELECT
-		item.item_id,
-		item.name,
-		item.rating,
-		item.category,
-		item.reputation,
-		CASE
-			WHEN item.reputation <= 500 THEN 'red'
-			WHEN item.reputation <= 799 THEN 'yellow'
-        	ELSE 'green'
-		END AS reputation_badge,
-		item.price,
-		item.availability,
-		item.image,
-		item_location.city,
-		item_location.state,
-		item_location.country,
-		item_location.zip_code,
-		item_location.address
-	FROM
-		item
-	INNER JOIN
-		item_location
-	ON 
-		item.item_id = item_location.item_id
+	query := itemSelectQuery + `
 	WHERE
-		item.item_id = $1
+		item.item_id = $1 AND item.deleted_at IS NULL
 	`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&item.ID, &item.Name, &item.Rating, &item.Category, &item.Reputation, &item.ReputationBadge, &item.Price, &item.Availability, &item.Image, &item.Location.City, &item.Location.State, &item.Location.Country, &item.Location.ZipCode, &item.Location.Address)
+	item, err := scanItem(r.conn(ctx).QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Item{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
@@ -107,109 +409,564 @@ func (r *ItemsRepository) GetItem(ctx context.Context, id int) (Item, error) {
 	return item, nil
 }
 
-//UpdateItem  updates a Item with sku code
-func (r *ItemsRepository) UpdateItem(ctx context.Context, item Item) error {
-	tx, err := r.db.Begin()
+//ItemExists tells whether an item is stored, deleted items included
+func (r *ItemsRepository) ItemExists(ctx context.Context, id int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM item WHERE item_id = $1);`
+	if err := r.conn(ctx).QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("Failed to fetch Item%w", utils.ErrFetchError)
+	}
+	return exists, nil
+}
+
+//UpdateItem updates a Item and returns it with its new version, a non zero item.Version updates
+//it only while it is still at that version
+func (r *ItemsRepository) UpdateItem(ctx context.Context, item Item) (Item, error) {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return Item{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
 	defer func() {
 		if err != nil {
 			// rolling back if error occured
 			tx.Rollback()
 		}
 	}()
+
+	before, err := lockItem(ctx, tx, int(item.ID), "AND item.deleted_at IS NULL")
 	if err != nil {
-		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+		if err == sql.ErrNoRows {
+			return Item{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		}
+		return Item{}, fmt.Errorf("Error occured while fetching the Item %w", utils.ErrItemNotUpdated)
+	}
+	if item.Version != 0 && item.Version != before.Version {
+		err = utils.ErrItemVersionMismatch
+		return Item{}, fmt.Errorf("Item has been modified %w", err)
 	}
 
-	// update item details
-	itemQry := `UPDATE item SET name = $2, rating = $3, category=$4 , image =$5 , reputation =$6 , price=$7 , availability = $8 WHERE item_id = $1;`
-	_, err = tx.ExecContext(ctx, itemQry, item.ID, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Availability)
+	// update item details, an update without a currency keeps it and the reputation of a reviewed item is
+	// kept as computed from its reviews
+	if item.Currency == "" {
+		item.Currency = before.Currency
+	}
+	itemQry := `UPDATE item SET name = $2, rating = $3, category=$4 , image =$5 , reputation = CASE WHEN review_count > 0 THEN reputation ELSE $6 END ,
+		price=$7 , currency = $8 , availability = $9, version = version + 1
+		WHERE item_id = $1 RETURNING version, reputation, review_count,` + reputationBadgeColumn
+	err = tx.QueryRowContext(ctx, itemQry, item.ID, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Currency, item.Availability).Scan(&item.Version, &item.Reputation, &item.ReviewCount, &item.ReputationBadge)
 	if err != nil {
-		return fmt.Errorf("Error occured while updating the Item %w", utils.ErrItemNotUpdated)
+		return Item{}, fmt.Errorf("Error occured while updating the Item %w", utils.ErrItemNotUpdated)
 	}
 
 	// update location
-	locationQry := `UPDATE item_location SET city = $2, state = $3, country=$4 , zip_code =$5 , address =$6  WHERE item_id = $1;`
-	_, err = tx.ExecContext(ctx, locationQry, item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address)
+	locationQry := `UPDATE item_location SET city = $2, state = $3, country=$4 , zip_code =$5 , address =$6 , latitude = $7 , longitude = $8 , city_mismatch = $9  WHERE item_id = $1;`
+	_, err = tx.ExecContext(ctx, locationQry, item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address, item.Location.Latitude, item.Location.Longitude, item.Location.CityMismatch)
 	if err != nil {
-		return fmt.Errorf("Error occured while updating the Item %w", utils.ErrItemNotUpdated)
+		return Item{}, fmt.Errorf("Error occured while updating the Item %w", utils.ErrItemNotUpdated)
 	}
 
-	tx.Commit()
-	return nil
+	// the image of the item is the primary image of its gallery, an empty image leaves the gallery as it is
+	if item.Image != "" {
+		_, err = tx.ExecContext(ctx, primaryImageUpsert, item.ID, item.Image)
+		if err != nil {
+			return Item{}, fmt.Errorf("Error occured while updating the image %w", utils.ErrItemNotUpdated)
+		}
+	}
+
+	// amenities left out of the update are kept
+	if item.Amenities == nil {
+		item.Amenities = before.Amenities
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM item_amenity WHERE item_id = $1;`, item.ID)
+		if err != nil {
+			return Item{}, fmt.Errorf("Error occured while updating the amenities %w", utils.ErrItemNotUpdated)
+		}
+		item.Amenities, err = saveItemAmenities(ctx, tx, item.ID, item.Amenities)
+		if errors.Is(err, utils.ErrUnknownAmenity) {
+			return Item{}, err
+		}
+		if err != nil {
+			return Item{}, fmt.Errorf("Error occured while updating the amenities %w", utils.ErrItemNotUpdated)
+		}
+	}
+
+	// the availability is the room count of every upcoming night, it can not drop below the rooms already booked
+	inventoryQry := `UPDATE item_inventory SET rooms_total = GREATEST($2, rooms_booked) WHERE item_id = $1 AND date >= CURRENT_DATE;`
+	_, err = tx.ExecContext(ctx, inventoryQry, item.ID, item.Availability)
+	if err != nil {
+		return Item{}, fmt.Errorf("Error occured while updating the inventory %w", utils.ErrItemNotUpdated)
+	}
+
+	err = recordAudit(ctx, tx, item.ID, auditActionUpdate, before, item)
+	if err != nil {
+		return Item{}, fmt.Errorf("Error occured while recording the history %w", utils.ErrItemNotUpdated)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return Item{}, fmt.Errorf("Error occured while updating the Item %w", utils.ErrItemNotUpdated)
+	}
+	return item, nil
 }
 
-//GetItems with limits
-func (r *ItemsRepository) GetItems(ctx context.Context) ([]Item, error) {
-	query := `
-	SELECT
-		item.item_id,
-		item.name,
-		item.rating,
-		item.category,
-		item.reputation,
-		CASE
-			WHEN item.reputation <= 500 THEN 'red'
-			WHEN item.reputation <= 799 THEN 'yellow'
-        	ELSE 'green'
-		END AS reputation_badge,
-		item.price,
-		item.availability,
-		item.image,
-		item_location.city,
-		item_location.state,
-		item_location.country,
-		item_location.zip_code,
-		item_location.address
-	FROM
-		item
-	LEFT JOIN
-		item_location
-	ON 
-		item.item_id = item_location.item_id;
-	`
-	rows, err := r.db.QueryContext(ctx, query)
+//GetItems returns a page of items matching the filter
+func (r *ItemsRepository) GetItems(ctx context.Context, filter ItemFilter) (ItemList, error) {
+	conditions, args := itemsFilterConditions(filter)
+
+	// the total is counted for the first page only, a count on every page would scan the whole listing
+	var total *int
+	if filter.Cursor == nil {
+		var count int
+		countQuery := `SELECT COUNT(*) FROM item LEFT JOIN item_location ON item.item_id = item_location.item_id` + whereClause(conditions)
+		if err := r.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&count); err != nil {
+			return ItemList{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+		}
+		total = &count
+	}
+
+	if filter.Cursor != nil {
+		var condition string
+		condition, args = itemsCursorCondition(filter.Sort, *filter.Cursor, args)
+		conditions = append(conditions, condition)
+	}
+	// one extra row tells whether there is a next page
+	query := itemSelectQuery + whereClause(conditions) + itemsOrderClause(filter.Sort) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit+1, filter.Offset)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return ItemList{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	items := []Item{}
+	for rows.Next() {
+		i, err := scanItem(rows)
+		if err != nil {
+			return ItemList{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return ItemList{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+	}
+	list := ItemList{
+		Items: items,
+		Meta:  ListMeta{Total: total, Limit: filter.Limit, Offset: filter.Offset},
+	}
+	if len(items) > filter.Limit {
+		list.Items = items[:filter.Limit]
+		list.NextCursor = NewItemCursor(filter.Sort, list.Items[filter.Limit-1]).Encode()
+	}
+	return list, nil
+}
+
+//SearchItems returns a page of the items matching the words of the search, best match first. When no item
+//matches, the items with a name or city spelled like the search are returned instead, to get past typos
+func (r *ItemsRepository) SearchItems(ctx context.Context, search ItemSearch) (SearchResults, error) {
+	results, err := r.searchItems(ctx, search,
+		"item.search_vector @@ websearch_to_tsquery('"+searchConfig+"', $%[1]d)",
+		"ts_rank_cd(item.search_vector, websearch_to_tsquery('"+searchConfig+"', $%[1]d))")
+	if err != nil || *results.Meta.Total > 0 {
+		return results, err
+	}
+	results, err = r.searchItems(ctx, search,
+		"($%[1]d <%% item.name OR $%[1]d <%% item_location.city)",
+		"GREATEST(word_similarity($%[1]d, item.name), word_similarity($%[1]d, item_location.city))")
+	if err != nil {
+		return SearchResults{}, err
+	}
+	results.Fuzzy = true
+	return results, nil
+}
+
+// searchItems returns the page of items matching condition ordered by rank, both are formats where $%[1]d is
+// the search text
+func (r *ItemsRepository) searchItems(ctx context.Context, search ItemSearch, condition, rank string) (SearchResults, error) {
+	conditions, args := itemsFilterConditions(search.Filter)
+	args = append(args, search.Query)
+	conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM item LEFT JOIN item_location ON item.item_id = item_location.item_id` + whereClause(conditions)
+	if err := r.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return SearchResults{}, fmt.Errorf("Error occured while searching record%w", utils.ErrFetchError)
+	}
+
+	query := itemSelectQuery + whereClause(conditions) + " ORDER BY " + fmt.Sprintf(rank, len(args)) + " DESC, item.item_id" +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, search.Filter.Limit, search.Filter.Offset)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
-		return []Item{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+		return SearchResults{}, fmt.Errorf("Error occured while searching record%w", utils.ErrFetchError)
 	}
 	defer rows.Close()
 	items := []Item{}
 	for rows.Next() {
-		var i Item
-		if err := rows.Scan(&i.ID, &i.Name, &i.Rating, &i.Category, &i.Reputation, &i.ReputationBadge, &i.Price, &i.Availability, &i.Image, &i.Location.City, &i.Location.State, &i.Location.Country, &i.Location.ZipCode, &i.Location.Address); err != nil {
-			return nil, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+		i, err := scanItem(rows)
+		if err != nil {
+			return SearchResults{}, fmt.Errorf("Error occured while searching record%w", utils.ErrFetchError)
 		}
 		items = append(items, i)
 	}
-	return items, nil
+	if err := rows.Err(); err != nil {
+		return SearchResults{}, fmt.Errorf("Error occured while searching record%w", utils.ErrFetchError)
+	}
+	return SearchResults{ItemList: ItemList{
+		Items: items,
+		Meta:  ListMeta{Total: &total, Limit: search.Filter.Limit, Offset: search.Filter.Offset},
+	}}, nil
+}
+
+//NearbyItems returns a page of the items within the radius of the search, nearest first. Items
+//without coordinates are left out
+func (r *ItemsRepository) NearbyItems(ctx context.Context, search NearbySearch) (NearbyResults, error) {
+	conditions, args := itemsFilterConditions(search.Filter)
+	args = append(args, search.Latitude, search.Longitude)
+	distance := fmt.Sprintf(haversineDistance, len(args)-1, len(args), earthRadiusKm)
+	// the bounding box lets the coordinates index narrow the items before the distance is computed
+	minLat, maxLat, minLng, maxLng, allLongitudes := search.BoundingBox()
+	args = append(args, minLat, maxLat)
+	conditions = append(conditions, fmt.Sprintf("item_location.latitude BETWEEN $%d AND $%d", len(args)-1, len(args)))
+	if allLongitudes {
+		conditions = append(conditions, "item_location.longitude IS NOT NULL")
+	} else {
+		args = append(args, minLng, maxLng)
+		conditions = append(conditions, fmt.Sprintf("item_location.longitude BETWEEN $%d AND $%d", len(args)-1, len(args)))
+	}
+	args = append(args, search.RadiusKm)
+	conditions = append(conditions, fmt.Sprintf("%s <= $%d", distance, len(args)))
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM item LEFT JOIN item_location ON item.item_id = item_location.item_id` + whereClause(conditions)
+	if err := r.conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return NearbyResults{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+	}
+
+	query := itemSelectColumns + ", " + distance + " AS distance_km" + itemSelectFrom + whereClause(conditions) +
+		fmt.Sprintf(" ORDER BY distance_km, item.item_id LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, search.Filter.Limit, search.Filter.Offset)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return NearbyResults{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	items := []NearbyItem{}
+	for rows.Next() {
+		var distanceKm float64
+		i, err := scanItem(rows, &distanceKm)
+		if err != nil {
+			return NearbyResults{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+		}
+		items = append(items, NearbyItem{Item: i, DistanceKm: distanceKm})
+	}
+	if err := rows.Err(); err != nil {
+		return NearbyResults{}, fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+	}
+	return NearbyResults{
+		Items: items,
+		Meta:  ListMeta{Total: &total, Limit: search.Filter.Limit, Offset: search.Filter.Offset},
+	}, nil
+}
+
+//ExportItems calls each for every item matching the filter, in the order of filter.Sort. Rows are
+//scanned one at a time from the cursor, so memory does not grow with the number of items. Paging is ignored
+func (r *ItemsRepository) ExportItems(ctx context.Context, filter ItemFilter, each func(Item) error) error {
+	conditions, args := itemsFilterConditions(filter)
+	query := itemSelectQuery + whereClause(conditions) + itemsOrderClause(filter.Sort)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		i, err := scanItem(rows)
+		if err != nil {
+			return fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+		}
+		if err := each(i); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error occured while fetching record%w", utils.ErrFetchError)
+	}
+	return nil
 }
 
-// BookAccommodation book accomodation
-func (r *ItemsRepository) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) error {
-	tx, err := r.db.Begin()
+// BookAccommodation reserves the rooms for every night of the stay, the item
+// row is locked so concurrent bookings of an item are checked one at a time.
+// Items selling room types are booked from the inventory of the room type
+func (r *ItemsRepository) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) (Booking, error) {
+	tx, err := r.begin(ctx)
+	if err != nil {
+		return Booking{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
 	defer func() {
 		if err != nil {
 			// rolling back if error occured
 			tx.Rollback()
 		}
 	}()
+
+	var availability uint
+	var price uint64
+	var priceCurrency string
+	var sellsRoomTypes bool
+	itemQry := `SELECT availability, price, currency, EXISTS (SELECT 1 FROM room_type WHERE room_type.item_id = item.item_id) FROM item WHERE item_id = $1 AND deleted_at IS NULL FOR UPDATE;`
+	err = tx.QueryRowContext(ctx, itemQry, bookingInfo.ItemID).Scan(&availability, &price, &priceCurrency, &sellsRoomTypes)
 	if err != nil {
-		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+		if err == sql.ErrNoRows {
+			return Booking{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		}
+		return Booking{}, fmt.Errorf("Error occured while fetching the Item %w", utils.ErrBookingFailed)
+	}
+	inventoryQry, reserveQry, inventoryID := itemInventoryQuery, itemReserveQuery, bookingInfo.ItemID
+	if bookingInfo.RoomTypeID != 0 {
+		var maxOccupancy uint
+		roomTypeQry := `SELECT inventory, max_occupancy, price FROM room_type WHERE id = $1 AND item_id = $2;`
+		err = tx.QueryRowContext(ctx, roomTypeQry, bookingInfo.RoomTypeID, bookingInfo.ItemID).Scan(&availability, &maxOccupancy, &price)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return Booking{}, fmt.Errorf("Room type not found %w", utils.ErrRoomTypeNotFound)
+			}
+			return Booking{}, fmt.Errorf("Error occured while fetching the room type %w", utils.ErrBookingFailed)
+		}
+		if bookingInfo.Guests > maxOccupancy*bookingInfo.NoOfRooms {
+			err = utils.ErrTooManyGuests
+			return Booking{}, fmt.Errorf("%d rooms take at most %d guests %w", bookingInfo.NoOfRooms, maxOccupancy*bookingInfo.NoOfRooms, err)
+		}
+		inventoryQry, reserveQry, inventoryID = roomTypeInventoryQuery, roomTypeReserveQuery, bookingInfo.RoomTypeID
+	} else if sellsRoomTypes {
+		err = utils.ErrRoomTypeRequired
+		return Booking{}, fmt.Errorf("Item %d sells room types %w", bookingInfo.ItemID, err)
+	}
+	if bookingInfo.NoOfRooms > availability {
+		err = utils.ErrRoomsNotEnough
+		return Booking{}, fmt.Errorf("Rooms not available for the stay %w", err)
 	}
 
-	// update item availability
-	itemQry := `UPDATE item SET availability = availability - $2 WHERE item_id = $1;`
-	_, err = tx.ExecContext(ctx, itemQry, bookingInfo.ItemID, bookingInfo.NoOfRooms)
+	_, err = tx.ExecContext(ctx, inventoryQry, inventoryID, bookingInfo.CheckIn, bookingInfo.CheckOut)
 	if err != nil {
-		return fmt.Errorf("Error occured while updating the Item %w", utils.ErrBookingFailed)
+		return Booking{}, fmt.Errorf("Error occured while updating the inventory %w", utils.ErrBookingFailed)
 	}
-	// creating booking record
-	bookingQry := `INSERT INTO item_booking(item_id , person_name , no_of_rooms) VALUES ($1, $2, $3);`
-	_, err = tx.ExecContext(ctx, bookingQry, bookingInfo.ItemID, bookingInfo.PersonName, bookingInfo.NoOfRooms)
+	result, err := tx.ExecContext(ctx, reserveQry, inventoryID, bookingInfo.CheckIn, bookingInfo.CheckOut, bookingInfo.NoOfRooms)
 	if err != nil {
-		return fmt.Errorf("Error occured while updating the Item %w", utils.ErrBookingFailed)
+		return Booking{}, fmt.Errorf("Error occured while updating the inventory %w", utils.ErrBookingFailed)
 	}
-	tx.Commit()
-	return nil
+	nights, err := result.RowsAffected()
+	if err != nil {
+		return Booking{}, fmt.Errorf("Error occured while updating the inventory %w", utils.ErrBookingFailed)
+	}
+	if nights != int64(bookingInfo.Nights()) {
+		err = utils.ErrRoomsNotEnough
+		return Booking{}, fmt.Errorf("Rooms not available for the stay %w", err)
+	}
+
+	// the booking keeps the total quoted for the stay by the pricing rules of the moment
+	stay := QuoteRequest{ItemID: bookingInfo.ItemID, RoomTypeID: bookingInfo.RoomTypeID, Rooms: bookingInfo.NoOfRooms, CheckIn: bookingInfo.CheckIn, CheckOut: bookingInfo.CheckOut}
+	rules, err := stayPricingRules(ctx, tx, stay)
+	if err != nil {
+		return Booking{}, fmt.Errorf("Error occured while fetching pricing rules %w", utils.ErrBookingFailed)
+	}
+
+	// creating booking record, a new confirmation code is drawn if the code is taken
+	booking := Booking{
+		ItemID:     bookingInfo.ItemID,
+		RoomTypeID: bookingInfo.RoomTypeID,
+		PersonName: bookingInfo.PersonName,
+		NoOfRooms:  bookingInfo.NoOfRooms,
+		Guests:     bookingInfo.Guests,
+		TotalPrice: quoteStay(stay, price, rules).Total,
+		Currency:   priceCurrency,
+		CheckIn:    bookingInfo.CheckIn,
+		CheckOut:   bookingInfo.CheckOut,
+		Status:     bookingStatusPending,
+	}
+	bookingQry := `
+	INSERT INTO item_booking(item_id , room_type_id , person_name , no_of_rooms, guests, check_in, check_out, total_price, currency, status, confirmation_code) VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (confirmation_code) DO NOTHING
+	RETURNING id_booking, created_at;`
+	for attempt := 0; attempt < confirmationCodeAttempts && booking.ID == 0; attempt++ {
+		booking.ConfirmationCode, err = newConfirmationCode()
+		if err != nil {
+			return Booking{}, fmt.Errorf("Error occured while creating the confirmation code %w", utils.ErrBookingFailed)
+		}
+		err = tx.QueryRowContext(ctx, bookingQry, booking.ItemID, booking.RoomTypeID, booking.PersonName, booking.NoOfRooms, booking.Guests, booking.CheckIn, booking.CheckOut, booking.TotalPrice, booking.Currency, booking.Status, booking.ConfirmationCode).Scan(&booking.ID, &booking.CreatedAt)
+		if err != nil && err != sql.ErrNoRows {
+			return Booking{}, fmt.Errorf("Error occured while creating the booking %w", utils.ErrBookingFailed)
+		}
+	}
+	if booking.ID == 0 {
+		err = utils.ErrBookingFailed
+		return Booking{}, fmt.Errorf("No free confirmation code found %w", err)
+	}
+	err = recordAudit(ctx, tx, booking.ItemID, auditActionBook, nil, booking)
+	if err != nil {
+		return Booking{}, fmt.Errorf("Error occured while recording the history %w", utils.ErrBookingFailed)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return Booking{}, fmt.Errorf("Error occured while saving the booking %w", utils.ErrBookingFailed)
+	}
+	return booking, nil
+}
+
+//UnknownAmenities returns the codes that are not in the amenity catalogue
+func (r *ItemsRepository) UnknownAmenities(ctx context.Context, codes []string) ([]string, error) {
+	query := `SELECT wanted.code FROM UNNEST($1::TEXT[]) AS wanted(code) WHERE NOT EXISTS (SELECT 1 FROM amenity WHERE amenity.code = wanted.code) ORDER BY wanted.code;`
+	rows, err := r.conn(ctx).QueryContext(ctx, query, pq.Array(codes))
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching amenities %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	unknown := []string{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("Error occured while fetching amenities %w", utils.ErrFetchError)
+		}
+		unknown = append(unknown, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching amenities %w", utils.ErrFetchError)
+	}
+	return unknown, nil
+}
+
+// saveItemAmenities links the item to the amenities of codes and returns the codes in the order they are read
+func saveItemAmenities(ctx context.Context, tx queryer, itemID uint64, codes []string) ([]string, error) {
+	codes = amenityCodes(codes)
+	sort.Strings(codes)
+	if len(codes) == 0 {
+		return codes, nil
+	}
+	query := `INSERT INTO item_amenity(item_id, amenity_id) SELECT $1, id FROM amenity WHERE code = ANY($2);`
+	result, err := tx.ExecContext(ctx, query, itemID, pq.Array(codes))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if int(rows) != len(codes) {
+		return nil, fmt.Errorf("Unknown amenities %w", utils.ErrUnknownAmenity)
+	}
+	return codes, nil
+}
+
+// lockItem reads the item with id, matching the extra condition, and locks its row until tx ends
+func lockItem(ctx context.Context, tx queryer, id int, condition string) (Item, error) {
+	query := itemSelectQuery + `
+	WHERE
+		item.item_id = $1 ` + condition + `
+	FOR UPDATE OF item`
+	return scanItem(tx.QueryRowContext(ctx, query, id))
+}
+
+// scanItem scans a row selected with itemSelectQuery
+func scanItem(row rowScanner, extra ...interface{}) (Item, error) {
+	var i Item
+	dest := []interface{}{&i.ID, &i.Name, &i.Rating, &i.Category, &i.Reputation, &i.ReputationBadge, &i.ReviewCount, &i.Price, &i.Currency, &i.Availability, &i.Image, &i.Location.City, &i.Location.State, &i.Location.Country, &i.Location.ZipCode, &i.Location.Address, &i.Location.Latitude, &i.Location.Longitude, &i.Location.CityMismatch, &i.Version, pq.Array(&i.Amenities)}
+	err := row.Scan(append(dest, extra...)...)
+	if i.Amenities == nil {
+		i.Amenities = []string{}
+	}
+	return i, err
+}
+
+// itemsFilterConditions turns the filter into parameterized conditions
+func itemsFilterConditions(filter ItemFilter) ([]string, []interface{}) {
+	conditions := []string{"item.deleted_at IS NULL"}
+	args := []interface{}{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Category != "" {
+		add("item.category = $%d", filter.Category)
+	}
+	if filter.City != "" {
+		add("LOWER(item_location.city) = LOWER($%d)", filter.City)
+	}
+	if filter.Country != "" {
+		add("LOWER(item_location.country) = LOWER($%d)", filter.Country)
+	}
+	if filter.Rating != 0 {
+		add("item.rating = $%d", filter.Rating)
+	}
+	if filter.CityMismatch {
+		conditions = append(conditions, "item_location.city_mismatch")
+	}
+	if filter.MinPrice != 0 {
+		add("item.price >= $%d", filter.MinPrice)
+	}
+	if filter.MaxPrice != 0 {
+		add("item.price <= $%d", filter.MaxPrice)
+	}
+	if filter.PriceCurrency != "" {
+		add("item.currency = $%d", filter.PriceCurrency)
+	}
+	if len(filter.Amenities) > 0 {
+		offered := `SELECT %s FROM item_amenity JOIN amenity ON amenity.id = item_amenity.amenity_id
+			WHERE item_amenity.item_id = item.item_id AND amenity.code = ANY($%%d)`
+		if filter.AnyAmenity {
+			add("EXISTS ("+fmt.Sprintf(offered, "1")+")", pq.Array(filter.Amenities))
+		} else {
+			add("("+fmt.Sprintf(offered, "COUNT(*)")+fmt.Sprintf(") = %d", len(filter.Amenities)), pq.Array(filter.Amenities))
+		}
+	}
+	if condition, ok := reputationBadgeRanges[filter.ReputationBadge]; ok {
+		conditions = append(conditions, "("+condition+")")
+	}
+	return conditions, args
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// itemsCursorCondition selects the items ordered after the cursor, for sort
+// (a, -b) it expands to a > $1 OR (a = $1 AND b < $2) OR (a = $1 AND b = $2 AND id > $3)
+func itemsCursorCondition(sort []SortField, cursor ItemCursor, args []interface{}) (string, []interface{}) {
+	fields := keysetFields(sort)
+	placeholders := []string{}
+	for i, field := range fields {
+		if field.Field == "id" {
+			args = append(args, cursor.ID)
+		} else {
+			args = append(args, cursor.Values[i])
+		}
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	alternatives := []string{}
+	for i, field := range fields {
+		terms := []string{}
+		for j := 0; j < i; j++ {
+			terms = append(terms, sortableColumns[fields[j].Field]+" = "+placeholders[j])
+		}
+		operator := " > "
+		if field.Desc {
+			operator = " < "
+		}
+		terms = append(terms, sortableColumns[field.Field]+operator+placeholders[i])
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// itemsOrderClause builds the ORDER BY clause, ending with item_id so that pages are stable
+func itemsOrderClause(sort []SortField) string {
+	keys := []string{}
+	for _, field := range keysetFields(sort) {
+		key := sortableColumns[field.Field]
+		if field.Desc {
+			key += " DESC"
+		}
+		keys = append(keys, key)
+	}
+	return " ORDER BY " + strings.Join(keys, ", ")
 }
 
 //NewItemsRepository method
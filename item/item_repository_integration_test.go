@@ -0,0 +1,175 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+// openTestDB connects to the migrated database named by TEST_DATABASE_URL
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping database test")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening the test database", err)
+	}
+	return db
+}
+
+func TestBookAccommodationConcurrent(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	const rooms = 5
+	const attempts = 25
+	var itemID uint64
+	err := db.QueryRowContext(ctx, `INSERT INTO item(name, rating, category, image, reputation, price, availability) VALUES ('Concurrency Test Hotel', 3, 'hotel', 'http://example.com/a.jpg', 500, 100, $1) RETURNING item_id`, rooms).Scan(&itemID)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the item", err)
+	}
+	defer db.ExecContext(ctx, `DELETE FROM item WHERE item_id = $1`, itemID)
+
+	checkIn := time.Now().UTC().AddDate(1, 0, 0)
+	repo := NewItemsRepository(db)
+	start := make(chan struct{})
+	results := make(chan error, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, err := repo.BookAccommodation(ctx, BookAccommodation{
+				ItemID:     itemID,
+				PersonName: fmt.Sprintf("guest %d", i),
+				NoOfRooms:  1,
+				CheckIn:    checkIn.Format(dateLayout),
+				CheckOut:   checkIn.AddDate(0, 0, 2).Format(dateLayout),
+			})
+			results <- err
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	booked := 0
+	for err := range results {
+		if err == nil {
+			booked++
+			continue
+		}
+		if !errors.Is(err, utils.ErrRoomsNotEnough) {
+			t.Errorf("unexpected booking error %s", err)
+		}
+	}
+	assert.Equal(t, rooms, booked)
+
+	rows, err := db.QueryContext(ctx, `SELECT rooms_booked FROM item_inventory WHERE item_id = $1`, itemID)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when reading the inventory", err)
+	}
+	defer rows.Close()
+	nights := 0
+	for rows.Next() {
+		var roomsBooked int
+		assert.NoError(t, rows.Scan(&roomsBooked))
+		assert.Equal(t, rooms, roomsBooked)
+		nights++
+	}
+	assert.Equal(t, 2, nights)
+}
+
+func TestSearchItems(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	repo := NewItemsRepository(db)
+	created, err := repo.AddItem(ctx, Item{
+		Name:         "Search Test Seaside Inn",
+		Rating:       4,
+		Category:     "hotel",
+		Image:        "http://example.com/a.jpg",
+		Reputation:   700,
+		Price:        120,
+		Availability: 3,
+		Location:     Location{City: "Kovalam", State: "Kerala", Country: "India", ZipCode: "695527", Address: "Lighthouse Road"},
+	})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the item", err)
+	}
+	defer db.ExecContext(ctx, `DELETE FROM item WHERE item_id = $1`, created.ID)
+
+	found := func(results SearchResults) bool {
+		for _, i := range results.Items {
+			if i.ID == created.ID {
+				return true
+			}
+		}
+		return false
+	}
+	results, err := repo.SearchItems(ctx, ItemSearch{Query: "seaside kovalam", Filter: ItemFilter{Limit: maxItemsLimit}})
+	assert.NoError(t, err)
+	assert.False(t, results.Fuzzy)
+	assert.True(t, found(results))
+
+	results, err = repo.SearchItems(ctx, ItemSearch{Query: "kovallam", Filter: ItemFilter{Limit: maxItemsLimit}})
+	assert.NoError(t, err)
+	assert.True(t, results.Fuzzy)
+	assert.True(t, found(results))
+}
+
+func TestNearbyItemsDistance(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	repo := NewItemsRepository(db)
+	lat, lng := 8.4004, 76.9787
+	created, err := repo.AddItem(ctx, Item{
+		Name:         "Nearby Test Beach Resort",
+		Rating:       4,
+		Category:     "resort",
+		Image:        "http://example.com/a.jpg",
+		Reputation:   700,
+		Price:        120,
+		Availability: 3,
+		Location:     Location{City: "Kovalam", State: "Kerala", Country: "India", ZipCode: "695527", Address: "Lighthouse Road", Latitude: &lat, Longitude: &lng},
+	})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the item", err)
+	}
+	defer db.ExecContext(ctx, `DELETE FROM item WHERE item_id = $1`, created.ID)
+
+	// Thiruvananthapuram central station is about 10 km away
+	distance := func(radiusKm float64) (float64, bool) {
+		results, err := repo.NearbyItems(ctx, NearbySearch{Latitude: 8.4875, Longitude: 76.9525, RadiusKm: radiusKm, Filter: ItemFilter{Limit: maxItemsLimit}})
+		assert.NoError(t, err)
+		for _, i := range results.Items {
+			if i.ID == created.ID {
+				return i.DistanceKm, true
+			}
+		}
+		return 0, false
+	}
+	km, found := distance(20)
+	assert.True(t, found)
+	assert.InDelta(t, 10.1, km, 0.5)
+	_, found = distance(5)
+	assert.False(t, found)
+}
@@ -2,13 +2,25 @@ package item
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sayooj/trivago/utils"
 	"github.com/stretchr/testify/assert"
 )
 
+var itemColumns = []string{"item_id", "name", "rating", "category", "reputation", "reputation_badge", "review_count", "price", "currency", "availability", "image", "city", "state", "country", "zip_code", "address", "latitude", "longitude", "city_mismatch", "version", "amenities"}
+
+// expectLockItem expects the item to be read and locked by lockItem
+func expectLockItem(mock sqlmock.Sqlmock, id int, version uint64) {
+	mock.ExpectQuery(`FOR UPDATE OF item`).WithArgs(id).WillReturnRows(sqlmock.NewRows(itemColumns).
+		AddRow(id, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, version, nil))
+}
+
 func TestAddItemSuccess(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -28,17 +40,40 @@ func TestAddItemSuccess(t *testing.T) {
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "78999",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	}
 	mock.ExpectBegin()
-	mock.ExpectQuery(`INSERT INTO item`).WithArgs(item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Availability).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
-	mock.ExpectExec(`INSERT INTO item_location`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO item`).WithArgs(item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, "EUR", item.Availability).WillReturnRows(sqlmock.NewRows([]string{"item_id", "version", "reputation_badge"}).AddRow(1, 1, "green"))
+	mock.ExpectExec(`INSERT INTO item_location`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address, nil, nil, false).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO item_image.* ON CONFLICT \(item_id\) WHERE is_primary`).WithArgs(item.ID, item.Image).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(item.ID, "create", utils.AnonymousActor, "", nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	repo := NewItemsRepository(db)
-	resp := repo.AddItem(context.Background(), item)
-	assert.NoError(t, resp)
+	resp, err := repo.AddItem(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.ID)
+	assert.Equal(t, "green", resp.ReputationBadge)
+}
+
+func TestAddItemsRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	items := []Item{{Name: "hotel abcd"}, {Name: "hotel efgh"}}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO item`).WithArgs("hotel abcd", 0, "", "", 0, 0, "EUR", 0).WillReturnRows(sqlmock.NewRows([]string{"item_id", "version", "reputation_badge"}).AddRow(1, 1, "red"))
+	mock.ExpectExec(`INSERT INTO item_location`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO item_audit`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO item`).WithArgs("hotel efgh", 0, "", "", 0, 0, "EUR", 0).WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, err = repo.AddItems(context.Background(), items)
+	assert.True(t, errors.Is(err, utils.ErrItemNotAdded))
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestAddItemFail(t *testing.T) {
@@ -60,16 +95,16 @@ func TestAddItemFail(t *testing.T) {
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "78999",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	}
 	mock.ExpectBegin()
-	mock.ExpectQuery(`INSERT INTO item`).WithArgs(item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Availability).WillReturnError(errors.New("error"))
-	mock.ExpectExec(`INSERT INTO item_location`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address).WillReturnError(errors.New("error"))
+	mock.ExpectQuery(`INSERT INTO item`).WithArgs(item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, "EUR", item.Availability).WillReturnError(errors.New("error"))
+	mock.ExpectExec(`INSERT INTO item_location`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address, nil, nil, false).WillReturnError(errors.New("error"))
 	mock.ExpectCommit()
 	repo := NewItemsRepository(db)
-	resp := repo.AddItem(context.Background(), item)
+	_, resp := repo.AddItem(context.Background(), item)
 	assert.Error(t, resp)
 }
 
@@ -79,10 +114,15 @@ func TestDeleteItem(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer db.Close()
-	mock.ExpectExec(`DELETE FROM item`).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 3)
+	mock.ExpectExec(`UPDATE item SET deleted_at = NOW\(\)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(uint64(1), "delete", utils.AnonymousActor, "", sqlmock.AnyArg(), nil).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 	repo := NewItemsRepository(db)
-	resp := repo.DeleteItem(context.Background(), 1)
+	resp := repo.DeleteItem(context.Background(), 1, 0)
 	assert.NoError(t, resp)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestDeleteItemError(t *testing.T) {
@@ -91,25 +131,146 @@ func TestDeleteItemError(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer db.Close()
-	mock.ExpectExec(`DELETE FROM item`).WithArgs(1).WillReturnError(errors.New("error"))
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 3)
+	mock.ExpectExec(`UPDATE item SET deleted_at`).WithArgs(1).WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
 	repo := NewItemsRepository(db)
-	resp := repo.DeleteItem(context.Background(), 1)
+	resp := repo.DeleteItem(context.Background(), 1, 0)
 	assert.Error(t, resp)
 }
 
+func TestWithTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 3)
+	mock.ExpectExec(`UPDATE item SET deleted_at = NOW\(\)`).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO item_audit`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT`).WithArgs(2).WillReturnRows(sqlmock.NewRows(itemColumns))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	err = repo.WithTransaction(context.Background(), func(ctx context.Context) error {
+		if err := repo.DeleteItem(ctx, 1, 0); err != nil {
+			return err
+		}
+		_, err := repo.GetItem(ctx, 2)
+		return err
+	})
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchItemsFuzzy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) .* item.search_vector @@ websearch_to_tsquery\('simple', \$2\)`).WithArgs("hotel", "berln").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`ORDER BY ts_rank_cd`).WithArgs("hotel", "berln", 20, 0).WillReturnRows(sqlmock.NewRows(itemColumns))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) .* \(\$2 <% item.name OR \$2 <% item_location.city\)`).WithArgs("hotel", "berln").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`ORDER BY GREATEST\(word_similarity\(\$2, item.name\), word_similarity\(\$2, item_location.city\)\) DESC, item.item_id LIMIT \$3 OFFSET \$4`).
+		WithArgs("hotel", "berln", 20, 0).
+		WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(1, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "berlin", "berlin", "germany", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil))
+	repo := NewItemsRepository(db)
+	res, err := repo.SearchItems(context.Background(), ItemSearch{Query: "berln", Filter: ItemFilter{Category: "hotel", Limit: 20}})
+	assert.NoError(t, err)
+	assert.True(t, res.Fuzzy)
+	assert.Equal(t, 1, *res.Meta.Total)
+	assert.Equal(t, "berlin", res.Items[0].Location.City)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNearbyItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	search := NearbySearch{Latitude: 10.52, Longitude: 76.21, RadiusKm: 5, Filter: ItemFilter{Limit: 20}}
+	minLat, maxLat, minLng, maxLng, _ := search.BoundingBox()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) .* item_location.latitude BETWEEN \$3 AND \$4 AND item_location.longitude BETWEEN \$5 AND \$6 AND \(2 \* 6371 \* ASIN`).
+		WithArgs(10.52, 76.21, minLat, maxLat, minLng, maxLng, 5.0).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`AS distance_km .* ORDER BY distance_km, item.item_id LIMIT \$8 OFFSET \$9`).
+		WithArgs(10.52, 76.21, minLat, maxLat, minLng, maxLng, 5.0, 20, 0).
+		WillReturnRows(sqlmock.NewRows(append(itemColumns, "distance_km")).AddRow(1, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", 10.53, 76.2, false, 1, nil, 1.52))
+	repo := NewItemsRepository(db)
+	res, err := repo.NearbyItems(context.Background(), search)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *res.Meta.Total)
+	assert.Equal(t, 1.52, res.Items[0].DistanceKm)
+	assert.Equal(t, 10.53, *res.Items[0].Location.Latitude)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetItem(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer db.Close()
-	mock.ExpectQuery(`SELECT`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id", "name", "rating", "category", "reputation", "reputation_badge", "price", "availability", "image", "city", "state", "country", "zip_code", "address"}).AddRow(1, "test", 5, "hotel", 600, "yellow", 1000, 10, "http://sc.com", "fdfd", "dffd", "fdfdf", 67888, "dfdfdf dfd d "))
+	mock.ExpectQuery(`SELECT`).WithArgs(1).WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(1, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil))
 	repo := NewItemsRepository(db)
 	resp, err := repo.GetItem(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, resp.ID, uint64(1))
 }
 
+func TestRestoreItemNotDeletedRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`item.item_id = \$1 AND item.deleted_at IS NOT NULL\s+FOR UPDATE OF item`).WithArgs(1).WillReturnRows(sqlmock.NewRows(itemColumns))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	err = repo.RestoreItem(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 3)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(1, sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`DELETE FROM item WHERE item_id = \$1`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(uint64(1), "purge", utils.AnonymousActor, "", sqlmock.AnyArg(), nil).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	repo := NewItemsRepository(db)
+	err = repo.PurgeItem(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeItemActiveBookings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 3)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(1, sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	err = repo.PurgeItem(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrItemHasActiveBookings))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetItemError(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -128,13 +289,140 @@ func TestGetItems(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer db.Close()
-	mock.ExpectQuery(`SELECT`).WillReturnRows(sqlmock.NewRows([]string{"item_id", "name", "rating", "category", "reputation", "reputation_badge", "price", "availability", "image", "city", "state", "country", "zip_code", "address"}).
-		AddRow(1, "test", 5, "hotel", 600, "yellow", 1000, 10, "http://sc.com", "fdfd", "dffd", "fdfdf", 67888, "dfdfdf dfd d ").AddRow(2, "test", 5, "hotel", 600, "yellow", 1000, 10, "http://sc.com", "fdfd", "dffd", "fdfdf", 67888, "dfdfdf dfd d "))
+	mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT`).WithArgs(21, 0).WillReturnRows(sqlmock.NewRows(itemColumns).
+		AddRow(1, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil).AddRow(2, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil))
+	repo := NewItemsRepository(db)
+	resp, err := repo.GetItems(context.Background(), ItemFilter{Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, resp.Items[0].ID, uint64(1))
+	assert.Equal(t, resp.Items[1].ID, uint64(2))
+	assert.Equal(t, *resp.Meta.Total, 2)
+}
+
+func TestGetItemsFilter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	filter := ItemFilter{
+		Limit:           5,
+		Offset:          10,
+		Category:        "hotel",
+		Country:         "india",
+		ReputationBadge: "yellow",
+		MinPrice:        500,
+		PriceCurrency:   "EUR",
+		Sort:            []SortField{{Field: "price"}, {Field: "rating", Desc: true}},
+	}
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM item .* WHERE item.deleted_at IS NULL AND item.category = \$1 AND LOWER\(item_location.country\) = LOWER\(\$2\) AND item.price >= \$3 AND item.currency = \$4 AND \(item.reputation > 500 AND item.reputation <= 799\)`).
+		WithArgs("hotel", "india", uint64(500), "EUR").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+	mock.ExpectQuery(`ORDER BY item.price, item.rating DESC, item.item_id LIMIT \$5 OFFSET \$6`).
+		WithArgs("hotel", "india", uint64(500), "EUR", 6, 10).WillReturnRows(sqlmock.NewRows(itemColumns).
+		AddRow(11, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "india", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil))
+	repo := NewItemsRepository(db)
+	resp, err := repo.GetItems(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), resp.Items[0].ID)
+	total := 11
+	assert.Equal(t, ListMeta{Total: &total, Limit: 5, Offset: 10}, resp.Meta)
+	assert.Empty(t, resp.NextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetItemsAmenities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	amenities := pq.Array([]string{"wifi", "pool"})
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM item .* WHERE item.deleted_at IS NULL AND \(SELECT COUNT\(\*\) FROM item_amenity .* amenity.code = ANY\(\$1\)\) = 2`).
+		WithArgs(amenities).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`ORDER BY item.item_id LIMIT \$2 OFFSET \$3`).WithArgs(amenities, 21, 0).WillReturnRows(sqlmock.NewRows(itemColumns).
+		AddRow(11, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "india", "67888", "dfdfdf dfd d ", nil, nil, false, 1, "{pool,spa,wifi}"))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM item .* WHERE item.deleted_at IS NULL AND EXISTS \(SELECT 1 FROM item_amenity .* amenity.code = ANY\(\$1\)\)$`).
+		WithArgs(amenities).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`EXISTS \(SELECT 1 FROM item_amenity .* ORDER BY item.item_id LIMIT \$2 OFFSET \$3`).WithArgs(amenities, 21, 0).WillReturnRows(sqlmock.NewRows(itemColumns))
+	repo := NewItemsRepository(db)
+	resp, err := repo.GetItems(context.Background(), ItemFilter{Limit: 20, Amenities: []string{"wifi", "pool"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pool", "spa", "wifi"}, resp.Items[0].Amenities)
+	resp, err = repo.GetItems(context.Background(), ItemFilter{Limit: 20, Amenities: []string{"wifi", "pool"}, AnyAmenity: true})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateItemAmenities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	item := Item{ID: 1, Name: "hotel abcd", Image: "http://abc.com/img.jpg", Amenities: []string{"wifi", "pool", "wifi"}}
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 1)
+	mock.ExpectQuery(`UPDATE item SET`).WillReturnRows(sqlmock.NewRows([]string{"version", "reputation", "review_count", "reputation_badge"}).AddRow(2, 600, 0, "green"))
+	mock.ExpectExec(`UPDATE item_location`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO item_image`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM item_amenity WHERE item_id = \$1`).WithArgs(item.ID).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO item_amenity`).WithArgs(item.ID, pq.Array([]string{"pool", "wifi"})).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, err = repo.UpdateItem(context.Background(), item)
+	assert.True(t, errors.Is(err, utils.ErrUnknownAmenity))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetItemsCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	sort := []SortField{{Field: "price", Desc: true}}
+	filter := ItemFilter{
+		Limit:  1,
+		Sort:   sort,
+		Cursor: &ItemCursor{Sort: "-price", Values: []string{"1000"}, ID: 4},
+	}
+	// pages reached with a cursor are not counted
+	mock.ExpectQuery(`WHERE item.deleted_at IS NULL AND \(\(item.price < \$1\) OR \(item.price = \$1 AND item.item_id > \$2\)\) ORDER BY item.price DESC, item.item_id LIMIT \$3 OFFSET \$4`).
+		WithArgs("1000", uint64(4), 2, 0).WillReturnRows(sqlmock.NewRows(itemColumns).
+		AddRow(2, "test", 5, "hotel", 600, "yellow", 0, 900, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil).
+		AddRow(3, "test", 5, "hotel", 600, "yellow", 0, 800, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil))
 	repo := NewItemsRepository(db)
-	resp, err := repo.GetItems(context.Background())
+	resp, err := repo.GetItems(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Items, 1)
+	assert.Equal(t, uint64(2), resp.Items[0].ID)
+	assert.Nil(t, resp.Meta.Total)
+	next, err := DecodeItemCursor(resp.NextCursor)
 	assert.NoError(t, err)
-	assert.Equal(t, resp[0].ID, uint64(1))
-	assert.Equal(t, resp[1].ID, uint64(2))
+	assert.Equal(t, ItemCursor{Sort: "-price", Values: []string{"900"}, ID: 2}, next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportItemsRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`WHERE item.deleted_at IS NULL AND item.category = \$1 ORDER BY item.item_id$`).WithArgs("hotel").WillReturnRows(sqlmock.NewRows(itemColumns).
+		AddRow(1, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil).
+		AddRow(2, "test", 5, "hotel", 600, "yellow", 0, 1000, "EUR", 10, "http://sc.com", "fdfd", "dffd", "fdfdf", "67888", "dfdfdf dfd d ", nil, nil, false, 1, nil))
+	repo := NewItemsRepository(db)
+	ids := []uint64{}
+	err = repo.ExportItems(context.Background(), ItemFilter{Limit: 20, Category: "hotel"}, func(i Item) error {
+		ids = append(ids, i.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetItemsError(t *testing.T) {
@@ -145,10 +433,25 @@ func TestGetItemsError(t *testing.T) {
 	defer db.Close()
 	mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("Error"))
 	repo := NewItemsRepository(db)
-	_, err = repo.GetItems(context.Background())
+	_, err = repo.GetItems(context.Background(), ItemFilter{Limit: 20})
 	assert.Error(t, err)
 }
 
+func TestItemExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	// deleted items exist until they are purged
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM item WHERE item_id = \$1\)`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	repo := NewItemsRepository(db)
+	exists, err := repo.ItemExists(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdateItem(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -168,17 +471,43 @@ func TestUpdateItem(t *testing.T) {
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "78999",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	}
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE`).WithArgs(item.ID, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Availability).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(`UPDATE`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLockItem(mock, 1, 1)
+	mock.ExpectQuery(`UPDATE item SET .*\s+price=.* version = version \+ 1`).WithArgs(item.ID, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, "EUR", item.Availability).WillReturnRows(sqlmock.NewRows([]string{"version", "reputation", "review_count", "reputation_badge"}).AddRow(2, 600, 0, "green"))
+	mock.ExpectExec(`UPDATE`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address, nil, nil, false).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO item_image`).WithArgs(item.ID, item.Image).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE item_inventory`).WithArgs(item.ID, item.Availability).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(item.ID, "update", utils.AnonymousActor, "", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	repo := NewItemsRepository(db)
-	resp := repo.UpdateItem(context.Background(), item)
-	assert.NoError(t, resp)
+	resp, err := repo.UpdateItem(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), resp.Version)
+}
+
+func TestUpdateItemWithoutImage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	item := Item{ID: 1, Name: "hotel abcd", Rating: 5, Category: "hotel", Reputation: 800, Price: 1000, Availability: 10}
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 1)
+	mock.ExpectQuery(`UPDATE item SET`).WithArgs(item.ID, item.Name, item.Rating, item.Category, "", item.Reputation, item.Price, "EUR", item.Availability).WillReturnRows(sqlmock.NewRows([]string{"version", "reputation", "review_count", "reputation_badge"}).AddRow(2, 800, 0, "green"))
+	mock.ExpectExec(`UPDATE item_location`).WillReturnResult(sqlmock.NewResult(1, 1))
+	// an empty image does not become the url of the primary image
+	mock.ExpectExec(`UPDATE item_inventory`).WithArgs(item.ID, item.Availability).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO item_audit`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	repo := NewItemsRepository(db)
+	_, err = repo.UpdateItem(context.Background(), item)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUpdateItemError(t *testing.T) {
@@ -200,19 +529,51 @@ func TestUpdateItemError(t *testing.T) {
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "78999",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	}
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE`).WithArgs(item.ID, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, item.Availability).WillReturnError(errors.New("error"))
-	mock.ExpectExec(`UPDATE`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address).WillReturnError(errors.New("error"))
+	expectLockItem(mock, 1, 1)
+	mock.ExpectQuery(`UPDATE`).WithArgs(item.ID, item.Name, item.Rating, item.Category, item.Image, item.Reputation, item.Price, "EUR", item.Availability).WillReturnError(errors.New("error"))
+	mock.ExpectExec(`UPDATE`).WithArgs(item.ID, item.Location.City, item.Location.State, item.Location.Country, item.Location.ZipCode, item.Location.Address, nil, nil, false).WillReturnError(errors.New("error"))
 	mock.ExpectCommit()
 	repo := NewItemsRepository(db)
-	resp := repo.UpdateItem(context.Background(), item)
+	_, resp := repo.UpdateItem(context.Background(), item)
 	assert.Error(t, resp)
 }
 
+func TestUpdateItemVersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	item := Item{ID: 1, Name: "hotel abcd", Availability: 10, Version: 2}
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 3)
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, err = repo.UpdateItem(context.Background(), item)
+	assert.True(t, errors.Is(err, utils.ErrItemVersionMismatch))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteItemVersionMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	expectLockItem(mock, 1, 4)
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	err = repo.DeleteItem(context.Background(), 1, 3)
+	assert.True(t, errors.Is(err, utils.ErrItemVersionMismatch))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestBookAccommodation(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -223,14 +584,150 @@ func TestBookAccommodation(t *testing.T) {
 		ItemID:     1,
 		PersonName: "Svr",
 		NoOfRooms:  3,
+		CheckIn:    "2030-01-10",
+		CheckOut:   "2030-01-12",
 	}
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE`).WithArgs(item.ItemID, item.NoOfRooms).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(`INSERT`).WithArgs(item.ItemID, item.PersonName, item.NoOfRooms).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT availability, price, currency, EXISTS \(SELECT 1 FROM room_type .*\) FROM item WHERE item_id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs(item.ItemID).WillReturnRows(sqlmock.NewRows([]string{"availability", "price", "currency", "exists"}).AddRow(5, 1000, "EUR", false))
+	mock.ExpectExec(`INSERT INTO item_inventory`).WithArgs(item.ItemID, item.CheckIn, item.CheckOut).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE item_inventory .* AND rooms_total - rooms_booked >= \$4`).WithArgs(item.ItemID, item.CheckIn, item.CheckOut, item.NoOfRooms).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`FROM\s+pricing_rule\s+WHERE item_id = \$1 AND COALESCE\(room_type_id, 0\) = \$2`).WithArgs(item.ItemID, item.RoomTypeID, item.CheckIn, item.CheckOut).WillReturnRows(sqlmock.NewRows(pricingRuleColumns))
+	// the first confirmation code is already taken
+	mock.ExpectQuery(`INSERT INTO item_booking`).WithArgs(item.ItemID, item.RoomTypeID, item.PersonName, item.NoOfRooms, item.Guests, item.CheckIn, item.CheckOut, uint64(6000), "EUR", "pending", sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id_booking", "created_at"}))
+	mock.ExpectQuery(`INSERT INTO item_booking`).WithArgs(item.ItemID, item.RoomTypeID, item.PersonName, item.NoOfRooms, item.Guests, item.CheckIn, item.CheckOut, uint64(6000), "EUR", "pending", sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"id_booking", "created_at"}).AddRow(9, time.Now()))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(item.ItemID, "book", utils.AnonymousActor, "", nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 	repo := NewItemsRepository(db)
-	resp := repo.BookAccommodation(context.Background(), item)
-	assert.NoError(t, resp)
+	resp, err := repo.BookAccommodation(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), resp.ID)
+	assert.Equal(t, "pending", resp.Status)
+	assert.Regexp(t, `^[A-Z2-9]{4}-[A-Z2-9]{4}$`, resp.ConfirmationCode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookAccommodationItemNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(uint64(1)).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, resp := repo.BookAccommodation(context.Background(), BookAccommodation{ItemID: 1, NoOfRooms: 1, CheckIn: "2030-01-10", CheckOut: "2030-01-11"})
+	assert.True(t, errors.Is(resp, utils.ErrItemNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookAccommodationOverCapacity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(uint64(1)).WillReturnRows(sqlmock.NewRows([]string{"availability", "price", "currency", "exists"}).AddRow(2, 1000, "EUR", false))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, resp := repo.BookAccommodation(context.Background(), BookAccommodation{ItemID: 1, NoOfRooms: 3, CheckIn: "2030-01-10", CheckOut: "2030-01-11"})
+	assert.True(t, errors.Is(resp, utils.ErrRoomsNotEnough))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookAccommodationRoomType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	item := BookAccommodation{
+		ItemID:     1,
+		RoomTypeID: 2,
+		PersonName: "Svr",
+		NoOfRooms:  2,
+		Guests:     5,
+		CheckIn:    "2030-01-10",
+		CheckOut:   "2030-01-12",
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(item.ItemID).WillReturnRows(sqlmock.NewRows([]string{"availability", "price", "currency", "exists"}).AddRow(0, 1000, "EUR", true))
+	mock.ExpectQuery(`SELECT inventory, max_occupancy, price FROM room_type WHERE id = \$1 AND item_id = \$2`).WithArgs(item.RoomTypeID, item.ItemID).
+		WillReturnRows(sqlmock.NewRows([]string{"inventory", "max_occupancy", "price"}).AddRow(4, 3, 9000))
+	mock.ExpectExec(`INSERT INTO room_type_inventory`).WithArgs(item.RoomTypeID, item.CheckIn, item.CheckOut).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE room_type_inventory .* AND rooms_total - rooms_booked >= \$4`).WithArgs(item.RoomTypeID, item.CheckIn, item.CheckOut, item.NoOfRooms).WillReturnResult(sqlmock.NewResult(0, 2))
+	// friday 2030-01-11 is priced by a weekend rule
+	mock.ExpectQuery(`FROM\s+pricing_rule`).WithArgs(item.ItemID, item.RoomTypeID, item.CheckIn, item.CheckOut).WillReturnRows(sqlmock.NewRows(pricingRuleColumns).
+		AddRow(4, 1, 2, "Weekend", "nightly", nil, nil, "{friday,saturday}", 0, 12000, 0, 0, time.Now()))
+	mock.ExpectQuery(`INSERT INTO item_booking`).WithArgs(item.ItemID, item.RoomTypeID, item.PersonName, item.NoOfRooms, item.Guests, item.CheckIn, item.CheckOut, uint64(42000), "EUR", "pending", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id_booking", "created_at"}).AddRow(9, time.Now()))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(item.ItemID, "book", utils.AnonymousActor, "", nil, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	repo := NewItemsRepository(db)
+	resp, err := repo.BookAccommodation(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), resp.RoomTypeID)
+	assert.Equal(t, uint(5), resp.Guests)
+	assert.Equal(t, uint64(42000), resp.TotalPrice)
+	assert.Equal(t, "EUR", resp.Currency)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookAccommodationRoomTypeRequired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(uint64(1)).WillReturnRows(sqlmock.NewRows([]string{"availability", "price", "currency", "exists"}).AddRow(5, 1000, "EUR", true))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, resp := repo.BookAccommodation(context.Background(), BookAccommodation{ItemID: 1, NoOfRooms: 1, CheckIn: "2030-01-10", CheckOut: "2030-01-11"})
+	assert.True(t, errors.Is(resp, utils.ErrRoomTypeRequired))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookAccommodationTooManyGuests(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(uint64(1)).WillReturnRows(sqlmock.NewRows([]string{"availability", "price", "currency", "exists"}).AddRow(0, 1000, "EUR", true))
+	mock.ExpectQuery(`FROM room_type WHERE id = \$1`).WithArgs(uint64(2), uint64(1)).WillReturnRows(sqlmock.NewRows([]string{"inventory", "max_occupancy", "price"}).AddRow(4, 2, 9000))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, resp := repo.BookAccommodation(context.Background(), BookAccommodation{ItemID: 1, RoomTypeID: 2, NoOfRooms: 1, Guests: 3, CheckIn: "2030-01-10", CheckOut: "2030-01-11"})
+	assert.True(t, errors.Is(resp, utils.ErrTooManyGuests))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookAccommodationRoomsTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	item := BookAccommodation{
+		ItemID:     1,
+		PersonName: "Svr",
+		NoOfRooms:  3,
+		CheckIn:    "2030-01-10",
+		CheckOut:   "2030-01-12",
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(item.ItemID).WillReturnRows(sqlmock.NewRows([]string{"availability", "price", "currency", "exists"}).AddRow(5, 1000, "EUR", false))
+	mock.ExpectExec(`INSERT INTO item_inventory`).WithArgs(item.ItemID, item.CheckIn, item.CheckOut).WillReturnResult(sqlmock.NewResult(0, 0))
+	// only one of the two nights has 3 rooms left
+	mock.ExpectExec(`UPDATE item_inventory`).WithArgs(item.ItemID, item.CheckIn, item.CheckOut, item.NoOfRooms).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+	repo := NewItemsRepository(db)
+	_, resp := repo.BookAccommodation(context.Background(), item)
+	assert.True(t, errors.Is(resp, utils.ErrRoomsNotEnough))
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestBookAccommodationError(t *testing.T) {
@@ -243,12 +740,34 @@ func TestBookAccommodationError(t *testing.T) {
 		ItemID:     1,
 		PersonName: "Svr",
 		NoOfRooms:  3,
+		CheckIn:    "2030-01-10",
+		CheckOut:   "2030-01-12",
 	}
 	mock.ExpectBegin()
-	mock.ExpectExec(`UPDATE`).WithArgs(item.ItemID, item.NoOfRooms).WillReturnError(errors.New("error"))
-	mock.ExpectExec(`INSERT`).WithArgs(item.ItemID, item.PersonName, item.NoOfRooms).WillReturnError(errors.New("error"))
-	mock.ExpectCommit()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(item.ItemID).WillReturnRows(sqlmock.NewRows([]string{"availability", "price", "currency", "exists"}).AddRow(5, 1000, "EUR", false))
+	mock.ExpectExec(`INSERT INTO item_inventory`).WithArgs(item.ItemID, item.CheckIn, item.CheckOut).WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
 	repo := NewItemsRepository(db)
-	resp := repo.BookAccommodation(context.Background(), item)
+	_, resp := repo.BookAccommodation(context.Background(), item)
 	assert.Error(t, resp)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetItemHistoryRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	columns := []string{"id", "item_id", "action", "actor", "request_id", "before", "after", "created_at"}
+	mock.ExpectQuery(`FROM item_audit WHERE item_id = \$1 ORDER BY id`).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(1, 1, "create", "svr", "host/abc-000001", nil, []byte(`{"price":1000}`), time.Now()).
+		AddRow(2, 1, "update", "svr", "host/abc-000002", []byte(`{"price":1000}`), []byte(`{"price":1200}`), time.Now()))
+	repo := NewItemsRepository(db)
+	history, err := repo.GetItemHistory(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Nil(t, history[0].Before)
+	assert.Equal(t, "update", history[1].Action)
+	assert.JSONEq(t, `{"price":1200}`, string(history[1].After))
 }
@@ -2,135 +2,413 @@ package item
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 
+	"github.com/sayooj/trivago/currency"
+	"github.com/sayooj/trivago/geocode"
 	"github.com/sayooj/trivago/utils"
 )
 
 //ItemsUseCaseInterface interface
 type ItemsUseCaseInterface interface {
-	AddItem(ctx context.Context, Item Item) error
-	DeleteItem(ctx context.Context, id int) error
+	AddItem(ctx context.Context, Item Item) (Item, error)
+	ImportItems(ctx context.Context, reader ItemReader) (ImportReport, error)
+	Batch(ctx context.Context, batch BatchRequest) (BatchResult, error)
+	DeleteItem(ctx context.Context, id int, version uint64) error
 	GetItem(ctx context.Context, id int) (Item, error)
-	UpdateItem(ctx context.Context, item Item) error
-	GetItems(ctx context.Context) ([]Item, error)
-	BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) error
+	UpdateItem(ctx context.Context, item Item) (Item, error)
+	RestoreItem(ctx context.Context, id int) (Item, error)
+	PurgeItem(ctx context.Context, id int) error
+	GetItemHistory(ctx context.Context, id int) ([]AuditEntry, error)
+	GetItems(ctx context.Context, filter ItemFilter) (ItemList, error)
+	ConvertPrices(ctx context.Context, items []Item, to string) error
+	SearchItems(ctx context.Context, search ItemSearch) (SearchResults, error)
+	NearbyItems(ctx context.Context, search NearbySearch) (NearbyResults, error)
+	ExportItems(ctx context.Context, filter ItemFilter, writer ItemWriter) error
+	BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) (Booking, error)
 }
 
-//ItemsUseCase struct
+//ItemsUseCase struct, without a geocoder locations are stored as given and without exchange rates prices
+//can not be converted
 type ItemsUseCase struct {
 	itemRepo ItemsRepositoryInterface
+	geocoder geocode.GeocodeUseCaseInterface
+	rates    currency.ExchangeRatesUseCaseInterface
 }
 
-//AddItem method
-func (u *ItemsUseCase) AddItem(ctx context.Context, item Item) error {
-	err := u.itemRepo.AddItem(ctx, item)
+//AddItem adds an item and returns the created item
+func (u *ItemsUseCase) AddItem(ctx context.Context, item Item) (Item, error) {
+	if err := u.geocodeLocation(ctx, &item.Location); err != nil {
+		return Item{}, err
+	}
+	if err := u.checkAmenities(ctx, item.Amenities); err != nil {
+		return Item{}, err
+	}
+	created, err := u.itemRepo.AddItem(ctx, item)
+	if err != nil {
+		return Item{}, err
+	}
+	return created, nil
+}
+
+//ImportItems validates every row of the reader and adds the valid ones in batches of importBatchSize.
+//The report covers the rows read so far when an error stops the import
+func (u *ItemsUseCase) ImportItems(ctx context.Context, reader ItemReader) (ImportReport, error) {
+	report := ImportReport{Rows: []ImportRowResult{}}
+	batch := []Item{}
+	batchRows := []int{}
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		created, err := u.itemRepo.AddItems(ctx, batch)
+		for i, row := range batchRows {
+			if err != nil {
+				report.Rows[row].Status = importRowFailed
+				report.Failed++
+				continue
+			}
+			report.Rows[row].Status = importRowAccepted
+			report.Rows[row].ID = created[i].ID
+			report.Accepted++
+		}
+		batch = batch[:0]
+		batchRows = batchRows[:0]
+		return err
+	}
+	// stop stores the rows read so far before the import stops with err, a failure to store them is reported along
+	stop := func(err error) error {
+		if flushErr := flush(); flushErr != nil {
+			return fmt.Errorf("%v, the rows read before could not be stored %w", err, flushErr)
+		}
+		return err
+	}
+
+	for row := 1; ; row++ {
+		item, invalidParams, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, stop(fmt.Errorf("Failed to read the import %w", utils.ErrInvalidImport))
+		}
+		if len(invalidParams) == 0 {
+			invalidParams = item.ValidateRequiredItem()
+		}
+		if len(invalidParams) == 0 {
+			invalidParams = item.ValidateFields()
+		}
+		if len(invalidParams) == 0 {
+			err := u.geocodeLocation(ctx, &item.Location)
+			if errors.Is(err, utils.ErrInvalidLocation) {
+				invalidParams = invalidLocationParams()
+			} else if err != nil {
+				return report, stop(err)
+			}
+		}
+		if len(invalidParams) == 0 {
+			err := u.checkAmenities(ctx, item.Amenities)
+			if errors.Is(err, utils.ErrUnknownAmenity) {
+				invalidParams = invalidAmenitiesParams()
+			} else if err != nil {
+				return report, stop(err)
+			}
+		}
+		if len(invalidParams) > 0 {
+			report.Rows = append(report.Rows, ImportRowResult{Row: row, Status: importRowRejected, InvalidParams: invalidParams})
+			report.Rejected++
+			continue
+		}
+		report.Rows = append(report.Rows, ImportRowResult{Row: row})
+		batch = append(batch, item)
+		batchRows = append(batchRows, len(report.Rows)-1)
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+//Batch runs the operations of batch in order and returns the result of each. An atomic batch runs in one
+//transaction and stops at the first failure, the operations before it are rolled back and the ones after it
+//are not run. An error is returned only when the transaction itself fails
+func (u *ItemsUseCase) Batch(ctx context.Context, batch BatchRequest) (BatchResult, error) {
+	result := BatchResult{Atomic: batch.Atomic, Results: make([]BatchOperationResult, len(batch.Operations))}
+	valid := true
+	for i, op := range batch.Operations {
+		result.Results[i] = BatchOperationResult{Ref: op.Ref, Op: op.Op}
+		if invalidParams := op.Validate(); len(invalidParams) > 0 {
+			result.Results[i].InvalidParams = invalidParams
+			result.Results[i].err = fmt.Errorf("Invalid operation %s %w", op.Ref, utils.ErrInvalidBatchOperation)
+			valid = false
+		}
+	}
+
+	if !batch.Atomic {
+		for i, op := range batch.Operations {
+			if result.Results[i].err == nil {
+				u.runBatchOperation(ctx, op, &result.Results[i])
+			}
+		}
+		result.Committed = true
+		return result, nil
+	}
+
+	if valid {
+		failed := false
+		err := u.itemRepo.WithTransaction(ctx, func(ctx context.Context) error {
+			for i, op := range batch.Operations {
+				if err := u.runBatchOperation(ctx, op, &result.Results[i]); err != nil {
+					failed = true
+					return err
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			result.Committed = true
+			return result, nil
+		}
+		if !failed {
+			return BatchResult{}, err
+		}
+	}
+	for i := range result.Results {
+		if result.Results[i].err == nil {
+			result.Results[i] = BatchOperationResult{Ref: result.Results[i].Ref, Op: result.Results[i].Op, err: utils.ErrBatchRolledBack}
+		}
+	}
+	return result, nil
+}
+
+// runBatchOperation runs op and records its outcome in result
+func (u *ItemsUseCase) runBatchOperation(ctx context.Context, op BatchOperation, result *BatchOperationResult) error {
+	var item Item
+	var err error
+	switch op.Op {
+	case batchOpCreate:
+		item, err = u.AddItem(ctx, *op.Item)
+	case batchOpUpdate:
+		item = *op.Item
+		item.ID = uint64(op.ID)
+		item.Version = op.Version
+		item, err = u.UpdateItem(ctx, item)
+	case batchOpDelete:
+		err = u.DeleteItem(ctx, op.ID, op.Version)
+		item.ID = uint64(op.ID)
+	}
 	if err != nil {
+		result.err = err
 		return err
 	}
+	result.ID = item.ID
+	if op.Op != batchOpDelete {
+		result.ETag = item.ETag()
+	}
 	return nil
 }
 
-//DeleteItem delete Item
-func (u *ItemsUseCase) DeleteItem(ctx context.Context, id int) error {
+//DeleteItem delete Item, a non zero version deletes it only while it is still at that version
+func (u *ItemsUseCase) DeleteItem(ctx context.Context, id int, version uint64) error {
 	_, err := u.itemRepo.GetItem(ctx, id)
 	if err != nil {
 		return fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
 	}
-	err = u.itemRepo.DeleteItem(ctx, id)
+	err = u.itemRepo.DeleteItem(ctx, id, version)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-//GetItem gets a Item with id
-func (u *ItemsUseCase) GetItem(ctx context.Context, id int) (Item, error) {
-	item, err := u.itemRepo.GetItem(ctx, id)
+//RestoreItem restores a deleted Item and returns it
+func (u *ItemsUseCase) RestoreItem(ctx context.Context, id int) (Item, error) {
+	err := u.itemRepo.RestoreItem(ctx, id)
 	if err != nil {
 		return Item{}, err
 	}
-	return item, nil
+	return u.itemRepo.GetItem(ctx, id)
 }
 
-//UpdateItem updates a Item with id
-func (u *ItemsUseCase) UpdateItem(ctx context.Context, item Item) error {
-	itemInfo, err := u.itemRepo.GetItem(ctx, int(item.ID))
+//PurgeItem removes a Item for good
+func (u *ItemsUseCase) PurgeItem(ctx context.Context, id int) error {
+	return u.itemRepo.PurgeItem(ctx, id)
+}
+
+//ExportItems writes every item matching the filter to writer
+func (u *ItemsUseCase) ExportItems(ctx context.Context, filter ItemFilter, writer ItemWriter) error {
+	err := u.itemRepo.ExportItems(ctx, filter, writer.Write)
 	if err != nil {
-		return fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
-	}
-	if item.Name != "" {
-		itemInfo.Name = item.Name
-	}
-	if item.Rating != 0 {
-		itemInfo.Rating = item.Rating
+		return err
 	}
-	if item.Category != "" {
-		itemInfo.Category = item.Category
+	return writer.Flush()
+}
+
+//GetItemHistory returns the changes of a Item, each with the fields it changed
+func (u *ItemsUseCase) GetItemHistory(ctx context.Context, id int) ([]AuditEntry, error) {
+	entries, err := u.itemRepo.GetItemHistory(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if item.Image != "" {
-		itemInfo.Image = item.Image
+	if len(entries) == 0 {
+		// items created before the history was recorded have none
+		if _, err := u.itemRepo.GetItem(ctx, id); err != nil {
+			return nil, err
+		}
 	}
-	if item.Reputation != 0 {
-		itemInfo.Reputation = item.Reputation
+	for i := range entries {
+		entries[i].Changes, err = diffSnapshots(entries[i].Before, entries[i].After)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to compare the history %w", utils.ErrFetchError)
+		}
 	}
-	if item.Price != 0 {
-		itemInfo.Price = item.Price
+	return entries, nil
+}
+
+//GetItem gets a Item with id
+func (u *ItemsUseCase) GetItem(ctx context.Context, id int) (Item, error) {
+	item, err := u.itemRepo.GetItem(ctx, id)
+	if err != nil {
+		return Item{}, err
 	}
-	if item.Availability != 0 {
-		itemInfo.Availability = item.Availability
+	return item, nil
+}
+
+//UpdateItem replaces the Item with id, every field of item is stored as given. A non zero
+//item.Version replaces it only while it is still at that version
+func (u *ItemsUseCase) UpdateItem(ctx context.Context, item Item) (Item, error) {
+	_, err := u.itemRepo.GetItem(ctx, int(item.ID))
+	if err != nil {
+		return Item{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
 	}
-	if item.Location.City != "" {
-		itemInfo.Location.City = item.Location.City
+	if err := u.geocodeLocation(ctx, &item.Location); err != nil {
+		return Item{}, err
 	}
-	if item.Location.State != "" {
-		itemInfo.Location.State = item.Location.State
+	if err := u.checkAmenities(ctx, item.Amenities); err != nil {
+		return Item{}, err
 	}
-	if item.Location.Country != "" {
-		itemInfo.Location.Country = item.Location.Country
+	updated, err := u.itemRepo.UpdateItem(ctx, item)
+	if err != nil {
+		return Item{}, err
 	}
-	if item.Location.ZipCode != 0 {
-		itemInfo.Location.ZipCode = item.Location.ZipCode
+	return updated, nil
+}
+
+//GetItems returns a page of items matching the filter
+func (u *ItemsUseCase) GetItems(ctx context.Context, filter ItemFilter) (ItemList, error) {
+	items, err := u.itemRepo.GetItems(ctx, filter)
+	if err != nil {
+		return ItemList{}, err
 	}
-	if item.Location.Address != "" {
-		itemInfo.Location.Address = item.Location.Address
+	return items, nil
+}
+
+//ConvertPrices sets the ConvertedPrice of every item to its price in the currency to, with the exchange rates
+//of the moment. An item whose currency, or to, has no exchange rate is an ErrExchangeRateNotFound
+func (u *ItemsUseCase) ConvertPrices(ctx context.Context, items []Item, to string) error {
+	if u.rates == nil {
+		return fmt.Errorf("%w %s", utils.ErrExchangeRateNotFound, to)
 	}
-	err = u.itemRepo.UpdateItem(ctx, itemInfo)
+	table, err := u.rates.RateTable(ctx)
 	if err != nil {
 		return err
 	}
+	for i := range items {
+		conversion, err := table.Convert(items[i].Price, items[i].Currency, to)
+		if err != nil {
+			return err
+		}
+		items[i].ConvertedPrice = &conversion
+	}
 	return nil
 }
 
-//GetItems returns items
-func (u *ItemsUseCase) GetItems(ctx context.Context) ([]Item, error) {
-	items, err := u.itemRepo.GetItems(ctx)
+//SearchItems returns a page of the items matching the search, best match first
+func (u *ItemsUseCase) SearchItems(ctx context.Context, search ItemSearch) (SearchResults, error) {
+	results, err := u.itemRepo.SearchItems(ctx, search)
 	if err != nil {
-		return []Item{}, err
+		return SearchResults{}, err
 	}
-	return items, nil
+	return results, nil
 }
 
-// BookAccommodation book accommodation
-func (u *ItemsUseCase) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) error {
-	itemInfo, err := u.itemRepo.GetItem(ctx, int(bookingInfo.ItemID))
+//NearbyItems returns a page of the items within the radius of the search, nearest first
+func (u *ItemsUseCase) NearbyItems(ctx context.Context, search NearbySearch) (NearbyResults, error) {
+	results, err := u.itemRepo.NearbyItems(ctx, search)
 	if err != nil {
-		return fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		return NearbyResults{}, err
+	}
+	return results, nil
+}
+
+// BookAccommodation book accommodation, the repository checks the rooms left
+// inside the booking transaction so concurrent bookings can not oversell
+func (u *ItemsUseCase) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) (Booking, error) {
+	booking, err := u.itemRepo.BookAccommodation(ctx, bookingInfo)
+	if err != nil {
+		return Booking{}, err
+	}
+	return booking, nil
+}
+
+// checkAmenities rejects amenities that are not in the catalogue with ErrUnknownAmenity
+func (u *ItemsUseCase) checkAmenities(ctx context.Context, codes []string) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	unknown, err := u.itemRepo.UnknownAmenities(ctx, codes)
+	if err != nil {
+		return err
 	}
-	if itemInfo.Availability == 0 {
-		return fmt.Errorf("Item not found %w", utils.ErrRoomsNotEnough)
+	if len(unknown) > 0 {
+		return fmt.Errorf("Unknown amenities %s %w", strings.Join(unknown, ", "), utils.ErrUnknownAmenity)
 	}
-	if bookingInfo.NoOfRooms > itemInfo.Availability {
-		return fmt.Errorf("Item not found %w", utils.ErrRoomsNotEnough)
+	return nil
+}
+
+// geocodeLocation stores the zip code in upper case and checks it against the postal code reference data. It fills in
+// the coordinates, city and state left out and flags a city that is not a place of the zip code. A zip code
+// that is not a postal code of a country with reference data is an ErrInvalidLocation
+func (u *ItemsUseCase) geocodeLocation(ctx context.Context, location *Location) error {
+	location.ZipCode = strings.ToUpper(strings.TrimSpace(location.ZipCode))
+	location.CityMismatch = false
+	if u.geocoder == nil || location.ZipCode == "" {
+		return nil
 	}
-	err = u.itemRepo.BookAccommodation(ctx, bookingInfo)
+	match, err := u.geocoder.Resolve(ctx, location.Country, location.ZipCode, location.City)
 	if err != nil {
 		return err
 	}
+	if !match.CountryKnown {
+		return nil
+	}
+	if !match.Found {
+		return fmt.Errorf("Unknown zip code %s %w", location.ZipCode, utils.ErrInvalidLocation)
+	}
+	if location.City == "" {
+		location.City = match.PostalCode.City
+	} else {
+		location.CityMismatch = !match.CityMatches
+	}
+	if location.State == "" {
+		location.State = match.PostalCode.State
+	}
+	if location.Latitude == nil && location.Longitude == nil {
+		latitude, longitude := match.PostalCode.Latitude, match.PostalCode.Longitude
+		location.Latitude, location.Longitude = &latitude, &longitude
+	}
 	return nil
 }
 
 //NewItemsUseCase method
-func NewItemsUseCase(repo *ItemsRepository) *ItemsUseCase {
-	return &ItemsUseCase{repo}
+func NewItemsUseCase(repo *ItemsRepository, geocoder *geocode.GeocodeUseCase, rates *currency.ExchangeRatesUseCase) *ItemsUseCase {
+	return &ItemsUseCase{itemRepo: repo, geocoder: geocoder, rates: rates}
 }
@@ -1,12 +1,18 @@
 package item
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/sayooj/trivago/currency"
+	"github.com/sayooj/trivago/geocode"
 	"github.com/sayooj/trivago/utils"
 
 	"github.com/stretchr/testify/mock"
@@ -25,7 +31,7 @@ var item = Item{
 		City:    "abcs",
 		State:   "sfddf",
 		Country: "india",
-		ZipCode: 78999,
+		ZipCode: "680001",
 		Address: "sdsfsf  df fdf  df ",
 	},
 }
@@ -44,7 +50,7 @@ var items = []Item{
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "680001",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	},
@@ -61,7 +67,7 @@ var items = []Item{
 			City:    "abcs",
 			State:   "sfddf",
 			Country: "india",
-			ZipCode: 78999,
+			ZipCode: "680001",
 			Address: "sdsfsf  df fdf  df ",
 		},
 	},
@@ -77,13 +83,56 @@ type MockRepo struct {
 	mock.Mock
 }
 
-func (m *MockRepo) AddItem(ctx context.Context, item Item) error {
+type MockGeocoder struct {
+	mock.Mock
+}
+
+func (m *MockGeocoder) Resolve(ctx context.Context, country, zipCode, city string) (geocode.Match, error) {
+	args := m.Called(ctx, country, zipCode, city)
+	return args.Get(0).(geocode.Match), args.Error(1)
+}
+
+func (m *MockGeocoder) LoadPostalCodes(ctx context.Context, r io.Reader) (int, error) {
+	args := m.Called(ctx, r)
+	return args.Int(0), args.Error(1)
+}
+
+type MockRates struct {
+	mock.Mock
+}
+
+func (m *MockRates) GetRates(ctx context.Context) ([]currency.Rate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]currency.Rate), args.Error(1)
+}
+
+func (m *MockRates) SaveRates(ctx context.Context, rates []currency.Rate) ([]currency.Rate, error) {
+	args := m.Called(ctx, rates)
+	return args.Get(0).([]currency.Rate), args.Error(1)
+}
+
+func (m *MockRates) LoadRates(ctx context.Context, r io.Reader) (int, error) {
+	args := m.Called(ctx, r)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRates) RateTable(ctx context.Context) (currency.RateTable, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(currency.RateTable), args.Error(1)
+}
+
+func (m *MockRepo) AddItem(ctx context.Context, item Item) (Item, error) {
 	args := m.Called(ctx, item)
-	return args.Error(0)
+	return args.Get(0).(Item), args.Error(1)
 }
 
-func (m *MockRepo) DeleteItem(ctx context.Context, id int) error {
-	args := m.Called(ctx, id)
+func (m *MockRepo) AddItems(ctx context.Context, items []Item) ([]Item, error) {
+	args := m.Called(ctx, items)
+	return args.Get(0).([]Item), args.Error(1)
+}
+
+func (m *MockRepo) DeleteItem(ctx context.Context, id int, version uint64) error {
+	args := m.Called(ctx, id, version)
 	return args.Error(0)
 }
 
@@ -92,68 +141,311 @@ func (m *MockRepo) GetItem(ctx context.Context, id int) (Item, error) {
 	return args.Get(0).(Item), args.Error(1)
 }
 
-func (m *MockRepo) UpdateItem(ctx context.Context, item Item) error {
+func (m *MockRepo) ItemExists(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) UpdateItem(ctx context.Context, item Item) (Item, error) {
 	args := m.Called(ctx, item)
+	return args.Get(0).(Item), args.Error(1)
+}
+
+func (m *MockRepo) RestoreItem(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockRepo) GetItems(ctx context.Context) ([]Item, error) {
+func (m *MockRepo) PurgeItem(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepo) GetItemHistory(ctx context.Context, id int) ([]AuditEntry, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]AuditEntry), args.Error(1)
+}
+
+func (m *MockRepo) GetItems(ctx context.Context, filter ItemFilter) (ItemList, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(ItemList), args.Error(1)
+}
+
+func (m *MockRepo) SearchItems(ctx context.Context, search ItemSearch) (SearchResults, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).(SearchResults), args.Error(1)
+}
+
+func (m *MockRepo) NearbyItems(ctx context.Context, search NearbySearch) (NearbyResults, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).(NearbyResults), args.Error(1)
+}
+
+func (m *MockRepo) ExportItems(ctx context.Context, filter ItemFilter, each func(Item) error) error {
+	args := m.Called(ctx, filter, each)
+	for _, i := range args.Get(0).([]Item) {
+		if err := each(i); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockRepo) UnknownAmenities(ctx context.Context, codes []string) ([]string, error) {
+	args := m.Called(ctx, codes)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepo) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
 	args := m.Called(ctx)
-	return args.Get(0).([]Item), args.Error(1)
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return args.Error(0)
 }
 
-func (m *MockRepo) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) error {
+func (m *MockRepo) BookAccommodation(ctx context.Context, bookingInfo BookAccommodation) (Booking, error) {
 	args := m.Called(ctx, bookingInfo)
-	return args.Error(0)
+	return args.Get(0).(Booking), args.Error(1)
 }
 
 func TestAddItem(t *testing.T) {
 	repo := new(MockRepo)
-	repo.On("AddItem", context.Background(), item).Return(nil)
-	uc := ItemsUseCase{repo}
-	uc.AddItem(context.Background(), item)
+	created := item
+	created.ReputationBadge = "green"
+	repo.On("AddItem", context.Background(), item).Return(created, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	res, err := uc.AddItem(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, "green", res.ReputationBadge)
+	repo.AssertExpectations(t)
+}
+
+func TestConvertPrices(t *testing.T) {
+	rates := new(MockRates)
+	rates.On("RateTable", context.Background()).Return(currency.NewRateTable([]currency.Rate{{Currency: "USD", Rate: 1.0853}, {Currency: "JPY", Rate: 160}}), nil)
+	uc := ItemsUseCase{itemRepo: new(MockRepo), rates: rates}
+	items := []Item{{ID: 1, Price: 1000, Currency: "EUR"}, {ID: 2, Price: 100, Currency: "JPY"}}
+	err := uc.ConvertPrices(context.Background(), items, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1085), items[0].ConvertedPrice.Amount)
+	assert.Equal(t, uint64(68), items[1].ConvertedPrice.Amount)
+	assert.Equal(t, "USD", items[1].ConvertedPrice.Currency)
+	rates.AssertExpectations(t)
+}
+
+func TestConvertPricesNoRate(t *testing.T) {
+	rates := new(MockRates)
+	rates.On("RateTable", context.Background()).Return(currency.NewRateTable(nil), nil)
+	uc := ItemsUseCase{itemRepo: new(MockRepo), rates: rates}
+	err := uc.ConvertPrices(context.Background(), []Item{{ID: 1, Price: 1000, Currency: "EUR"}}, "GBP")
+	assert.True(t, errors.Is(err, utils.ErrExchangeRateNotFound))
+	rates.AssertExpectations(t)
+}
+
+func TestAddItemGeocoded(t *testing.T) {
+	repo := new(MockRepo)
+	geocoder := new(MockGeocoder)
+	place := geocode.PostalCode{City: "sfddf", State: "kerala", Latitude: 10.5, Longitude: 76.2}
+	geocoder.On("Resolve", context.Background(), "india", "680001", "abcs").Return(geocode.Match{CountryKnown: true, Found: true, PostalCode: place}, nil)
+	repo.On("AddItem", context.Background(), mock.MatchedBy(func(i Item) bool {
+		return i.Location.CityMismatch && *i.Location.Latitude == 10.5 && *i.Location.Longitude == 76.2 && i.Location.State == "sfddf"
+	})).Return(item, nil)
+	uc := ItemsUseCase{itemRepo: repo, geocoder: geocoder}
+	_, err := uc.AddItem(context.Background(), item)
+	assert.NoError(t, err)
 	repo.AssertExpectations(t)
 }
 
+func TestAddItemUnknownAmenities(t *testing.T) {
+	repo := new(MockRepo)
+	withAmenities := item
+	withAmenities.Amenities = []string{"wifi", "helipad"}
+	repo.On("UnknownAmenities", context.Background(), withAmenities.Amenities).Return([]string{"helipad"}, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.AddItem(context.Background(), withAmenities)
+	assert.True(t, errors.Is(err, utils.ErrUnknownAmenity))
+	assert.Contains(t, err.Error(), "helipad")
+	repo.AssertExpectations(t)
+}
+
+func TestAddItemInvalidLocation(t *testing.T) {
+	repo := new(MockRepo)
+	geocoder := new(MockGeocoder)
+	geocoder.On("Resolve", context.Background(), "india", "680001", "abcs").Return(geocode.Match{CountryKnown: true}, nil)
+	uc := ItemsUseCase{itemRepo: repo, geocoder: geocoder}
+	_, err := uc.AddItem(context.Background(), item)
+	assert.True(t, errors.Is(err, utils.ErrInvalidLocation))
+	repo.AssertNotCalled(t, "AddItem", mock.Anything, mock.Anything)
+}
+
 func TestAddFail(t *testing.T) {
 	repo := new(MockRepo)
-	repo.On("AddItem", context.Background(), item).Return(errors.New("Error"))
-	uc := ItemsUseCase{repo}
-	uc.AddItem(context.Background(), item)
+	repo.On("AddItem", context.Background(), item).Return(Item{}, errors.New("Error"))
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.AddItem(context.Background(), item)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestImportItems(t *testing.T) {
+	repo := new(MockRepo)
+	valid := item
+	valid.ID = 0
+	upload := ""
+	for i := 0; i < importBatchSize+1; i++ {
+		upload += `{"name": "hotel abcdefghijk", "rating": 5, "category": "hotel", "image": "http://abc.com/img.jpg", "reputation": 800, "price": 1000, "availability": 10, ` +
+			`"location": {"city": "tsr", "state": "kerala", "country": "india", "zip_code": "680001", "address": "street 1"}}` + "\n"
+		if i == 1 {
+			upload += `{"name": "no"}` + "\n"
+		}
+	}
+	created := func(n int) []Item {
+		items := make([]Item, n)
+		for i := range items {
+			items[i].ID = uint64(i + 1)
+		}
+		return items
+	}
+	repo.On("AddItems", context.Background(), mock.MatchedBy(func(items []Item) bool { return len(items) == importBatchSize })).Return(created(importBatchSize), nil).Once()
+	repo.On("AddItems", context.Background(), mock.MatchedBy(func(items []Item) bool { return len(items) == 1 })).Return([]Item{}, utils.ErrItemNotAdded).Once()
+	uc := ItemsUseCase{itemRepo: repo}
+	reader, _ := NewItemReader(strings.NewReader(upload), "jsonl")
+	report, err := uc.ImportItems(context.Background(), reader)
+	assert.True(t, errors.Is(err, utils.ErrItemNotAdded))
+	assert.Equal(t, importBatchSize, report.Accepted)
+	assert.Equal(t, 1, report.Rejected)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, ImportRowResult{Row: 1, Status: "accepted", ID: 1}, report.Rows[0])
+	assert.Equal(t, 3, report.Rows[2].Row)
+	assert.Equal(t, "rejected", report.Rows[2].Status)
+	assert.Equal(t, "failed", report.Rows[len(report.Rows)-1].Status)
+	repo.AssertExpectations(t)
+}
+
+func TestImportItemsReadErrorNotStored(t *testing.T) {
+	repo := new(MockRepo)
+	upload := `{"name": "hotel abcdefghijk", "rating": 5, "category": "hotel", "image": "http://abc.com/img.jpg", "reputation": 800, "price": 1000, "availability": 10, ` +
+		`"location": {"city": "tsr", "state": "kerala", "country": "india", "zip_code": "680001", "address": "street 1"}}` + "\n"
+	repo.On("AddItems", context.Background(), mock.Anything).Return([]Item{}, utils.ErrItemNotAdded).Once()
+	uc := ItemsUseCase{itemRepo: repo}
+	// the upload breaks off after its first row
+	reader, _ := NewItemReader(io.MultiReader(strings.NewReader(upload), iotest.TimeoutReader(strings.NewReader("x"))), "jsonl")
+	report, err := uc.ImportItems(context.Background(), reader)
+	// the rows read before the upload broke could not be stored, that is what the error says
+	assert.True(t, errors.Is(err, utils.ErrItemNotAdded))
+	assert.Contains(t, err.Error(), "Failed to read the import")
+	assert.Equal(t, 1, report.Failed)
+	repo.AssertExpectations(t)
+}
+
+func TestBatch(t *testing.T) {
+	repo := new(MockRepo)
+	newItem := item
+	newItem.ID = 0
+	created := item
+	created.Version = 1
+	repo.On("AddItem", context.Background(), newItem).Return(created, nil)
+	repo.On("GetItem", context.Background(), 5).Return(Item{}, utils.ErrItemNotFound)
+	uc := ItemsUseCase{itemRepo: repo}
+	batch := BatchRequest{Operations: []BatchOperation{
+		{Ref: "a", Op: "create", Item: &newItem},
+		{Ref: "b", Op: "delete", ID: 5},
+		{Ref: "c", Op: "update", ID: 1},
+	}}
+	res, err := uc.Batch(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.True(t, res.Committed)
+	assert.Equal(t, uint64(1), res.Results[0].ID)
+	assert.Equal(t, `"1"`, res.Results[0].ETag)
+	assert.NoError(t, res.Results[0].err)
+	assert.True(t, errors.Is(res.Results[1].err, utils.ErrItemNotFound))
+	assert.True(t, errors.Is(res.Results[2].err, utils.ErrInvalidBatchOperation))
+	assert.Equal(t, "item", res.Results[2].InvalidParams[0].Name)
+	repo.AssertExpectations(t)
+}
+
+func TestBatchAtomicRollback(t *testing.T) {
+	repo := new(MockRepo)
+	newItem := item
+	newItem.ID = 0
+	repo.On("WithTransaction", context.Background()).Return(nil)
+	repo.On("AddItem", context.Background(), newItem).Return(item, nil)
+	repo.On("GetItem", context.Background(), 5).Return(Item{}, utils.ErrItemNotFound)
+	uc := ItemsUseCase{itemRepo: repo}
+	batch := BatchRequest{Atomic: true, Operations: []BatchOperation{
+		{Ref: "a", Op: "create", Item: &newItem},
+		{Ref: "b", Op: "delete", ID: 5},
+		{Ref: "c", Op: "delete", ID: 6},
+	}}
+	res, err := uc.Batch(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.False(t, res.Committed)
+	assert.Equal(t, BatchOperationResult{Ref: "a", Op: "create", err: utils.ErrBatchRolledBack}, res.Results[0])
+	assert.True(t, errors.Is(res.Results[1].err, utils.ErrItemNotFound))
+	assert.Equal(t, utils.ErrBatchRolledBack, res.Results[2].err)
+	repo.AssertNotCalled(t, "GetItem", context.Background(), 6)
+	repo.AssertExpectations(t)
+}
+
+func TestBatchAtomicInvalid(t *testing.T) {
+	repo := new(MockRepo)
+	uc := ItemsUseCase{itemRepo: repo}
+	batch := BatchRequest{Atomic: true, Operations: []BatchOperation{
+		{Ref: "a", Op: "delete", ID: 1},
+		{Ref: "b", Op: "move", ID: 1},
+	}}
+	res, err := uc.Batch(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.False(t, res.Committed)
+	assert.Equal(t, utils.ErrBatchRolledBack, res.Results[0].err)
+	assert.True(t, errors.Is(res.Results[1].err, utils.ErrInvalidBatchOperation))
+	repo.AssertNotCalled(t, "WithTransaction", mock.Anything)
+}
+
+func TestBatchAtomicCommitFailed(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("WithTransaction", context.Background()).Return(utils.ErrTransactionCommitFailed)
+	repo.On("GetItem", context.Background(), 1).Return(item, nil)
+	repo.On("DeleteItem", context.Background(), 1, uint64(0)).Return(nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	batch := BatchRequest{Atomic: true, Operations: []BatchOperation{{Ref: "a", Op: "delete", ID: 1}}}
+	_, err := uc.Batch(context.Background(), batch)
+	assert.True(t, errors.Is(err, utils.ErrTransactionCommitFailed))
 	repo.AssertExpectations(t)
 }
 
 func TestDeleteItemSuccess(t *testing.T) {
 	repo := new(MockRepo)
 	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	repo.On("DeleteItem", context.Background(), 1).Return(nil)
-	uc := ItemsUseCase{repo}
-	uc.DeleteItem(context.Background(), 1)
+	repo.On("DeleteItem", context.Background(), 1, uint64(0)).Return(nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	uc.DeleteItem(context.Background(), 1, 0)
 	repo.AssertExpectations(t)
 }
 
 func TestDeleteItemItemNotFound(t *testing.T) {
 	repo := new(MockRepo)
 	repo.On("GetItem", context.Background(), 1).Return(Item{}, utils.ErrItemNotFound)
-	// repo.On("DeleteItem", context.Background(), 1).Return(nil)
-	uc := ItemsUseCase{repo}
-	uc.DeleteItem(context.Background(), 1)
+	// repo.On("DeleteItem", context.Background(), 1, uint64(0)).Return(nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	uc.DeleteItem(context.Background(), 1, 0)
 	repo.AssertExpectations(t)
 }
 
 func TestDeleteItemFail(t *testing.T) {
 	repo := new(MockRepo)
 	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	repo.On("DeleteItem", context.Background(), 1).Return(utils.ErrItemNotDeleted)
-	uc := ItemsUseCase{repo}
-	uc.DeleteItem(context.Background(), 1)
+	repo.On("DeleteItem", context.Background(), 1, uint64(0)).Return(utils.ErrItemNotDeleted)
+	uc := ItemsUseCase{itemRepo: repo}
+	uc.DeleteItem(context.Background(), 1, 0)
 	repo.AssertExpectations(t)
 }
 
 func TestGetItemSuccess(t *testing.T) {
 	repo := new(MockRepo)
 	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	uc := ItemsUseCase{repo}
+	uc := ItemsUseCase{itemRepo: repo}
 	res, err := uc.GetItem(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(1), res.ID)
@@ -163,7 +455,7 @@ func TestGetItemSuccess(t *testing.T) {
 func TestGetItemFail(t *testing.T) {
 	repo := new(MockRepo)
 	repo.On("GetItem", context.Background(), 1).Return(Item{}, utils.ErrItemNotFound)
-	uc := ItemsUseCase{repo}
+	uc := ItemsUseCase{itemRepo: repo}
 	_, err := uc.GetItem(context.Background(), 1)
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
@@ -172,81 +464,135 @@ func TestGetItemFail(t *testing.T) {
 func TestUpdateItemSuccess(t *testing.T) {
 	repo := new(MockRepo)
 	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	repo.On("UpdateItem", context.Background(), item).Return(nil)
-	uc := ItemsUseCase{repo}
-	err := uc.UpdateItem(context.Background(), item)
+	updated := item
+	updated.Version = 2
+	repo.On("UpdateItem", context.Background(), item).Return(updated, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	res, err := uc.UpdateItem(context.Background(), item)
 	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), res.Version)
 	repo.AssertExpectations(t)
 }
 
 func TestUpdateItemFail(t *testing.T) {
 	repo := new(MockRepo)
 	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	repo.On("UpdateItem", context.Background(), item).Return(utils.ErrItemNotUpdated)
-	uc := ItemsUseCase{repo}
-	err := uc.UpdateItem(context.Background(), item)
+	repo.On("UpdateItem", context.Background(), item).Return(Item{}, utils.ErrItemNotUpdated)
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.UpdateItem(context.Background(), item)
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
 }
 
-func TestGetItemsSuccess(t *testing.T) {
+func TestRestoreItem(t *testing.T) {
 	repo := new(MockRepo)
-	repo.On("GetItems", context.Background()).Return(items, nil)
-	uc := ItemsUseCase{repo}
-	res, err := uc.GetItems(context.Background())
+	repo.On("RestoreItem", context.Background(), 1).Return(nil)
+	repo.On("GetItem", context.Background(), 1).Return(item, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	res, err := uc.RestoreItem(context.Background(), 1)
 	assert.NoError(t, err)
-	assert.Equal(t, res[0].ID, uint64(1))
-	assert.Equal(t, res[1].ID, uint64(2))
+	assert.Equal(t, item, res)
 	repo.AssertExpectations(t)
 }
 
-func TestGetItemsFail(t *testing.T) {
+func TestRestoreItemNotDeleted(t *testing.T) {
 	repo := new(MockRepo)
-	repo.On("GetItems", context.Background()).Return([]Item{}, utils.ErrFetchError)
-	uc := ItemsUseCase{repo}
-	_, err := uc.GetItems(context.Background())
-	assert.Error(t, err)
+	repo.On("RestoreItem", context.Background(), 1).Return(utils.ErrItemNotFound)
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.RestoreItem(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	repo.AssertNotCalled(t, "GetItem", context.Background(), 1)
+}
+
+func TestGetItemHistory(t *testing.T) {
+	repo := new(MockRepo)
+	history := []AuditEntry{
+		{ID: 1, ItemID: 1, Action: "create", After: []byte(`{"name":"hotel abcd","price":1000,"location":{"city":"tsr"}}`)},
+		{ID: 2, ItemID: 1, Action: "update", Before: []byte(`{"name":"hotel abcd","price":1000,"location":{"city":"tsr"}}`), After: []byte(`{"name":"hotel abcd","price":1200,"location":{"city":"kochi"}}`)},
+	}
+	repo.On("GetItemHistory", context.Background(), 1).Return(history, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	res, err := uc.GetItemHistory(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, res[0].Changes, 3)
+	assert.Equal(t, []FieldChange{
+		{Field: "location.city", From: "tsr", To: "kochi"},
+		{Field: "price", From: float64(1000), To: float64(1200)},
+	}, res[1].Changes)
 	repo.AssertExpectations(t)
 }
 
-func TestBookAccommodationSuccess(t *testing.T) {
+func TestGetItemHistoryItemNotFound(t *testing.T) {
 	repo := new(MockRepo)
-	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	repo.On("BookAccommodation", context.Background(), bookingInfo).Return(nil)
-	uc := ItemsUseCase{repo}
-	err := uc.BookAccommodation(context.Background(), bookingInfo)
+	repo.On("GetItemHistory", context.Background(), 1).Return([]AuditEntry{}, nil)
+	repo.On("GetItem", context.Background(), 1).Return(Item{}, utils.ErrItemNotFound)
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.GetItemHistory(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+}
+
+func TestGetItemsSuccess(t *testing.T) {
+	repo := new(MockRepo)
+	filter := ItemFilter{Limit: 10}
+	total := 2
+	repo.On("GetItems", context.Background(), filter).Return(ItemList{Items: items, Meta: ListMeta{Total: &total, Limit: 10}}, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	res, err := uc.GetItems(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, res.Items[0].ID, uint64(1))
+	assert.Equal(t, res.Items[1].ID, uint64(2))
+	assert.Equal(t, *res.Meta.Total, 2)
+	repo.AssertExpectations(t)
+}
+
+func TestExportItems(t *testing.T) {
+	repo := new(MockRepo)
+	filter := ItemFilter{Category: "hotel"}
+	repo.On("ExportItems", context.Background(), filter, mock.Anything).Return(items, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	var out bytes.Buffer
+	writer, _ := NewItemWriter(&out, "jsonl")
+	err := uc.ExportItems(context.Background(), filter, writer)
 	assert.NoError(t, err)
+	assert.Equal(t, len(items), strings.Count(out.String(), "\n"))
 	repo.AssertExpectations(t)
 }
 
-func TestBookAccommodationZeroRooms(t *testing.T) {
+func TestGetItemsFail(t *testing.T) {
 	repo := new(MockRepo)
-	newitem := item
-	newitem.Availability = 0
-	repo.On("GetItem", context.Background(), 1).Return(newitem, nil)
-	uc := ItemsUseCase{repo}
-	err := uc.BookAccommodation(context.Background(), bookingInfo)
+	repo.On("GetItems", context.Background(), ItemFilter{Limit: 10}).Return(ItemList{}, utils.ErrFetchError)
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.GetItems(context.Background(), ItemFilter{Limit: 10})
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
 }
 
+func TestBookAccommodationSuccess(t *testing.T) {
+	repo := new(MockRepo)
+	repo.On("BookAccommodation", context.Background(), bookingInfo).Return(booking, nil)
+	uc := ItemsUseCase{itemRepo: repo}
+	res, err := uc.BookAccommodation(context.Background(), bookingInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, booking, res)
+	repo.AssertExpectations(t)
+}
+
 func TestBookAccommodationNotEnoughRooms(t *testing.T) {
 	repo := new(MockRepo)
 	newBooking := bookingInfo
 	newBooking.NoOfRooms = 11
-	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	uc := ItemsUseCase{repo}
-	err := uc.BookAccommodation(context.Background(), newBooking)
-	assert.Error(t, err)
+	repo.On("BookAccommodation", context.Background(), newBooking).Return(Booking{}, utils.ErrRoomsNotEnough)
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.BookAccommodation(context.Background(), newBooking)
+	assert.True(t, errors.Is(err, utils.ErrRoomsNotEnough))
 	repo.AssertExpectations(t)
 }
 
 func TestBookAccommodationFail(t *testing.T) {
 	repo := new(MockRepo)
-	repo.On("GetItem", context.Background(), 1).Return(item, nil)
-	repo.On("BookAccommodation", context.Background(), bookingInfo).Return(utils.ErrBookingFailed)
-	uc := ItemsUseCase{repo}
-	err := uc.BookAccommodation(context.Background(), bookingInfo)
+	repo.On("BookAccommodation", context.Background(), bookingInfo).Return(Booking{}, utils.ErrBookingFailed)
+	uc := ItemsUseCase{itemRepo: repo}
+	_, err := uc.BookAccommodation(context.Background(), bookingInfo)
 	assert.Error(t, err)
 	repo.AssertExpectations(t)
 }
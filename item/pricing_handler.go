@@ -0,0 +1,186 @@
+package item
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+//PricingHandler handler for the pricing rules and quotes of items
+type PricingHandler struct {
+	useCase PricingUseCaseInterface
+	logger  *logrus.Logger
+}
+
+//GetPricingRules get the pricing rules of an item
+func (h *PricingHandler) GetPricingRules(w http.ResponseWriter, r *http.Request) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	rules, err := h.useCase.GetPricingRules(r.Context(), itemID)
+	if err != nil {
+		h.respondWithPricingError(w, err, "Error occured while fetching the pricing rules")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, rules)
+}
+
+//GetPricingRule get a pricing rule of an item
+func (h *PricingHandler) GetPricingRule(w http.ResponseWriter, r *http.Request) {
+	itemID, ruleID, ok := pricingRuleURLParams(w, r)
+	if !ok {
+		return
+	}
+	rule, err := h.useCase.GetPricingRule(r.Context(), itemID, ruleID)
+	if err != nil {
+		h.respondWithPricingError(w, err, "Error occured while fetching the pricing rule")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, rule)
+}
+
+//AddPricingRule add a pricing rule to an item
+func (h *PricingHandler) AddPricingRule(w http.ResponseWriter, r *http.Request) {
+	var rule PricingRule
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	if !h.decodePricingRule(w, r, &rule) {
+		return
+	}
+	rule.ItemID = uint64(itemID)
+	created, err := h.useCase.AddPricingRule(r.Context(), rule)
+	if err != nil {
+		h.respondWithPricingError(w, err, "Failed to add pricing rule")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/item/%d/pricing-rules/%d", itemID, created.ID))
+	utils.RespondWithJSON(w, http.StatusCreated, created)
+}
+
+//UpdatePricingRule replace a pricing rule of an item
+func (h *PricingHandler) UpdatePricingRule(w http.ResponseWriter, r *http.Request) {
+	var rule PricingRule
+	itemID, ruleID, ok := pricingRuleURLParams(w, r)
+	if !ok {
+		return
+	}
+	if !h.decodePricingRule(w, r, &rule) {
+		return
+	}
+	rule.ID, rule.ItemID = uint64(ruleID), uint64(itemID)
+	updated, err := h.useCase.UpdatePricingRule(r.Context(), rule)
+	if err != nil {
+		h.respondWithPricingError(w, err, "Failed to update pricing rule")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, updated)
+}
+
+//DeletePricingRule remove a pricing rule from an item
+func (h *PricingHandler) DeletePricingRule(w http.ResponseWriter, r *http.Request) {
+	itemID, ruleID, ok := pricingRuleURLParams(w, r)
+	if !ok {
+		return
+	}
+	err := h.useCase.DeletePricingRule(r.Context(), itemID, ruleID)
+	if err != nil {
+		h.respondWithPricingError(w, err, "Failed to delete pricing rule")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+//GetQuote price a stay, GET /item/56/quote?checkin=2030-01-10&checkout=2030-01-12&rooms=2&room_type_id=3
+func (h *PricingHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	request, invalidParams := NewQuoteRequest(uint64(itemID), r.URL.Query())
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid query parameters")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	quote, err := h.useCase.GetQuote(r.Context(), request)
+	if err != nil {
+		h.respondWithPricingError(w, err, "Error occured while pricing the stay")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, quote)
+}
+
+//pricingRuleURLParams reads the item and pricing rule ids of the path, ok is false when a response has already been written
+func pricingRuleURLParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return 0, 0, false
+	}
+	ruleID, err := strconv.Atoi(chi.URLParam(r, "ruleId"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid pricing rule id")
+		return 0, 0, false
+	}
+	return itemID, ruleID, true
+}
+
+//decodePricingRule decodes and validates the body, it is false when a response has already been written
+func (h *PricingHandler) decodePricingRule(w http.ResponseWriter, r *http.Request, rule *PricingRule) bool {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(rule); err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return false
+	}
+	invalidParams := rule.Validate()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return false
+	}
+	return true
+}
+
+//respondWithPricingError maps the errors of the pricing rules and quotes, message describes any other error
+func (h *PricingHandler) respondWithPricingError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, utils.ErrPricingRuleNotFound) {
+		h.logger.Info("Pricing rule not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Pricing rule not found")
+		return
+	}
+	if errors.Is(err, utils.ErrRoomTypeNotFound) {
+		h.logger.Info("Room type not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Room type not found")
+		return
+	}
+	if errors.Is(err, utils.ErrRoomTypeRequired) {
+		h.logger.Info("Room type required")
+		utils.RespondWithError(w, http.StatusBadRequest, utils.ErrRoomTypeRequired.Error())
+		return
+	}
+	h.logger.Info(message)
+	utils.RespondWithError(w, http.StatusInternalServerError, message)
+}
+
+//NewPricingHandler method
+func NewPricingHandler(useCase *PricingUseCase, log *logrus.Logger) *PricingHandler {
+	return &PricingHandler{useCase, log}
+}
@@ -0,0 +1,116 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+type MockPricingUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPricingUseCase) GetPricingRules(ctx context.Context, itemID int) ([]PricingRule, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]PricingRule), args.Error(1)
+}
+
+func (m *MockPricingUseCase) GetPricingRule(ctx context.Context, itemID, ruleID int) (PricingRule, error) {
+	args := m.Called(ctx, itemID, ruleID)
+	return args.Get(0).(PricingRule), args.Error(1)
+}
+
+func (m *MockPricingUseCase) AddPricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(PricingRule), args.Error(1)
+}
+
+func (m *MockPricingUseCase) UpdatePricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(PricingRule), args.Error(1)
+}
+
+func (m *MockPricingUseCase) DeletePricingRule(ctx context.Context, itemID, ruleID int) error {
+	args := m.Called(ctx, itemID, ruleID)
+	return args.Error(0)
+}
+
+func (m *MockPricingUseCase) GetQuote(ctx context.Context, request QuoteRequest) (Quote, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(Quote), args.Error(1)
+}
+
+func TestAddPricingRuleHandler(t *testing.T) {
+	uc := new(MockPricingUseCase)
+	ph := PricingHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/pricing-rules", `{"name":"Weekend","kind":"nightly","weekdays":["friday","saturday"],"price":1500}`, "id", "1")
+	uc.On("AddPricingRule", req.Context(), PricingRule{ItemID: 1, Name: "Weekend", Kind: "nightly", Weekdays: []string{"friday", "saturday"}, Price: 1500}).Return(pricingRule, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ph.AddPricingRule).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/item/1/pricing-rules/4", rr.Header().Get("Location"))
+	uc.AssertExpectations(t)
+}
+
+func TestAddPricingRuleHandlerInvalid(t *testing.T) {
+	uc := new(MockPricingUseCase)
+	ph := PricingHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/pricing-rules", `{"name":"Weekend","kind":"nightly"}`, "id", "1")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ph.AddPricingRule).ServeHTTP(rr, req)
+	var res utils.ErrorModel
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "price", res.InvalidParams[0].Name)
+	uc.AssertExpectations(t)
+}
+
+func TestDeletePricingRuleHandlerNotFound(t *testing.T) {
+	uc := new(MockPricingUseCase)
+	ph := PricingHandler{uc, logrus.New()}
+	req := newRouteRequest("DELETE", "/item/1/pricing-rules/9", "", "id", "1", "ruleId", "9")
+	uc.On("DeletePricingRule", req.Context(), 1, 9).Return(utils.ErrPricingRuleNotFound)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ph.DeletePricingRule).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetQuoteHandler(t *testing.T) {
+	uc := new(MockPricingUseCase)
+	ph := PricingHandler{uc, logrus.New()}
+	checkIn := time.Now().UTC().AddDate(0, 0, 7).Format("2006-01-02")
+	checkOut := time.Now().UTC().AddDate(0, 0, 8).Format("2006-01-02")
+	req := newRouteRequest("GET", "/item/1/quote?checkin="+checkIn+"&checkout="+checkOut+"&rooms=2", "", "id", "1")
+	request := QuoteRequest{ItemID: 1, Rooms: 2, CheckIn: checkIn, CheckOut: checkOut}
+	quote := Quote{ItemID: 1, Rooms: 2, CheckIn: checkIn, CheckOut: checkOut, Nights: []QuoteNight{{Date: checkIn, Price: 1000}}, Subtotal: 2000, Total: 2000}
+	uc.On("GetQuote", req.Context(), request).Return(quote, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ph.GetQuote).ServeHTTP(rr, req)
+	var res Quote
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, quote, res)
+	uc.AssertExpectations(t)
+}
+
+func TestGetQuoteHandlerInvalid(t *testing.T) {
+	uc := new(MockPricingUseCase)
+	ph := PricingHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/quote?checkin=2030-01-10", "", "id", "1")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(ph.GetQuote).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
@@ -0,0 +1,207 @@
+package item
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//PricingRule changes the price of an item, or of one of its room types with RoomTypeID. A nightly rule sets
+//the Price of the nights between StartDate and EndDate, both included, falling on its Weekdays, the rule
+//with the highest Priority wins. A stay_discount rule takes DiscountPercent off stays of MinNights or more
+//checking in between its dates, the discount of the longest MinNights reached applies. Every rule only
+//applies to stays of MinNights or more, missing dates or weekdays do not restrict it
+type PricingRule struct {
+	ID              uint64    `json:"id"`
+	ItemID          uint64    `json:"item_id"`
+	RoomTypeID      uint64    `json:"room_type_id,omitempty"`
+	Name            string    `json:"name"`
+	Kind            string    `json:"kind"`
+	StartDate       string    `json:"start_date,omitempty"`
+	EndDate         string    `json:"end_date,omitempty"`
+	Weekdays        []string  `json:"weekdays"`
+	MinNights       uint      `json:"min_nights"`
+	Price           uint64    `json:"price,omitempty"`
+	DiscountPercent uint      `json:"discount_percent,omitempty"`
+	Priority        int       `json:"priority"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+//Validate validates the pricing rule
+func (p PricingRule) Validate() []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if p.Name == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "name", Reason: "name required"})
+	}
+	if utf8.RuneCountInString(p.Name) > maxPricingRuleNameLength {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "name", Reason: fmt.Sprintf("name should be at most %d characters", maxPricingRuleNameLength)})
+	}
+	switch p.Kind {
+	case pricingRuleNightly:
+		if p.Price == 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "price", Reason: "price required"})
+		}
+		if p.DiscountPercent != 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "discount_percent", Reason: "discount_percent is only for stay_discount rules"})
+		}
+	case pricingRuleStayDiscount:
+		if p.DiscountPercent == 0 || p.DiscountPercent > 100 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "discount_percent", Reason: "discount_percent should be > 0 and <= 100"})
+		}
+		if p.Price != 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "price", Reason: "price is only for nightly rules"})
+		}
+		if len(p.Weekdays) > 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "weekdays", Reason: "weekdays are only for nightly rules"})
+		}
+	default:
+		validationErr = append(validationErr, utils.InvalidParams{Name: "kind", Reason: fmt.Sprintf("kind should be %s or %s", pricingRuleNightly, pricingRuleStayDiscount)})
+	}
+	startDate, err := time.Parse(dateLayout, p.StartDate)
+	if p.StartDate != "" && err != nil {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "start_date", Reason: "start_date should be a date in YYYY-MM-DD format"})
+	}
+	endDate, err := time.Parse(dateLayout, p.EndDate)
+	if p.EndDate != "" && err != nil {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "end_date", Reason: "end_date should be a date in YYYY-MM-DD format"})
+	}
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "end_date", Reason: "end_date should not be before start_date"})
+	}
+	for _, weekday := range p.Weekdays {
+		if _, ok := weekdayNames[weekday]; !ok {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "weekdays", Reason: "weekdays should be lower case day names like saturday"})
+			break
+		}
+	}
+	return validationErr
+}
+
+//covers tells whether date is between the dates of the rule
+func (p PricingRule) covers(date time.Time) bool {
+	if startDate, err := time.Parse(dateLayout, p.StartDate); err == nil && date.Before(startDate) {
+		return false
+	}
+	if endDate, err := time.Parse(dateLayout, p.EndDate); err == nil && date.After(endDate) {
+		return false
+	}
+	return true
+}
+
+//fallsOn tells whether date is one of the weekdays of the rule
+func (p PricingRule) fallsOn(date time.Time) bool {
+	if len(p.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range p.Weekdays {
+		if weekdayNames[weekday] == date.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+//QuoteRequest asks the price of a stay of Rooms rooms, of a room type of the item with RoomTypeID
+type QuoteRequest struct {
+	ItemID     uint64
+	RoomTypeID uint64
+	Rooms      uint
+	CheckIn    string
+	CheckOut   string
+}
+
+//NewQuoteRequest reads a quote request from the query of GET /item/{id}/quote, rooms defaults to 1
+func NewQuoteRequest(itemID uint64, query url.Values) (QuoteRequest, []utils.InvalidParams) {
+	request := QuoteRequest{ItemID: itemID, Rooms: 1, CheckIn: query.Get("checkin"), CheckOut: query.Get("checkout")}
+	validationErr := validateStay(request.CheckIn, request.CheckOut, "checkin", "checkout")
+	if v := query.Get("rooms"); v != "" {
+		rooms, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || rooms == 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "rooms", Reason: "rooms should be a number > 0"})
+		}
+		request.Rooms = uint(rooms)
+	}
+	if v := query.Get("room_type_id"); v != "" {
+		roomTypeID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || roomTypeID == 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "room_type_id", Reason: "room_type_id should be a number > 0"})
+		}
+		request.RoomTypeID = roomTypeID
+	}
+	return request, validationErr
+}
+
+//Quote is the price of a stay in minor units of Currency, the currency of the item. Subtotal is the price of
+//every night times the rooms, Discount is the DiscountPercent of the Subtotal rounded to the nearest minor
+//unit, half up, and Total is what is left
+type Quote struct {
+	ItemID          uint64       `json:"item_id"`
+	RoomTypeID      uint64       `json:"room_type_id,omitempty"`
+	CheckIn         string       `json:"check_in"`
+	CheckOut        string       `json:"check_out"`
+	Rooms           uint         `json:"rooms"`
+	Nights          []QuoteNight `json:"nights"`
+	Currency        string       `json:"currency"`
+	Subtotal        uint64       `json:"subtotal"`
+	DiscountRule    string       `json:"discount_rule,omitempty"`
+	DiscountPercent uint         `json:"discount_percent,omitempty"`
+	Discount        uint64       `json:"discount"`
+	Total           uint64       `json:"total"`
+}
+
+//QuoteNight is the price of a room on a night of the stay, Rule names the nightly rule that set it
+type QuoteNight struct {
+	Date  string `json:"date"`
+	Price uint64 `json:"price"`
+	Rule  string `json:"rule,omitempty"`
+}
+
+//quoteStay prices the stay of the request, every night costs price unless a nightly rule sets its price
+func quoteStay(request QuoteRequest, price uint64, rules []PricingRule) Quote {
+	quote := Quote{ItemID: request.ItemID, RoomTypeID: request.RoomTypeID, CheckIn: request.CheckIn, CheckOut: request.CheckOut, Rooms: request.Rooms, Nights: []QuoteNight{}}
+	nights := stayNights(request.CheckIn, request.CheckOut)
+	checkIn, err := time.Parse(dateLayout, request.CheckIn)
+	if err != nil {
+		return quote
+	}
+
+	for n := 0; n < nights; n++ {
+		date := checkIn.AddDate(0, 0, n)
+		night := QuoteNight{Date: date.Format(dateLayout), Price: price}
+		var best *PricingRule
+		for i, rule := range rules {
+			if rule.Kind != pricingRuleNightly || int(rule.MinNights) > nights || !rule.covers(date) || !rule.fallsOn(date) {
+				continue
+			}
+			if best == nil || rule.Priority > best.Priority || (rule.Priority == best.Priority && rule.ID > best.ID) {
+				best = &rules[i]
+			}
+		}
+		if best != nil {
+			night.Price, night.Rule = best.Price, best.Name
+		}
+		quote.Nights = append(quote.Nights, night)
+		quote.Subtotal += night.Price * uint64(request.Rooms)
+	}
+
+	var discount *PricingRule
+	for i, rule := range rules {
+		if rule.Kind != pricingRuleStayDiscount || int(rule.MinNights) > nights || !rule.covers(checkIn) {
+			continue
+		}
+		if discount == nil || rule.MinNights > discount.MinNights ||
+			(rule.MinNights == discount.MinNights && (rule.Priority > discount.Priority || (rule.Priority == discount.Priority && rule.ID > discount.ID))) {
+			discount = &rules[i]
+		}
+	}
+	if discount != nil {
+		quote.DiscountRule, quote.DiscountPercent = discount.Name, discount.DiscountPercent
+		quote.Discount = (quote.Subtotal*uint64(discount.DiscountPercent) + 50) / 100
+	}
+	quote.Total = quote.Subtotal - quote.Discount
+	return quote
+}
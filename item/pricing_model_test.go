@@ -0,0 +1,82 @@
+package item
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPricingRuleValidate(t *testing.T) {
+	nightly := PricingRule{Name: "Summer", Kind: "nightly", StartDate: "2030-06-01", EndDate: "2030-08-31", Weekdays: []string{"saturday"}, Price: 1500}
+	assert.Empty(t, nightly.Validate())
+	discount := PricingRule{Name: "Week stay", Kind: "stay_discount", MinNights: 7, DiscountPercent: 10}
+	assert.Empty(t, discount.Validate())
+	// names are counted in characters, not bytes
+	assert.Empty(t, PricingRule{Name: strings.Repeat("é", maxPricingRuleNameLength), Kind: "stay_discount", MinNights: 7, DiscountPercent: 10}.Validate())
+
+	invalid := PricingRule{Kind: "stay_discount", StartDate: "2030-08-31", EndDate: "2030-06-01", Weekdays: []string{"Sat"}, Price: 10, DiscountPercent: 101}
+	names := []string{}
+	for _, param := range invalid.Validate() {
+		names = append(names, param.Name)
+	}
+	assert.Equal(t, []string{"name", "discount_percent", "price", "weekdays", "end_date", "weekdays"}, names)
+
+	assert.Equal(t, "kind", PricingRule{Name: "Promo", Kind: "promo"}.Validate()[0].Name)
+}
+
+func TestQuoteStayNightlyRules(t *testing.T) {
+	request := QuoteRequest{ItemID: 1, Rooms: 2, CheckIn: "2030-01-10", CheckOut: "2030-01-13"}
+	rules := []PricingRule{
+		{ID: 1, Name: "Winter", Kind: "nightly", StartDate: "2030-01-01", EndDate: "2030-01-31", Price: 1200},
+		// friday 2030-01-11 and saturday 2030-01-12 are weekend nights, the weekend rule has priority
+		{ID: 2, Name: "Weekend", Kind: "nightly", Weekdays: []string{"friday", "saturday"}, Price: 1500, Priority: 1},
+		// the stay is too short for the long stay rate
+		{ID: 3, Name: "Long stay", Kind: "nightly", MinNights: 5, Price: 800, Priority: 5},
+	}
+	quote := quoteStay(request, 1000, rules)
+	assert.Equal(t, []QuoteNight{
+		{Date: "2030-01-10", Price: 1200, Rule: "Winter"},
+		{Date: "2030-01-11", Price: 1500, Rule: "Weekend"},
+		{Date: "2030-01-12", Price: 1500, Rule: "Weekend"},
+	}, quote.Nights)
+	assert.Equal(t, uint64(8400), quote.Subtotal)
+	assert.Equal(t, uint64(0), quote.Discount)
+	assert.Equal(t, uint64(8400), quote.Total)
+}
+
+func TestQuoteStayDiscountTiers(t *testing.T) {
+	request := QuoteRequest{ItemID: 1, Rooms: 1, CheckIn: "2030-01-10", CheckOut: "2030-01-17"}
+	rules := []PricingRule{
+		{ID: 1, Name: "3 nights", Kind: "stay_discount", MinNights: 3, DiscountPercent: 5},
+		{ID: 2, Name: "7 nights", Kind: "stay_discount", MinNights: 7, DiscountPercent: 15},
+		{ID: 3, Name: "14 nights", Kind: "stay_discount", MinNights: 14, DiscountPercent: 25},
+		// the stay checks in before the promotion starts
+		{ID: 4, Name: "Spring", Kind: "stay_discount", StartDate: "2030-03-01", MinNights: 7, DiscountPercent: 30},
+	}
+	quote := quoteStay(request, 999, rules)
+	assert.Len(t, quote.Nights, 7)
+	assert.Equal(t, uint64(6993), quote.Subtotal)
+	assert.Equal(t, "7 nights", quote.DiscountRule)
+	assert.Equal(t, uint(15), quote.DiscountPercent)
+	// 15% of 6993 is 1048.95, rounded to 1049
+	assert.Equal(t, uint64(1049), quote.Discount)
+	assert.Equal(t, uint64(5944), quote.Total)
+}
+
+func TestNewQuoteRequest(t *testing.T) {
+	checkIn := time.Now().UTC().AddDate(0, 0, 1)
+	query := url.Values{"checkin": {checkIn.Format("2006-01-02")}, "checkout": {checkIn.AddDate(0, 0, 2).Format("2006-01-02")}}
+	request, invalidParams := NewQuoteRequest(1, query)
+	assert.Empty(t, invalidParams)
+	assert.Equal(t, uint(1), request.Rooms)
+
+	_, invalidParams = NewQuoteRequest(1, url.Values{"checkin": {"2030-01-10"}, "rooms": {"0"}, "room_type_id": {"x"}})
+	names := []string{}
+	for _, param := range invalidParams {
+		names = append(names, param.Name)
+	}
+	assert.Equal(t, []string{"checkout", "rooms", "room_type_id"}, names)
+}
@@ -0,0 +1,275 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sayooj/trivago/utils"
+)
+
+//PricingRepositoryInterface interface
+type PricingRepositoryInterface interface {
+	GetPricingRules(ctx context.Context, itemID int) ([]PricingRule, error)
+	GetPricingRule(ctx context.Context, itemID, ruleID int) (PricingRule, error)
+	AddPricingRule(ctx context.Context, rule PricingRule) (PricingRule, error)
+	UpdatePricingRule(ctx context.Context, rule PricingRule) (PricingRule, error)
+	DeletePricingRule(ctx context.Context, itemID, ruleID int) error
+	GetQuote(ctx context.Context, request QuoteRequest) (Quote, error)
+}
+
+const pricingRuleSelectQuery = `
+	SELECT
+		id,
+		item_id,
+		COALESCE(room_type_id, 0),
+		name,
+		kind,
+		start_date,
+		end_date,
+		weekdays,
+		min_nights,
+		price,
+		discount_percent,
+		priority,
+		created_at
+	FROM
+		pricing_rule
+	`
+
+//PricingRepository struct
+type PricingRepository struct {
+	db *sql.DB
+}
+
+//GetPricingRules returns the pricing rules of an item and of its room types
+func (r *PricingRepository) GetPricingRules(ctx context.Context, itemID int) ([]PricingRule, error) {
+	query := pricingRuleSelectQuery + `WHERE item_id = $1 ORDER BY room_type_id NULLS FIRST, kind, priority DESC, id`
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching pricing rules %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	rules, err := scanPricingRules(rows)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching pricing rules %w", utils.ErrFetchError)
+	}
+	return rules, nil
+}
+
+//GetPricingRule gets a pricing rule of an item
+func (r *PricingRepository) GetPricingRule(ctx context.Context, itemID, ruleID int) (PricingRule, error) {
+	query := pricingRuleSelectQuery + `WHERE item_id = $1 AND id = $2`
+	rule, err := scanPricingRule(r.db.QueryRowContext(ctx, query, itemID, ruleID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return PricingRule{}, fmt.Errorf("Pricing rule not found %w", utils.ErrPricingRuleNotFound)
+		}
+		return PricingRule{}, fmt.Errorf("Failed to fetch pricing rule %w", utils.ErrFetchError)
+	}
+	return rule, nil
+}
+
+//AddPricingRule adds a pricing rule to an item, the room type of the rule should be a room type of the item
+func (r *PricingRepository) AddPricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PricingRule{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	err = lockItemRow(ctx, tx, int(rule.ItemID), utils.ErrPricingRuleNotSaved)
+	if err != nil {
+		return PricingRule{}, err
+	}
+	err = checkRuleRoomType(ctx, tx, rule)
+	if err != nil {
+		return PricingRule{}, err
+	}
+	if rule.Weekdays == nil {
+		rule.Weekdays = []string{}
+	}
+	insertQry := `INSERT INTO pricing_rule(item_id, room_type_id, name, kind, start_date, end_date, weekdays, min_nights, price, discount_percent, priority)
+		VALUES($1 , NULLIF($2, 0) , $3 , $4 , $5 , $6 , $7 , $8 , $9 , $10 , $11) RETURNING id, created_at;`
+	err = tx.QueryRowContext(ctx, insertQry, rule.ItemID, rule.RoomTypeID, rule.Name, rule.Kind, nullDate(rule.StartDate), nullDate(rule.EndDate),
+		pq.Array(rule.Weekdays), rule.MinNights, rule.Price, rule.DiscountPercent, rule.Priority).Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		return PricingRule{}, fmt.Errorf("Error occured during insertion %w", utils.ErrPricingRuleNotSaved)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return PricingRule{}, fmt.Errorf("Error occured during insertion %w", utils.ErrPricingRuleNotSaved)
+	}
+	return rule, nil
+}
+
+//UpdatePricingRule replaces a pricing rule of an item
+func (r *PricingRepository) UpdatePricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PricingRule{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	err = lockItemRow(ctx, tx, int(rule.ItemID), utils.ErrPricingRuleNotSaved)
+	if err != nil {
+		return PricingRule{}, err
+	}
+	err = checkRuleRoomType(ctx, tx, rule)
+	if err != nil {
+		return PricingRule{}, err
+	}
+	if rule.Weekdays == nil {
+		rule.Weekdays = []string{}
+	}
+	updateQry := `UPDATE pricing_rule SET room_type_id = NULLIF($3, 0), name = $4, kind = $5, start_date = $6, end_date = $7, weekdays = $8,
+		min_nights = $9, price = $10, discount_percent = $11, priority = $12
+		WHERE item_id = $1 AND id = $2 RETURNING created_at;`
+	err = tx.QueryRowContext(ctx, updateQry, rule.ItemID, rule.ID, rule.RoomTypeID, rule.Name, rule.Kind, nullDate(rule.StartDate), nullDate(rule.EndDate),
+		pq.Array(rule.Weekdays), rule.MinNights, rule.Price, rule.DiscountPercent, rule.Priority).Scan(&rule.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return PricingRule{}, fmt.Errorf("Pricing rule not found %w", utils.ErrPricingRuleNotFound)
+		}
+		return PricingRule{}, fmt.Errorf("Error occured while updating the pricing rule %w", utils.ErrPricingRuleNotSaved)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return PricingRule{}, fmt.Errorf("Error occured while updating the pricing rule %w", utils.ErrPricingRuleNotSaved)
+	}
+	return rule, nil
+}
+
+//DeletePricingRule removes a pricing rule from an item, quotes of bookings already made are kept
+func (r *PricingRepository) DeletePricingRule(ctx context.Context, itemID, ruleID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM pricing_rule WHERE item_id = $1 AND id = $2;`, itemID, ruleID)
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the pricing rule %w", utils.ErrPricingRuleNotSaved)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the pricing rule %w", utils.ErrPricingRuleNotSaved)
+	}
+	if rows == 0 {
+		return fmt.Errorf("Pricing rule not found %w", utils.ErrPricingRuleNotFound)
+	}
+	return nil
+}
+
+//GetQuote prices a stay from the price of the item, or of the room type of the request, and its pricing rules
+func (r *PricingRepository) GetQuote(ctx context.Context, request QuoteRequest) (Quote, error) {
+	var price uint64
+	var priceCurrency string
+	var sellsRoomTypes bool
+	itemQry := `SELECT price, currency, EXISTS (SELECT 1 FROM room_type WHERE room_type.item_id = item.item_id) FROM item WHERE item_id = $1 AND deleted_at IS NULL;`
+	err := r.db.QueryRowContext(ctx, itemQry, request.ItemID).Scan(&price, &priceCurrency, &sellsRoomTypes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Quote{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+		}
+		return Quote{}, fmt.Errorf("Error occured while fetching the Item %w", utils.ErrFetchError)
+	}
+	if request.RoomTypeID != 0 {
+		err = r.db.QueryRowContext(ctx, `SELECT price FROM room_type WHERE id = $1 AND item_id = $2;`, request.RoomTypeID, request.ItemID).Scan(&price)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return Quote{}, fmt.Errorf("Room type not found %w", utils.ErrRoomTypeNotFound)
+			}
+			return Quote{}, fmt.Errorf("Error occured while fetching the room type %w", utils.ErrFetchError)
+		}
+	} else if sellsRoomTypes {
+		return Quote{}, fmt.Errorf("Item %d sells room types %w", request.ItemID, utils.ErrRoomTypeRequired)
+	}
+	rules, err := stayPricingRules(ctx, r.db, request)
+	if err != nil {
+		return Quote{}, fmt.Errorf("Error occured while fetching pricing rules %w", utils.ErrFetchError)
+	}
+	quote := quoteStay(request, price, rules)
+	quote.Currency = priceCurrency
+	return quote, nil
+}
+
+//stayPricingRules returns the pricing rules of the item or room type of the request whose dates meet the stay
+func stayPricingRules(ctx context.Context, q queryer, request QuoteRequest) ([]PricingRule, error) {
+	query := pricingRuleSelectQuery + `WHERE item_id = $1 AND COALESCE(room_type_id, 0) = $2
+		AND (start_date IS NULL OR start_date < $4) AND (end_date IS NULL OR end_date >= $3)`
+	rows, err := q.QueryContext(ctx, query, request.ItemID, request.RoomTypeID, request.CheckIn, request.CheckOut)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPricingRules(rows)
+}
+
+//checkRuleRoomType checks the room type of the rule is a room type of its item
+func checkRuleRoomType(ctx context.Context, tx queryer, rule PricingRule) error {
+	if rule.RoomTypeID == 0 {
+		return nil
+	}
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM room_type WHERE id = $1 AND item_id = $2);`, rule.RoomTypeID, rule.ItemID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("Error occured while fetching the room type %w", utils.ErrPricingRuleNotSaved)
+	}
+	if !exists {
+		return fmt.Errorf("Room type not found %w", utils.ErrRoomTypeNotFound)
+	}
+	return nil
+}
+
+//scanPricingRules scans the rows selected with pricingRuleSelectQuery
+func scanPricingRules(rows *sql.Rows) ([]PricingRule, error) {
+	rules := []PricingRule{}
+	for rows.Next() {
+		rule, err := scanPricingRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+//scanPricingRule scans a row selected with pricingRuleSelectQuery
+func scanPricingRule(row rowScanner) (PricingRule, error) {
+	var p PricingRule
+	var startDate, endDate sql.NullTime
+	err := row.Scan(&p.ID, &p.ItemID, &p.RoomTypeID, &p.Name, &p.Kind, &startDate, &endDate, pq.Array(&p.Weekdays), &p.MinNights, &p.Price, &p.DiscountPercent, &p.Priority, &p.CreatedAt)
+	if err != nil {
+		return PricingRule{}, err
+	}
+	if startDate.Valid {
+		p.StartDate = startDate.Time.Format(dateLayout)
+	}
+	if endDate.Valid {
+		p.EndDate = endDate.Time.Format(dateLayout)
+	}
+	if p.Weekdays == nil {
+		p.Weekdays = []string{}
+	}
+	return p, nil
+}
+
+//nullDate stores a missing date as NULL
+func nullDate(date string) sql.NullString {
+	return sql.NullString{String: date, Valid: date != ""}
+}
+
+//NewPricingRepository method
+func NewPricingRepository(db *sql.DB) *PricingRepository {
+	return &PricingRepository{db}
+}
@@ -0,0 +1,129 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var pricingRuleColumns = []string{"id", "item_id", "room_type_id", "name", "kind", "start_date", "end_date", "weekdays", "min_nights", "price", "discount_percent", "priority", "created_at"}
+
+func TestGetPricingRules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	startDate := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM\s+pricing_rule\s+WHERE item_id = \$1 ORDER BY`).WithArgs(1).WillReturnRows(sqlmock.NewRows(pricingRuleColumns).
+		AddRow(4, 1, 0, "Summer", "nightly", startDate, startDate.AddDate(0, 3, -1), "{}", 0, 1500, 0, 1, time.Now()).
+		AddRow(5, 1, 2, "Week stay", "stay_discount", nil, nil, "{}", 7, 0, 10, 0, time.Now()))
+	repo := NewPricingRepository(db)
+	rules, err := repo.GetPricingRules(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "2030-06-01", rules[0].StartDate)
+	assert.Equal(t, "2030-08-31", rules[0].EndDate)
+	assert.Equal(t, []string{}, rules[0].Weekdays)
+	assert.Equal(t, uint64(2), rules[1].RoomTypeID)
+	assert.Equal(t, "", rules[1].StartDate)
+}
+
+func TestGetPricingRuleNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`WHERE item_id = \$1 AND id = \$2`).WithArgs(1, 9).WillReturnRows(sqlmock.NewRows(pricingRuleColumns))
+	repo := NewPricingRepository(db)
+	_, err = repo.GetPricingRule(context.Background(), 1, 9)
+	assert.True(t, errors.Is(err, utils.ErrPricingRuleNotFound))
+}
+
+func TestAddPricingRule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	rule := PricingRule{ItemID: 1, Name: "Weekend", Kind: "nightly", Weekdays: []string{"friday", "saturday"}, Price: 1500}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item .* FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO pricing_rule`).WithArgs(rule.ItemID, rule.RoomTypeID, rule.Name, rule.Kind, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), rule.MinNights, rule.Price, rule.DiscountPercent, rule.Priority).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(4, time.Now()))
+	mock.ExpectCommit()
+	repo := NewPricingRepository(db)
+	created, err := repo.AddPricingRule(context.Background(), rule)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), created.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddPricingRuleUnknownRoomType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	rule := PricingRule{ItemID: 1, RoomTypeID: 7, Name: "Week stay", Kind: "stay_discount", MinNights: 7, DiscountPercent: 10}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM room_type WHERE id = \$1 AND item_id = \$2\)`).WithArgs(rule.RoomTypeID, rule.ItemID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+	repo := NewPricingRepository(db)
+	_, err = repo.AddPricingRule(context.Background(), rule)
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePricingRuleNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM pricing_rule WHERE item_id = \$1 AND id = \$2`).WithArgs(1, 9).WillReturnResult(sqlmock.NewResult(0, 0))
+	repo := NewPricingRepository(db)
+	err = repo.DeletePricingRule(context.Background(), 1, 9)
+	assert.True(t, errors.Is(err, utils.ErrPricingRuleNotFound))
+}
+
+func TestGetQuote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	request := QuoteRequest{ItemID: 1, Rooms: 2, CheckIn: "2030-01-10", CheckOut: "2030-01-12"}
+	mock.ExpectQuery(`SELECT price, currency, EXISTS`).WithArgs(request.ItemID).WillReturnRows(sqlmock.NewRows([]string{"price", "currency", "exists"}).AddRow(1000, "JPY", false))
+	mock.ExpectQuery(`FROM\s+pricing_rule`).WithArgs(request.ItemID, request.RoomTypeID, request.CheckIn, request.CheckOut).WillReturnRows(sqlmock.NewRows(pricingRuleColumns).
+		AddRow(5, 1, 0, "Two nights", "stay_discount", nil, nil, "{}", 2, 0, 5, 0, time.Now()))
+	repo := NewPricingRepository(db)
+	quote, err := repo.GetQuote(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Len(t, quote.Nights, 2)
+	assert.Equal(t, uint64(4000), quote.Subtotal)
+	assert.Equal(t, uint64(200), quote.Discount)
+	assert.Equal(t, uint64(3800), quote.Total)
+	assert.Equal(t, "JPY", quote.Currency)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuoteRoomTypeRequired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT price, currency, EXISTS`).WithArgs(uint64(1)).WillReturnRows(sqlmock.NewRows([]string{"price", "currency", "exists"}).AddRow(1000, "EUR", true))
+	repo := NewPricingRepository(db)
+	_, err = repo.GetQuote(context.Background(), QuoteRequest{ItemID: 1, Rooms: 1, CheckIn: "2030-01-10", CheckOut: "2030-01-11"})
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeRequired))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
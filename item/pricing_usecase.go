@@ -0,0 +1,67 @@
+package item
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//PricingUseCaseInterface interface
+type PricingUseCaseInterface interface {
+	GetPricingRules(ctx context.Context, itemID int) ([]PricingRule, error)
+	GetPricingRule(ctx context.Context, itemID, ruleID int) (PricingRule, error)
+	AddPricingRule(ctx context.Context, rule PricingRule) (PricingRule, error)
+	UpdatePricingRule(ctx context.Context, rule PricingRule) (PricingRule, error)
+	DeletePricingRule(ctx context.Context, itemID, ruleID int) error
+	GetQuote(ctx context.Context, request QuoteRequest) (Quote, error)
+}
+
+//PricingUseCase struct
+type PricingUseCase struct {
+	pricingRepo PricingRepositoryInterface
+	itemRepo    ItemsRepositoryInterface
+}
+
+//GetPricingRules returns the pricing rules of an item
+func (u *PricingUseCase) GetPricingRules(ctx context.Context, itemID int) ([]PricingRule, error) {
+	_, err := u.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+	}
+	return u.pricingRepo.GetPricingRules(ctx, itemID)
+}
+
+//GetPricingRule gets a pricing rule of an item
+func (u *PricingUseCase) GetPricingRule(ctx context.Context, itemID, ruleID int) (PricingRule, error) {
+	_, err := u.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return PricingRule{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+	}
+	return u.pricingRepo.GetPricingRule(ctx, itemID, ruleID)
+}
+
+//AddPricingRule adds a pricing rule to an item
+func (u *PricingUseCase) AddPricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	return u.pricingRepo.AddPricingRule(ctx, rule)
+}
+
+//UpdatePricingRule replaces a pricing rule of an item
+func (u *PricingUseCase) UpdatePricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	return u.pricingRepo.UpdatePricingRule(ctx, rule)
+}
+
+//DeletePricingRule removes a pricing rule from an item
+func (u *PricingUseCase) DeletePricingRule(ctx context.Context, itemID, ruleID int) error {
+	return u.pricingRepo.DeletePricingRule(ctx, itemID, ruleID)
+}
+
+//GetQuote prices a stay at an item
+func (u *PricingUseCase) GetQuote(ctx context.Context, request QuoteRequest) (Quote, error) {
+	return u.pricingRepo.GetQuote(ctx, request)
+}
+
+//NewPricingUseCase method
+func NewPricingUseCase(pricingRepo *PricingRepository, itemRepo *ItemsRepository) *PricingUseCase {
+	return &PricingUseCase{pricingRepo, itemRepo}
+}
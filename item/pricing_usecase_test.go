@@ -0,0 +1,83 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+var pricingRule = PricingRule{ID: 4, ItemID: 1, Name: "Weekend", Kind: "nightly", Weekdays: []string{"friday", "saturday"}, Price: 1500}
+
+type MockPricingRepo struct {
+	mock.Mock
+}
+
+func (m *MockPricingRepo) GetPricingRules(ctx context.Context, itemID int) ([]PricingRule, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepo) GetPricingRule(ctx context.Context, itemID, ruleID int) (PricingRule, error) {
+	args := m.Called(ctx, itemID, ruleID)
+	return args.Get(0).(PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepo) AddPricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepo) UpdatePricingRule(ctx context.Context, rule PricingRule) (PricingRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(PricingRule), args.Error(1)
+}
+
+func (m *MockPricingRepo) DeletePricingRule(ctx context.Context, itemID, ruleID int) error {
+	args := m.Called(ctx, itemID, ruleID)
+	return args.Error(0)
+}
+
+func (m *MockPricingRepo) GetQuote(ctx context.Context, request QuoteRequest) (Quote, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(Quote), args.Error(1)
+}
+
+func TestGetPricingRulesSuccess(t *testing.T) {
+	repo := new(MockPricingRepo)
+	itemRepo := new(MockRepo)
+	itemRepo.On("GetItem", context.Background(), 1).Return(item, nil)
+	repo.On("GetPricingRules", context.Background(), 1).Return([]PricingRule{pricingRule}, nil)
+	uc := PricingUseCase{repo, itemRepo}
+	res, err := uc.GetPricingRules(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	repo.AssertExpectations(t)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestGetPricingRulesItemNotFound(t *testing.T) {
+	repo := new(MockPricingRepo)
+	itemRepo := new(MockRepo)
+	itemRepo.On("GetItem", context.Background(), 1).Return(Item{}, utils.ErrItemNotFound)
+	uc := PricingUseCase{repo, itemRepo}
+	_, err := uc.GetPricingRules(context.Background(), 1)
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	repo.AssertExpectations(t)
+}
+
+func TestGetQuoteSuccess(t *testing.T) {
+	repo := new(MockPricingRepo)
+	request := QuoteRequest{ItemID: 1, Rooms: 1, CheckIn: "2030-01-10", CheckOut: "2030-01-11"}
+	quote := Quote{ItemID: 1, Rooms: 1, Subtotal: 1500, Total: 1500}
+	repo.On("GetQuote", context.Background(), request).Return(quote, nil)
+	uc := PricingUseCase{repo, new(MockRepo)}
+	res, err := uc.GetQuote(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, quote, res)
+	repo.AssertExpectations(t)
+}
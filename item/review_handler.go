@@ -0,0 +1,97 @@
+package item
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+//ReviewsHandler handler for the reviews of items
+type ReviewsHandler struct {
+	useCase ReviewsUseCaseInterface
+	logger  *logrus.Logger
+}
+
+//GetReviews get a page of the reviews of an item
+func (h *ReviewsHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	page, invalidParams := NewReviewPage(r.URL.Query())
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid query parameters")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	reviews, err := h.useCase.GetReviews(r.Context(), itemID, page)
+	if err != nil {
+		h.respondWithReviewError(w, err, "Error occured while fetching the reviews")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, reviews)
+}
+
+//AddReview add the review of a guest with a completed booking to an item
+func (h *ReviewsHandler) AddReview(w http.ResponseWriter, r *http.Request) {
+	var request ReviewRequest
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&request); err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	request.ConfirmationCode = strings.ToUpper(strings.TrimSpace(request.ConfirmationCode))
+	invalidParams := request.Validate()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return
+	}
+	review, err := h.useCase.AddReview(r.Context(), itemID, request)
+	if err != nil {
+		h.respondWithReviewError(w, err, "Failed to add review")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/item/%d/reviews/%d", itemID, review.ID))
+	utils.RespondWithJSON(w, http.StatusCreated, review)
+}
+
+//respondWithReviewError maps the errors of the reviews, message describes any other error
+func (h *ReviewsHandler) respondWithReviewError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, utils.ErrReviewNotAllowed) {
+		h.logger.Info("Review not allowed")
+		utils.RespondWithError(w, http.StatusForbidden, utils.ErrReviewNotAllowed.Error())
+		return
+	}
+	if errors.Is(err, utils.ErrReviewExists) {
+		h.logger.Info("Booking already reviewed")
+		utils.RespondWithError(w, http.StatusConflict, utils.ErrReviewExists.Error())
+		return
+	}
+	h.logger.Info(message)
+	utils.RespondWithError(w, http.StatusInternalServerError, message)
+}
+
+//NewReviewsHandler method
+func NewReviewsHandler(useCase *ReviewsUseCase, log *logrus.Logger) *ReviewsHandler {
+	return &ReviewsHandler{useCase, log}
+}
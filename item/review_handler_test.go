@@ -0,0 +1,95 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+type MockReviewUseCase struct {
+	mock.Mock
+}
+
+func (m *MockReviewUseCase) GetReviews(ctx context.Context, itemID int, page ListMeta) (ReviewList, error) {
+	args := m.Called(ctx, itemID, page)
+	return args.Get(0).(ReviewList), args.Error(1)
+}
+
+func (m *MockReviewUseCase) AddReview(ctx context.Context, itemID int, request ReviewRequest) (Review, error) {
+	args := m.Called(ctx, itemID, request)
+	return args.Get(0).(Review), args.Error(1)
+}
+
+func TestGetReviewsHandler(t *testing.T) {
+	uc := new(MockReviewUseCase)
+	vh := ReviewsHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/reviews?limit=5", "", "id", "1")
+	total := 1
+	list := ReviewList{Reviews: []Review{review}, Meta: ListMeta{Total: &total, Limit: 5}}
+	uc.On("GetReviews", req.Context(), 1, ListMeta{Limit: 5}).Return(list, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(vh.GetReviews).ServeHTTP(rr, req)
+	var res ReviewList
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, list, res)
+	uc.AssertExpectations(t)
+}
+
+func TestGetReviewsHandlerInvalidPage(t *testing.T) {
+	uc := new(MockReviewUseCase)
+	vh := ReviewsHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/reviews?limit=0", "", "id", "1")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(vh.GetReviews).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestAddReviewHandler(t *testing.T) {
+	uc := new(MockReviewUseCase)
+	vh := ReviewsHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/reviews", `{"confirmation_code":" abcd-ef23 ","score":9,"comment":"Great stay"}`, "id", "1")
+	uc.On("AddReview", req.Context(), 1, ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 9, Comment: "Great stay"}).Return(review, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(vh.AddReview).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/item/1/reviews/4", rr.Header().Get("Location"))
+	uc.AssertExpectations(t)
+}
+
+func TestAddReviewHandlerInvalidScore(t *testing.T) {
+	uc := new(MockReviewUseCase)
+	vh := ReviewsHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/reviews", `{"confirmation_code":"ABCD-EF23","score":0}`, "id", "1")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(vh.AddReview).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestAddReviewHandlerErrors(t *testing.T) {
+	for err, code := range map[error]int{
+		utils.ErrItemNotFound:     http.StatusNotFound,
+		utils.ErrReviewNotAllowed: http.StatusForbidden,
+		utils.ErrReviewExists:     http.StatusConflict,
+		utils.ErrReviewNotSaved:   http.StatusInternalServerError,
+	} {
+		uc := new(MockReviewUseCase)
+		vh := ReviewsHandler{uc, logrus.New()}
+		req := newRouteRequest("POST", "/item/1/reviews", `{"confirmation_code":"ABCD-EF23","score":9}`, "id", "1")
+		uc.On("AddReview", req.Context(), 1, ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 9}).Return(Review{}, err)
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(vh.AddReview).ServeHTTP(rr, req)
+		assert.Equal(t, code, rr.Code, err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package item
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//Review is the review of a guest with a completed booking at the item, a booking is reviewed once
+type Review struct {
+	ID        uint64    `json:"id"`
+	ItemID    uint64    `json:"item_id"`
+	GuestName string    `json:"guest_name"`
+	Score     uint      `json:"score"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//ReviewRequest is the body of a review, the confirmation code proves the guest stayed at the item
+type ReviewRequest struct {
+	ConfirmationCode string `json:"confirmation_code"`
+	Score            uint   `json:"score"`
+	Comment          string `json:"comment"`
+}
+
+//Validate validates the review request
+func (r ReviewRequest) Validate() []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if strings.TrimSpace(r.ConfirmationCode) == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "confirmation_code", Reason: "confirmation_code required"})
+	}
+	if r.Score < minReviewScore || r.Score > maxReviewScore {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "score", Reason: fmt.Sprintf("score should be between %d and %d", minReviewScore, maxReviewScore)})
+	}
+	if utf8.RuneCountInString(r.Comment) > maxReviewCommentLength {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "comment", Reason: fmt.Sprintf("comment should be at most %d characters", maxReviewCommentLength)})
+	}
+	return validationErr
+}
+
+//ReviewList is a page of the reviews of an item, newest first
+type ReviewList struct {
+	Reviews []Review `json:"reviews"`
+	Meta    ListMeta `json:"meta"`
+}
+
+//NewReviewPage reads the limit and offset of GET /item/{id}/reviews
+func NewReviewPage(query url.Values) (ListMeta, []utils.InvalidParams) {
+	validationErr := []utils.InvalidParams{}
+	page := ListMeta{Limit: defaultReviewsLimit}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxReviewsLimit {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "limit", Reason: fmt.Sprintf("limit should be between 1 and %d", maxReviewsLimit)})
+		}
+		page.Limit = limit
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "offset", Reason: "offset should be >= 0"})
+		}
+		page.Offset = offset
+	}
+	return page, validationErr
+}
+
+//reviewSnapshot is what a review changes of its item, recorded in the history of the item
+type reviewSnapshot struct {
+	Reputation  uint64  `json:"reputation"`
+	ReviewCount uint    `json:"review_count"`
+	Review      *Review `json:"review,omitempty"`
+}
+
+//reviewReputation is the reputation of an item from the scores of its reviews. It is the mean score
+//weighted by recency, a review weighs half as much as one reviewHalfLife newer, scaled so that
+//minReviewScore is 0 and maxReviewScore is 1000 and rounded half up. An item without reviews has no
+//reputation from them, ok is false
+func reviewReputation(reviews []Review) (reputation uint64, ok bool) {
+	if len(reviews) == 0 {
+		return 0, false
+	}
+	newest := reviews[0].CreatedAt
+	for _, review := range reviews {
+		if review.CreatedAt.After(newest) {
+			newest = review.CreatedAt
+		}
+	}
+	var weighted, weights float64
+	for _, review := range reviews {
+		weight := math.Pow(0.5, float64(newest.Sub(review.CreatedAt))/float64(reviewHalfLife))
+		weighted += weight * float64(review.Score)
+		weights += weight
+	}
+	scaled := (weighted/weights - minReviewScore) / (maxReviewScore - minReviewScore) * 1000
+	return uint64(math.Floor(scaled + 0.5)), true
+}
@@ -0,0 +1,62 @@
+package item
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReviewRequest(t *testing.T) {
+	assert.Empty(t, ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 8}.Validate())
+	// comments are counted in characters, not bytes
+	assert.Empty(t, ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 8, Comment: strings.Repeat("é", maxReviewCommentLength)}.Validate())
+	invalid := ReviewRequest{Score: 11, Comment: string(make([]byte, maxReviewCommentLength+1))}.Validate()
+	assert.Len(t, invalid, 3)
+	assert.Equal(t, "confirmation_code", invalid[0].Name)
+	assert.Equal(t, "score", invalid[1].Name)
+	assert.Equal(t, "comment", invalid[2].Name)
+	assert.Len(t, ReviewRequest{ConfirmationCode: "ABCD-EF23"}.Validate(), 1)
+}
+
+func TestReviewReputation(t *testing.T) {
+	_, ok := reviewReputation(nil)
+	assert.False(t, ok)
+
+	now := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	// the lowest score is 0 and the highest 1000
+	reputation, ok := reviewReputation([]Review{{Score: minReviewScore, CreatedAt: now}})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), reputation)
+	reputation, _ = reviewReputation([]Review{{Score: maxReviewScore, CreatedAt: now}})
+	assert.Equal(t, uint64(1000), reputation)
+	reputation, _ = reviewReputation([]Review{{Score: 9, CreatedAt: now}})
+	assert.Equal(t, uint64(889), reputation)
+
+	// reviews of the same age weigh the same, a mean of 8.5 is (8.5 - 1) / 9
+	reputation, _ = reviewReputation([]Review{{Score: 10, CreatedAt: now}, {Score: 7, CreatedAt: now}})
+	assert.Equal(t, uint64(833), reputation)
+
+	// a review one half life older weighs half, (10 + 4 * 0.5) / 1.5 = 8
+	reputation, _ = reviewReputation([]Review{{Score: 4, CreatedAt: now.Add(-reviewHalfLife)}, {Score: 10, CreatedAt: now}})
+	assert.Equal(t, uint64(778), reputation)
+
+	// two half lives, (10 + 4 * 0.25) / 1.25 = 8.8
+	reputation, _ = reviewReputation([]Review{{Score: 10, CreatedAt: now}, {Score: 4, CreatedAt: now.Add(-2 * reviewHalfLife)}})
+	assert.Equal(t, uint64(867), reputation)
+}
+
+func TestNewReviewPage(t *testing.T) {
+	page, invalid := NewReviewPage(url.Values{})
+	assert.Empty(t, invalid)
+	assert.Equal(t, ListMeta{Limit: defaultReviewsLimit}, page)
+
+	page, invalid = NewReviewPage(url.Values{"limit": {"5"}, "offset": {"10"}})
+	assert.Empty(t, invalid)
+	assert.Equal(t, ListMeta{Limit: 5, Offset: 10}, page)
+
+	_, invalid = NewReviewPage(url.Values{"limit": {"101"}, "offset": {"-1"}})
+	assert.Len(t, invalid, 2)
+}
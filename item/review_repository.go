@@ -0,0 +1,152 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//ReviewsRepositoryInterface interface
+type ReviewsRepositoryInterface interface {
+	GetReviews(ctx context.Context, itemID int, page ListMeta) (ReviewList, error)
+	AddReview(ctx context.Context, itemID int, request ReviewRequest) (Review, error)
+}
+
+//ReviewsRepository struct
+type ReviewsRepository struct {
+	db *sql.DB
+}
+
+//GetReviews returns a page of the reviews of an item, newest first
+func (r *ReviewsRepository) GetReviews(ctx context.Context, itemID int, page ListMeta) (ReviewList, error) {
+	list := ReviewList{Reviews: []Review{}, Meta: page}
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM item_review WHERE item_id = $1;`, itemID).Scan(&total)
+	if err != nil {
+		return ReviewList{}, fmt.Errorf("Error occured while fetching reviews %w", utils.ErrFetchError)
+	}
+	list.Meta.Total = &total
+	query := `SELECT id, item_id, guest_name, score, comment, created_at FROM item_review WHERE item_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3;`
+	rows, err := r.db.QueryContext(ctx, query, itemID, page.Limit, page.Offset)
+	if err != nil {
+		return ReviewList{}, fmt.Errorf("Error occured while fetching reviews %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var review Review
+		if err := rows.Scan(&review.ID, &review.ItemID, &review.GuestName, &review.Score, &review.Comment, &review.CreatedAt); err != nil {
+			return ReviewList{}, fmt.Errorf("Error occured while fetching reviews %w", utils.ErrFetchError)
+		}
+		list.Reviews = append(list.Reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return ReviewList{}, fmt.Errorf("Error occured while fetching reviews %w", utils.ErrFetchError)
+	}
+	return list, nil
+}
+
+//AddReview adds the review of the guest of a completed booking of the item and recomputes the reputation
+//of the item from every review in the same transaction
+func (r *ReviewsRepository) AddReview(ctx context.Context, itemID int, request ReviewRequest) (Review, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Review{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	// the lock keeps concurrent reviews from computing the reputation from stale reviews
+	err = lockItemRow(ctx, tx, itemID, utils.ErrReviewNotSaved)
+	if err != nil {
+		return Review{}, err
+	}
+	var bookingID uint64
+	var guestName, status string
+	bookingQry := `SELECT id_booking, person_name, status FROM item_booking WHERE confirmation_code = $1 AND item_id = $2;`
+	err = tx.QueryRowContext(ctx, bookingQry, request.ConfirmationCode, itemID).Scan(&bookingID, &guestName, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.ErrReviewNotAllowed
+			return Review{}, fmt.Errorf("No booking %s at item %d %w", request.ConfirmationCode, itemID, err)
+		}
+		return Review{}, fmt.Errorf("Error occured while fetching the booking %w", utils.ErrReviewNotSaved)
+	}
+	if status != bookingStatusCompleted {
+		err = utils.ErrReviewNotAllowed
+		return Review{}, fmt.Errorf("Booking %s is %s %w", request.ConfirmationCode, status, err)
+	}
+
+	review := Review{ItemID: uint64(itemID), GuestName: guestName, Score: request.Score, Comment: request.Comment}
+	insertQry := `INSERT INTO item_review(item_id, booking_id, guest_name, score, comment) VALUES($1 , $2 , $3 , $4 , $5)
+		ON CONFLICT (booking_id) DO NOTHING
+		RETURNING id, created_at;`
+	err = tx.QueryRowContext(ctx, insertQry, itemID, bookingID, guestName, request.Score, request.Comment).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.ErrReviewExists
+			return Review{}, fmt.Errorf("Booking %s already reviewed %w", request.ConfirmationCode, err)
+		}
+		return Review{}, fmt.Errorf("Error occured during insertion %w", utils.ErrReviewNotSaved)
+	}
+
+	err = updateReviewReputation(ctx, tx, itemID, review)
+	if err != nil {
+		return Review{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return Review{}, fmt.Errorf("Error occured during insertion %w", utils.ErrReviewNotSaved)
+	}
+	return review, nil
+}
+
+//updateReviewReputation sets the reputation and review count of the item from its reviews, bumps its version
+//and records the review in the history of the item
+func updateReviewReputation(ctx context.Context, tx queryer, itemID int, review Review) error {
+	var before, after reviewSnapshot
+	err := tx.QueryRowContext(ctx, `SELECT reputation, review_count FROM item WHERE item_id = $1;`, itemID).Scan(&before.Reputation, &before.ReviewCount)
+	if err != nil {
+		return fmt.Errorf("Error occured while fetching the Item %w", utils.ErrReviewNotSaved)
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT score, created_at FROM item_review WHERE item_id = $1;`, itemID)
+	if err != nil {
+		return fmt.Errorf("Error occured while fetching reviews %w", utils.ErrReviewNotSaved)
+	}
+	defer rows.Close()
+	reviews := []Review{}
+	for rows.Next() {
+		var r Review
+		if err := rows.Scan(&r.Score, &r.CreatedAt); err != nil {
+			return fmt.Errorf("Error occured while fetching reviews %w", utils.ErrReviewNotSaved)
+		}
+		reviews = append(reviews, r)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Error occured while fetching reviews %w", utils.ErrReviewNotSaved)
+	}
+	reputation, ok := reviewReputation(reviews)
+	if !ok {
+		return nil
+	}
+	after = reviewSnapshot{Reputation: reputation, ReviewCount: uint(len(reviews)), Review: &review}
+	query := `UPDATE item SET reputation = $2, review_count = $3, version = version + 1 WHERE item_id = $1;`
+	if _, err := tx.ExecContext(ctx, query, itemID, after.Reputation, after.ReviewCount); err != nil {
+		return fmt.Errorf("Error occured while updating the reputation %w", utils.ErrReviewNotSaved)
+	}
+	if err := recordAudit(ctx, tx, uint64(itemID), auditActionReview, before, after); err != nil {
+		return fmt.Errorf("Error occured while recording the history %w", utils.ErrReviewNotSaved)
+	}
+	return nil
+}
+
+//NewReviewsRepository method
+func NewReviewsRepository(db *sql.DB) *ReviewsRepository {
+	return &ReviewsRepository{db}
+}
@@ -0,0 +1,114 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReviews(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM item_review WHERE item_id = \$1`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`FROM item_review WHERE item_id = \$1\s+ORDER BY created_at DESC, id DESC LIMIT \$2 OFFSET \$3`).WithArgs(1, 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "guest_name", "score", "comment", "created_at"}).
+			AddRow(3, 1, "Anna", 9, "Great stay", time.Now()).
+			AddRow(2, 1, "Ben", 6, "", time.Now()))
+	repo := NewReviewsRepository(db)
+	list, err := repo.GetReviews(context.Background(), 1, ListMeta{Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, list.Reviews, 2)
+	assert.Equal(t, 3, *list.Meta.Total)
+	assert.Equal(t, "Anna", list.Reviews[0].GuestName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddReview(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	now := time.Now()
+	request := ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 10, Comment: "Great stay"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item .* FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`FROM item_booking WHERE confirmation_code = \$1 AND item_id = \$2`).WithArgs("ABCD-EF23", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id_booking", "person_name", "status"}).AddRow(7, "Anna", bookingStatusCompleted))
+	mock.ExpectQuery(`INSERT INTO item_review`).WithArgs(1, uint64(7), "Anna", request.Score, request.Comment).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(4, now))
+	mock.ExpectQuery(`SELECT reputation, review_count FROM item WHERE item_id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"reputation", "review_count"}).AddRow(700, 1))
+	mock.ExpectQuery(`SELECT score, created_at FROM item_review WHERE item_id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"score", "created_at"}).AddRow(10, now).AddRow(7, now))
+	mock.ExpectExec(`UPDATE item SET reputation = \$2, review_count = \$3, version = version \+ 1`).WithArgs(1, uint64(833), uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO item_audit`).WithArgs(uint64(1), "review", utils.AnonymousActor, "", `{"reputation":700,"review_count":1}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	repo := NewReviewsRepository(db)
+	review, err := repo.AddReview(context.Background(), 1, request)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), review.ID)
+	assert.Equal(t, "Anna", review.GuestName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddReviewBookingNotCompleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item .* FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`FROM item_booking`).WithArgs("ABCD-EF23", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id_booking", "person_name", "status"}).AddRow(7, "Anna", bookingStatusConfirmed))
+	mock.ExpectRollback()
+	repo := NewReviewsRepository(db)
+	_, err = repo.AddReview(context.Background(), 1, ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 8})
+	assert.True(t, errors.Is(err, utils.ErrReviewNotAllowed))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddReviewUnknownBooking(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item .* FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`FROM item_booking`).WithArgs("ABCD-EF23", 1).WillReturnRows(sqlmock.NewRows([]string{"id_booking", "person_name", "status"}))
+	mock.ExpectRollback()
+	repo := NewReviewsRepository(db)
+	_, err = repo.AddReview(context.Background(), 1, ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 8})
+	assert.True(t, errors.Is(err, utils.ErrReviewNotAllowed))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddReviewExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item .* FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`FROM item_booking`).WithArgs("ABCD-EF23", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id_booking", "person_name", "status"}).AddRow(7, "Anna", bookingStatusCompleted))
+	mock.ExpectQuery(`ON CONFLICT \(booking_id\) DO NOTHING`).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+	mock.ExpectRollback()
+	repo := NewReviewsRepository(db)
+	_, err = repo.AddReview(context.Background(), 1, ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 8})
+	assert.True(t, errors.Is(err, utils.ErrReviewExists))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,39 @@
+package item
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//ReviewsUseCaseInterface interface
+type ReviewsUseCaseInterface interface {
+	GetReviews(ctx context.Context, itemID int, page ListMeta) (ReviewList, error)
+	AddReview(ctx context.Context, itemID int, request ReviewRequest) (Review, error)
+}
+
+//ReviewsUseCase struct
+type ReviewsUseCase struct {
+	reviewRepo ReviewsRepositoryInterface
+	itemRepo   ItemsRepositoryInterface
+}
+
+//GetReviews returns a page of the reviews of an item
+func (u *ReviewsUseCase) GetReviews(ctx context.Context, itemID int, page ListMeta) (ReviewList, error) {
+	_, err := u.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return ReviewList{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+	}
+	return u.reviewRepo.GetReviews(ctx, itemID, page)
+}
+
+//AddReview adds the review of a guest to an item
+func (u *ReviewsUseCase) AddReview(ctx context.Context, itemID int, request ReviewRequest) (Review, error) {
+	return u.reviewRepo.AddReview(ctx, itemID, request)
+}
+
+//NewReviewsUseCase method
+func NewReviewsUseCase(reviewRepo *ReviewsRepository, itemRepo *ItemsRepository) *ReviewsUseCase {
+	return &ReviewsUseCase{reviewRepo, itemRepo}
+}
@@ -0,0 +1,63 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+var review = Review{ID: 4, ItemID: 1, GuestName: "Anna", Score: 9, Comment: "Great stay"}
+
+type MockReviewRepo struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepo) GetReviews(ctx context.Context, itemID int, page ListMeta) (ReviewList, error) {
+	args := m.Called(ctx, itemID, page)
+	return args.Get(0).(ReviewList), args.Error(1)
+}
+
+func (m *MockReviewRepo) AddReview(ctx context.Context, itemID int, request ReviewRequest) (Review, error) {
+	args := m.Called(ctx, itemID, request)
+	return args.Get(0).(Review), args.Error(1)
+}
+
+func TestGetReviewsSuccess(t *testing.T) {
+	repo := new(MockReviewRepo)
+	itemRepo := new(MockRepo)
+	page := ListMeta{Limit: defaultReviewsLimit}
+	itemRepo.On("GetItem", context.Background(), 1).Return(item, nil)
+	total := 1
+	repo.On("GetReviews", context.Background(), 1, page).Return(ReviewList{Reviews: []Review{review}, Meta: ListMeta{Total: &total, Limit: defaultReviewsLimit}}, nil)
+	uc := ReviewsUseCase{repo, itemRepo}
+	res, err := uc.GetReviews(context.Background(), 1, page)
+	assert.NoError(t, err)
+	assert.Len(t, res.Reviews, 1)
+	repo.AssertExpectations(t)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestGetReviewsItemNotFound(t *testing.T) {
+	repo := new(MockReviewRepo)
+	itemRepo := new(MockRepo)
+	itemRepo.On("GetItem", context.Background(), 1).Return(Item{}, utils.ErrItemNotFound)
+	uc := ReviewsUseCase{repo, itemRepo}
+	_, err := uc.GetReviews(context.Background(), 1, ListMeta{Limit: defaultReviewsLimit})
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	repo.AssertExpectations(t)
+}
+
+func TestAddReviewNotAllowed(t *testing.T) {
+	repo := new(MockReviewRepo)
+	request := ReviewRequest{ConfirmationCode: "ABCD-EF23", Score: 9}
+	repo.On("AddReview", context.Background(), 1, request).Return(Review{}, utils.ErrReviewNotAllowed)
+	uc := ReviewsUseCase{repo, new(MockRepo)}
+	_, err := uc.AddReview(context.Background(), 1, request)
+	assert.True(t, errors.Is(err, utils.ErrReviewNotAllowed))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,169 @@
+package item
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/sayooj/trivago/utils"
+	"github.com/sirupsen/logrus"
+)
+
+//RoomTypesHandler handler for the room types of items
+type RoomTypesHandler struct {
+	useCase RoomTypesUseCaseInterface
+	logger  *logrus.Logger
+}
+
+//GetRoomTypes get the room types of an item
+func (h *RoomTypesHandler) GetRoomTypes(w http.ResponseWriter, r *http.Request) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	roomTypes, err := h.useCase.GetRoomTypes(r.Context(), itemID)
+	if err != nil {
+		h.respondWithRoomTypeError(w, err, "Error occured while fetching the room types")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, roomTypes)
+}
+
+//GetRoomType get a room type of an item
+func (h *RoomTypesHandler) GetRoomType(w http.ResponseWriter, r *http.Request) {
+	itemID, roomTypeID, ok := roomTypeURLParams(w, r)
+	if !ok {
+		return
+	}
+	roomType, err := h.useCase.GetRoomType(r.Context(), itemID, roomTypeID)
+	if err != nil {
+		h.respondWithRoomTypeError(w, err, "Error occured while fetching the room type")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, roomType)
+}
+
+//AddRoomType add a room type to an item
+func (h *RoomTypesHandler) AddRoomType(w http.ResponseWriter, r *http.Request) {
+	var roomType RoomType
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return
+	}
+	if !h.decodeRoomType(w, r, &roomType) {
+		return
+	}
+	roomType.ItemID = uint64(itemID)
+	created, err := h.useCase.AddRoomType(r.Context(), roomType)
+	if err != nil {
+		h.respondWithRoomTypeError(w, err, "Failed to add room type")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/item/%d/room-types/%d", itemID, created.ID))
+	utils.RespondWithJSON(w, http.StatusCreated, created)
+}
+
+//UpdateRoomType replace a room type of an item
+func (h *RoomTypesHandler) UpdateRoomType(w http.ResponseWriter, r *http.Request) {
+	var roomType RoomType
+	itemID, roomTypeID, ok := roomTypeURLParams(w, r)
+	if !ok {
+		return
+	}
+	if !h.decodeRoomType(w, r, &roomType) {
+		return
+	}
+	roomType.ID, roomType.ItemID = uint64(roomTypeID), uint64(itemID)
+	updated, err := h.useCase.UpdateRoomType(r.Context(), roomType)
+	if err != nil {
+		h.respondWithRoomTypeError(w, err, "Failed to update room type")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, updated)
+}
+
+//DeleteRoomType remove a room type from an item
+func (h *RoomTypesHandler) DeleteRoomType(w http.ResponseWriter, r *http.Request) {
+	itemID, roomTypeID, ok := roomTypeURLParams(w, r)
+	if !ok {
+		return
+	}
+	err := h.useCase.DeleteRoomType(r.Context(), itemID, roomTypeID)
+	if err != nil {
+		h.respondWithRoomTypeError(w, err, "Failed to delete room type")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, nil)
+}
+
+//roomTypeURLParams reads the item and room type ids of the path, ok is false when a response has already been written
+func roomTypeURLParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	itemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid id number")
+		return 0, 0, false
+	}
+	roomTypeID, err := strconv.Atoi(chi.URLParam(r, "roomTypeId"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid room type id")
+		return 0, 0, false
+	}
+	return itemID, roomTypeID, true
+}
+
+//decodeRoomType decodes and validates the body, a missing max occupancy is the capacity. It is false when a
+//response has already been written
+func (h *RoomTypesHandler) decodeRoomType(w http.ResponseWriter, r *http.Request, roomType *RoomType) bool {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(roomType); err != nil {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return false
+	}
+	if roomType.MaxOccupancy == 0 {
+		roomType.MaxOccupancy = roomType.Capacity
+	}
+	invalidParams := roomType.Validate()
+	if len(invalidParams) > 0 {
+		h.logger.Info("Invalid request payload")
+		utils.RespondWithValidationError(w, http.StatusBadRequest, invalidParams)
+		return false
+	}
+	return true
+}
+
+//respondWithRoomTypeError maps the errors of the room types, message describes any other error
+func (h *RoomTypesHandler) respondWithRoomTypeError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, utils.ErrItemNotFound) {
+		h.logger.Info("Item not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if errors.Is(err, utils.ErrRoomTypeNotFound) {
+		h.logger.Info("Room type not found")
+		utils.RespondWithError(w, http.StatusNotFound, "Room type not found")
+		return
+	}
+	if errors.Is(err, utils.ErrRoomTypeExists) {
+		h.logger.Info("Room type already exists")
+		utils.RespondWithError(w, http.StatusConflict, utils.ErrRoomTypeExists.Error())
+		return
+	}
+	if errors.Is(err, utils.ErrRoomTypeHasActiveBookings) {
+		h.logger.Info("Room type has active bookings")
+		utils.RespondWithError(w, http.StatusConflict, utils.ErrRoomTypeHasActiveBookings.Error())
+		return
+	}
+	h.logger.Info(message)
+	utils.RespondWithError(w, http.StatusInternalServerError, message)
+}
+
+//NewRoomTypesHandler method
+func NewRoomTypesHandler(useCase *RoomTypesUseCase, log *logrus.Logger) *RoomTypesHandler {
+	return &RoomTypesHandler{useCase, log}
+}
@@ -0,0 +1,119 @@
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+type MockRoomTypeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRoomTypeUseCase) GetRoomTypes(ctx context.Context, itemID int) ([]RoomType, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeUseCase) GetRoomType(ctx context.Context, itemID, roomTypeID int) (RoomType, error) {
+	args := m.Called(ctx, itemID, roomTypeID)
+	return args.Get(0).(RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeUseCase) AddRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	args := m.Called(ctx, roomType)
+	return args.Get(0).(RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeUseCase) UpdateRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	args := m.Called(ctx, roomType)
+	return args.Get(0).(RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeUseCase) DeleteRoomType(ctx context.Context, itemID, roomTypeID int) error {
+	args := m.Called(ctx, itemID, roomTypeID)
+	return args.Error(0)
+}
+
+func TestGetRoomTypesHandler(t *testing.T) {
+	uc := new(MockRoomTypeUseCase)
+	th := RoomTypesHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/room-types", "", "id", "1")
+	uc.On("GetRoomTypes", req.Context(), 1).Return([]RoomType{roomType}, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(th.GetRoomTypes).ServeHTTP(rr, req)
+	var res []RoomType
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []RoomType{roomType}, res)
+	uc.AssertExpectations(t)
+}
+
+func TestAddRoomTypeHandler(t *testing.T) {
+	uc := new(MockRoomTypeUseCase)
+	th := RoomTypesHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/room-types", `{"name":"Double","price":9000,"capacity":2,"inventory":10}`, "id", "1")
+	// a missing max occupancy is the capacity
+	uc.On("AddRoomType", req.Context(), RoomType{ItemID: 1, Name: "Double", Price: 9000, Capacity: 2, MaxOccupancy: 2, Inventory: 10}).Return(roomType, nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(th.AddRoomType).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/item/1/room-types/2", rr.Header().Get("Location"))
+	uc.AssertExpectations(t)
+}
+
+func TestAddRoomTypeHandlerInvalid(t *testing.T) {
+	uc := new(MockRoomTypeUseCase)
+	th := RoomTypesHandler{uc, logrus.New()}
+	req := newRouteRequest("POST", "/item/1/room-types", `{"name":"Suite","price":20000,"capacity":4,"max_occupancy":3}`, "id", "1")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(th.AddRoomType).ServeHTTP(rr, req)
+	var res utils.ErrorModel
+	err := json.NewDecoder(rr.Body).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "max_occupancy", res.InvalidParams[0].Name)
+	uc.AssertExpectations(t)
+}
+
+func TestUpdateRoomTypeHandlerExists(t *testing.T) {
+	uc := new(MockRoomTypeUseCase)
+	th := RoomTypesHandler{uc, logrus.New()}
+	req := newRouteRequest("PUT", "/item/1/room-types/2", `{"name":"Suite","price":20000,"capacity":2,"max_occupancy":4,"inventory":2}`, "id", "1", "roomTypeId", "2")
+	uc.On("UpdateRoomType", req.Context(), RoomType{ID: 2, ItemID: 1, Name: "Suite", Price: 20000, Capacity: 2, MaxOccupancy: 4, Inventory: 2}).Return(RoomType{}, utils.ErrRoomTypeExists)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(th.UpdateRoomType).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestDeleteRoomTypeHandlerActiveBookings(t *testing.T) {
+	uc := new(MockRoomTypeUseCase)
+	th := RoomTypesHandler{uc, logrus.New()}
+	req := newRouteRequest("DELETE", "/item/1/room-types/2", "", "id", "1", "roomTypeId", "2")
+	uc.On("DeleteRoomType", req.Context(), 1, 2).Return(utils.ErrRoomTypeHasActiveBookings)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(th.DeleteRoomType).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	uc.AssertExpectations(t)
+}
+
+func TestGetRoomTypeHandlerNotFound(t *testing.T) {
+	uc := new(MockRoomTypeUseCase)
+	th := RoomTypesHandler{uc, logrus.New()}
+	req := newRouteRequest("GET", "/item/1/room-types/9", "", "id", "1", "roomTypeId", "9")
+	uc.On("GetRoomType", req.Context(), 1, 9).Return(RoomType{}, utils.ErrRoomTypeNotFound)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(th.GetRoomType).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	uc.AssertExpectations(t)
+}
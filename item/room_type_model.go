@@ -0,0 +1,47 @@
+package item
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//RoomType is a room category an item sells, like a double room, a suite or a dorm bed. Price is the nightly
+//price of a room for up to Capacity guests, a room takes at most MaxOccupancy guests and Inventory rooms
+//are sold each night
+type RoomType struct {
+	ID           uint64    `json:"id"`
+	ItemID       uint64    `json:"item_id"`
+	Name         string    `json:"name"`
+	Price        uint64    `json:"price"`
+	Capacity     uint      `json:"capacity"`
+	MaxOccupancy uint      `json:"max_occupancy"`
+	Inventory    uint      `json:"inventory"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+//Validate validates the room type
+func (rt RoomType) Validate() []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if rt.Name == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "name", Reason: "name required"})
+	}
+	if utf8.RuneCountInString(rt.Name) > maxRoomTypeNameLength {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "name", Reason: fmt.Sprintf("name should be at most %d characters", maxRoomTypeNameLength)})
+	}
+	if rt.Price == 0 {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "price", Reason: "price required"})
+	}
+	if rt.Capacity == 0 {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "capacity", Reason: "capacity required"})
+	}
+	if rt.MaxOccupancy < rt.Capacity {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "max_occupancy", Reason: "max_occupancy should be >= capacity"})
+	}
+	if rt.MaxOccupancy > maxRoomTypeOccupancy {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "max_occupancy", Reason: fmt.Sprintf("max_occupancy should be <= %d", maxRoomTypeOccupancy)})
+	}
+	return validationErr
+}
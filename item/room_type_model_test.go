@@ -0,0 +1,24 @@
+package item
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoomTypeValidate(t *testing.T) {
+	valid := RoomType{Name: "Double", Price: 9000, Capacity: 2, MaxOccupancy: 3, Inventory: 10}
+	assert.Empty(t, valid.Validate())
+	// names are counted in characters, not bytes
+	assert.Empty(t, RoomType{Name: strings.Repeat("é", maxRoomTypeNameLength), Price: 9000, Capacity: 2, MaxOccupancy: 3}.Validate())
+
+	invalid := RoomType{Name: string(make([]byte, maxRoomTypeNameLength+1)), Capacity: 4, MaxOccupancy: 2}
+	names := []string{}
+	for _, param := range invalid.Validate() {
+		names = append(names, param.Name)
+	}
+	assert.Equal(t, []string{"name", "price", "max_occupancy"}, names)
+
+	assert.Equal(t, "max_occupancy", RoomType{Name: "Dorm bed", Price: 2000, Capacity: 1, MaxOccupancy: maxRoomTypeOccupancy + 1}.Validate()[0].Name)
+}
@@ -0,0 +1,223 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sayooj/trivago/utils"
+)
+
+//RoomTypesRepositoryInterface interface
+type RoomTypesRepositoryInterface interface {
+	GetRoomTypes(ctx context.Context, itemID int) ([]RoomType, error)
+	GetRoomType(ctx context.Context, itemID, roomTypeID int) (RoomType, error)
+	AddRoomType(ctx context.Context, roomType RoomType) (RoomType, error)
+	UpdateRoomType(ctx context.Context, roomType RoomType) (RoomType, error)
+	DeleteRoomType(ctx context.Context, itemID, roomTypeID int) error
+}
+
+const roomTypeSelectQuery = `
+	SELECT
+		id,
+		item_id,
+		name,
+		price,
+		capacity,
+		max_occupancy,
+		inventory,
+		created_at
+	FROM
+		room_type
+	`
+
+//RoomTypesRepository struct
+type RoomTypesRepository struct {
+	db *sql.DB
+}
+
+//GetRoomTypes returns the room types of an item from the cheapest
+func (r *RoomTypesRepository) GetRoomTypes(ctx context.Context, itemID int) ([]RoomType, error) {
+	query := roomTypeSelectQuery + `WHERE item_id = $1 ORDER BY price, id`
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Error occured while fetching room types %w", utils.ErrFetchError)
+	}
+	defer rows.Close()
+	roomTypes := []RoomType{}
+	for rows.Next() {
+		roomType, err := scanRoomType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Error occured while fetching room types %w", utils.ErrFetchError)
+		}
+		roomTypes = append(roomTypes, roomType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error occured while fetching room types %w", utils.ErrFetchError)
+	}
+	return roomTypes, nil
+}
+
+//GetRoomType gets a room type of an item
+func (r *RoomTypesRepository) GetRoomType(ctx context.Context, itemID, roomTypeID int) (RoomType, error) {
+	query := roomTypeSelectQuery + `WHERE item_id = $1 AND id = $2`
+	roomType, err := scanRoomType(r.db.QueryRowContext(ctx, query, itemID, roomTypeID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return RoomType{}, fmt.Errorf("Room type not found %w", utils.ErrRoomTypeNotFound)
+		}
+		return RoomType{}, fmt.Errorf("Failed to fetch room type %w", utils.ErrFetchError)
+	}
+	return roomType, nil
+}
+
+//AddRoomType adds a room type to an item, its name should not be taken by another room type of the item
+func (r *RoomTypesRepository) AddRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return RoomType{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	err = lockItemRow(ctx, tx, int(roomType.ItemID), utils.ErrRoomTypeNotSaved)
+	if err != nil {
+		return RoomType{}, err
+	}
+	insertQry := `INSERT INTO room_type(item_id, name, price, capacity, max_occupancy, inventory) VALUES($1 , $2 , $3 , $4 , $5 , $6)
+		ON CONFLICT (item_id, name) DO NOTHING
+		RETURNING id, created_at;`
+	err = tx.QueryRowContext(ctx, insertQry, roomType.ItemID, roomType.Name, roomType.Price, roomType.Capacity, roomType.MaxOccupancy, roomType.Inventory).Scan(&roomType.ID, &roomType.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = utils.ErrRoomTypeExists
+			return RoomType{}, fmt.Errorf("Room type %s already exists %w", roomType.Name, err)
+		}
+		return RoomType{}, fmt.Errorf("Error occured during insertion %w", utils.ErrRoomTypeNotSaved)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return RoomType{}, fmt.Errorf("Error occured during insertion %w", utils.ErrRoomTypeNotSaved)
+	}
+	return roomType, nil
+}
+
+//UpdateRoomType replaces a room type of an item. The inventory is the room count of every upcoming night,
+//it can not drop below the rooms already booked
+func (r *RoomTypesRepository) UpdateRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return RoomType{}, fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	err = lockItemRow(ctx, tx, int(roomType.ItemID), utils.ErrRoomTypeNotSaved)
+	if err != nil {
+		return RoomType{}, err
+	}
+	var taken bool
+	takenQry := `SELECT EXISTS (SELECT 1 FROM room_type WHERE item_id = $1 AND name = $2 AND id <> $3);`
+	err = tx.QueryRowContext(ctx, takenQry, roomType.ItemID, roomType.Name, roomType.ID).Scan(&taken)
+	if err != nil {
+		return RoomType{}, fmt.Errorf("Error occured while fetching room types %w", utils.ErrRoomTypeNotSaved)
+	}
+	if taken {
+		err = utils.ErrRoomTypeExists
+		return RoomType{}, fmt.Errorf("Room type %s already exists %w", roomType.Name, err)
+	}
+
+	updateQry := `UPDATE room_type SET name = $3, price = $4, capacity = $5, max_occupancy = $6, inventory = $7
+		WHERE item_id = $1 AND id = $2 RETURNING created_at;`
+	err = tx.QueryRowContext(ctx, updateQry, roomType.ItemID, roomType.ID, roomType.Name, roomType.Price, roomType.Capacity, roomType.MaxOccupancy, roomType.Inventory).Scan(&roomType.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return RoomType{}, fmt.Errorf("Room type not found %w", utils.ErrRoomTypeNotFound)
+		}
+		return RoomType{}, fmt.Errorf("Error occured while updating the room type %w", utils.ErrRoomTypeNotSaved)
+	}
+	inventoryQry := `UPDATE room_type_inventory SET rooms_total = GREATEST($2, rooms_booked) WHERE room_type_id = $1 AND date >= CURRENT_DATE;`
+	_, err = tx.ExecContext(ctx, inventoryQry, roomType.ID, roomType.Inventory)
+	if err != nil {
+		return RoomType{}, fmt.Errorf("Error occured while updating the inventory %w", utils.ErrRoomTypeNotSaved)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return RoomType{}, fmt.Errorf("Error occured while updating the room type %w", utils.ErrRoomTypeNotSaved)
+	}
+	return roomType, nil
+}
+
+//DeleteRoomType removes a room type from an item, its past bookings are kept without it. Room types with
+//pending, confirmed or checked in bookings can not be removed
+func (r *RoomTypesRepository) DeleteRoomType(ctx context.Context, itemID, roomTypeID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction%w", utils.ErrTransactionBeginFailed)
+	}
+	defer func() {
+		if err != nil {
+			// rolling back if error occured
+			tx.Rollback()
+		}
+	}()
+
+	// the lock keeps new bookings out until the room type is gone
+	err = lockItemRow(ctx, tx, itemID, utils.ErrRoomTypeNotSaved)
+	if err != nil {
+		return err
+	}
+	var active bool
+	activeQry := `SELECT EXISTS (SELECT 1 FROM item_booking WHERE room_type_id = $1 AND status = ANY($2));`
+	err = tx.QueryRowContext(ctx, activeQry, roomTypeID, pq.Array(activeBookingStatuses)).Scan(&active)
+	if err != nil {
+		return fmt.Errorf("Error occured while fetching the bookings %w", utils.ErrRoomTypeNotSaved)
+	}
+	if active {
+		err = utils.ErrRoomTypeHasActiveBookings
+		return fmt.Errorf("Room type can not be removed %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM room_type WHERE item_id = $1 AND id = $2;`, itemID, roomTypeID)
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the room type %w", utils.ErrRoomTypeNotSaved)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the room type %w", utils.ErrRoomTypeNotSaved)
+	}
+	if rows == 0 {
+		err = utils.ErrRoomTypeNotFound
+		return fmt.Errorf("Room type not found %w", err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Error occured while deleting the room type %w", utils.ErrRoomTypeNotSaved)
+	}
+	return nil
+}
+
+//scanRoomType scans a row selected with roomTypeSelectQuery
+func scanRoomType(row rowScanner) (RoomType, error) {
+	var rt RoomType
+	err := row.Scan(&rt.ID, &rt.ItemID, &rt.Name, &rt.Price, &rt.Capacity, &rt.MaxOccupancy, &rt.Inventory, &rt.CreatedAt)
+	if err != nil {
+		return RoomType{}, err
+	}
+	return rt, nil
+}
+
+//NewRoomTypesRepository method
+func NewRoomTypesRepository(db *sql.DB) *RoomTypesRepository {
+	return &RoomTypesRepository{db}
+}
@@ -0,0 +1,151 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sayooj/trivago/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var roomTypeColumns = []string{"id", "item_id", "name", "price", "capacity", "max_occupancy", "inventory", "created_at"}
+
+func TestGetRoomTypes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`FROM\s+room_type\s+WHERE item_id = \$1 ORDER BY price, id`).WithArgs(1).WillReturnRows(sqlmock.NewRows(roomTypeColumns).
+		AddRow(3, 1, "Dorm bed", 2000, 1, 1, 24, time.Now()).
+		AddRow(2, 1, "Double", 9000, 2, 3, 10, time.Now()))
+	repo := NewRoomTypesRepository(db)
+	roomTypes, err := repo.GetRoomTypes(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, roomTypes, 2)
+	assert.Equal(t, "Dorm bed", roomTypes[0].Name)
+	assert.Equal(t, uint(3), roomTypes[1].MaxOccupancy)
+}
+
+func TestGetRoomTypeNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`WHERE item_id = \$1 AND id = \$2`).WithArgs(1, 9).WillReturnRows(sqlmock.NewRows(roomTypeColumns))
+	repo := NewRoomTypesRepository(db)
+	_, err = repo.GetRoomType(context.Background(), 1, 9)
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeNotFound))
+}
+
+func TestAddRoomType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	request := RoomType{ItemID: 1, Name: "Double", Price: 9000, Capacity: 2, MaxOccupancy: 3, Inventory: 10}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT item_id FROM item .* FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO room_type`).WithArgs(request.ItemID, request.Name, request.Price, request.Capacity, request.MaxOccupancy, request.Inventory).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, time.Now()))
+	mock.ExpectCommit()
+	repo := NewRoomTypesRepository(db)
+	created, err := repo.AddRoomType(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), created.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddRoomTypeExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	request := RoomType{ItemID: 1, Name: "Double", Price: 9000, Capacity: 2, MaxOccupancy: 3, Inventory: 10}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`ON CONFLICT \(item_id, name\) DO NOTHING`).WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+	mock.ExpectRollback()
+	repo := NewRoomTypesRepository(db)
+	_, err = repo.AddRoomType(context.Background(), request)
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeExists))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRoomType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	request := RoomType{ID: 2, ItemID: 1, Name: "Double", Price: 9500, Capacity: 2, MaxOccupancy: 3, Inventory: 8}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM room_type WHERE item_id = \$1 AND name = \$2 AND id <> \$3\)`).WithArgs(request.ItemID, request.Name, request.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`UPDATE room_type SET`).WithArgs(request.ItemID, request.ID, request.Name, request.Price, request.Capacity, request.MaxOccupancy, request.Inventory).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectExec(`UPDATE room_type_inventory SET rooms_total = GREATEST\(\$2, rooms_booked\)`).WithArgs(request.ID, request.Inventory).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+	repo := NewRoomTypesRepository(db)
+	updated, err := repo.UpdateRoomType(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9500), updated.Price)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRoomTypeNameTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	request := RoomType{ID: 2, ItemID: 1, Name: "Suite", Price: 9500, Capacity: 2, MaxOccupancy: 3, Inventory: 8}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(request.ItemID, request.Name, request.ID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+	repo := NewRoomTypesRepository(db)
+	_, err = repo.UpdateRoomType(context.Background(), request)
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeExists))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRoomTypeActiveBookings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`FROM item_booking WHERE room_type_id = \$1 AND status = ANY\(\$2\)`).WithArgs(2, sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+	repo := NewRoomTypesRepository(db)
+	err = repo.DeleteRoomType(context.Background(), 1, 2)
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeHasActiveBookings))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRoomTypeNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow(1))
+	mock.ExpectQuery(`FROM item_booking`).WithArgs(9, sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`DELETE FROM room_type WHERE item_id = \$1 AND id = \$2`).WithArgs(1, 9).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	repo := NewRoomTypesRepository(db)
+	err = repo.DeleteRoomType(context.Background(), 1, 9)
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package item
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+//RoomTypesUseCaseInterface interface
+type RoomTypesUseCaseInterface interface {
+	GetRoomTypes(ctx context.Context, itemID int) ([]RoomType, error)
+	GetRoomType(ctx context.Context, itemID, roomTypeID int) (RoomType, error)
+	AddRoomType(ctx context.Context, roomType RoomType) (RoomType, error)
+	UpdateRoomType(ctx context.Context, roomType RoomType) (RoomType, error)
+	DeleteRoomType(ctx context.Context, itemID, roomTypeID int) error
+}
+
+//RoomTypesUseCase struct
+type RoomTypesUseCase struct {
+	roomTypeRepo RoomTypesRepositoryInterface
+	itemRepo     ItemsRepositoryInterface
+}
+
+//GetRoomTypes returns the room types of an item
+func (u *RoomTypesUseCase) GetRoomTypes(ctx context.Context, itemID int) ([]RoomType, error) {
+	_, err := u.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+	}
+	return u.roomTypeRepo.GetRoomTypes(ctx, itemID)
+}
+
+//GetRoomType gets a room type of an item
+func (u *RoomTypesUseCase) GetRoomType(ctx context.Context, itemID, roomTypeID int) (RoomType, error) {
+	_, err := u.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return RoomType{}, fmt.Errorf("Item not found %w", utils.ErrItemNotFound)
+	}
+	return u.roomTypeRepo.GetRoomType(ctx, itemID, roomTypeID)
+}
+
+//AddRoomType adds a room type to an item
+func (u *RoomTypesUseCase) AddRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	return u.roomTypeRepo.AddRoomType(ctx, roomType)
+}
+
+//UpdateRoomType replaces a room type of an item
+func (u *RoomTypesUseCase) UpdateRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	return u.roomTypeRepo.UpdateRoomType(ctx, roomType)
+}
+
+//DeleteRoomType removes a room type from an item
+func (u *RoomTypesUseCase) DeleteRoomType(ctx context.Context, itemID, roomTypeID int) error {
+	return u.roomTypeRepo.DeleteRoomType(ctx, itemID, roomTypeID)
+}
+
+//NewRoomTypesUseCase method
+func NewRoomTypesUseCase(roomTypeRepo *RoomTypesRepository, itemRepo *ItemsRepository) *RoomTypesUseCase {
+	return &RoomTypesUseCase{roomTypeRepo, itemRepo}
+}
@@ -0,0 +1,76 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+var roomType = RoomType{ID: 2, ItemID: 1, Name: "Double", Price: 9000, Capacity: 2, MaxOccupancy: 3, Inventory: 10}
+
+type MockRoomTypeRepo struct {
+	mock.Mock
+}
+
+func (m *MockRoomTypeRepo) GetRoomTypes(ctx context.Context, itemID int) ([]RoomType, error) {
+	args := m.Called(ctx, itemID)
+	return args.Get(0).([]RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeRepo) GetRoomType(ctx context.Context, itemID, roomTypeID int) (RoomType, error) {
+	args := m.Called(ctx, itemID, roomTypeID)
+	return args.Get(0).(RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeRepo) AddRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	args := m.Called(ctx, roomType)
+	return args.Get(0).(RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeRepo) UpdateRoomType(ctx context.Context, roomType RoomType) (RoomType, error) {
+	args := m.Called(ctx, roomType)
+	return args.Get(0).(RoomType), args.Error(1)
+}
+
+func (m *MockRoomTypeRepo) DeleteRoomType(ctx context.Context, itemID, roomTypeID int) error {
+	args := m.Called(ctx, itemID, roomTypeID)
+	return args.Error(0)
+}
+
+func TestGetRoomTypesSuccess(t *testing.T) {
+	repo := new(MockRoomTypeRepo)
+	itemRepo := new(MockRepo)
+	itemRepo.On("GetItem", context.Background(), 1).Return(item, nil)
+	repo.On("GetRoomTypes", context.Background(), 1).Return([]RoomType{roomType}, nil)
+	uc := RoomTypesUseCase{repo, itemRepo}
+	res, err := uc.GetRoomTypes(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	repo.AssertExpectations(t)
+	itemRepo.AssertExpectations(t)
+}
+
+func TestGetRoomTypeItemNotFound(t *testing.T) {
+	repo := new(MockRoomTypeRepo)
+	itemRepo := new(MockRepo)
+	itemRepo.On("GetItem", context.Background(), 1).Return(Item{}, utils.ErrItemNotFound)
+	uc := RoomTypesUseCase{repo, itemRepo}
+	_, err := uc.GetRoomType(context.Background(), 1, 2)
+	assert.True(t, errors.Is(err, utils.ErrItemNotFound))
+	repo.AssertExpectations(t)
+}
+
+func TestAddRoomTypeNameTaken(t *testing.T) {
+	repo := new(MockRoomTypeRepo)
+	request := RoomType{ItemID: 1, Name: "Double", Price: 9000, Capacity: 2, MaxOccupancy: 2}
+	repo.On("AddRoomType", context.Background(), request).Return(RoomType{}, utils.ErrRoomTypeExists)
+	uc := RoomTypesUseCase{repo, new(MockRepo)}
+	_, err := uc.AddRoomType(context.Background(), request)
+	assert.True(t, errors.Is(err, utils.ErrRoomTypeExists))
+	repo.AssertExpectations(t)
+}
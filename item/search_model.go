@@ -0,0 +1,127 @@
+package item
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sayooj/trivago/utils"
+)
+
+// ItemSearch is a full-text search, narrowed by the filters of GET /item
+type ItemSearch struct {
+	Query  string
+	Filter ItemFilter
+}
+
+// SearchResults is a page of items ordered by how well they match the search. Fuzzy is set when
+// nothing matched the words of the query and the items are the ones with similar spelling
+type SearchResults struct {
+	ItemList
+	Fuzzy bool `json:"fuzzy"`
+}
+
+// NewItemSearch builds an ItemSearch from the query parameters of GET /item/search, q is the text to
+// search. The results are ranked, so sort and cursor are not accepted
+func NewItemSearch(query url.Values) (ItemSearch, []utils.InvalidParams) {
+	search := ItemSearch{Query: strings.TrimSpace(query.Get("q"))}
+	filter, validationErr := NewItemFilter(query)
+	search.Filter = filter
+	if search.Query == "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "q", Reason: "q required"})
+	}
+	if utf8.RuneCountInString(search.Query) > maxSearchQueryLength {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "q", Reason: fmt.Sprintf("q should be at most %d characters", maxSearchQueryLength)})
+	}
+	validationErr = append(validationErr, unorderedParams(query, "relevance")...)
+	return search, validationErr
+}
+
+// NearbySearch finds the items within RadiusKm of a point, narrowed by the filters of GET /item
+type NearbySearch struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Filter    ItemFilter
+}
+
+// NearbyItem is an item along with its distance from the point of the search
+type NearbyItem struct {
+	Item
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NearbyResults is a page of items, nearest first
+type NearbyResults struct {
+	Items []NearbyItem `json:"items"`
+	Meta  ListMeta     `json:"meta"`
+}
+
+// NewNearbySearch builds a NearbySearch from the query parameters of GET /item/nearby, lat and lng are
+// required and radius_km defaults to defaultNearbyRadiusKm. The results are ordered by distance, so sort
+// and cursor are not accepted
+func NewNearbySearch(query url.Values) (NearbySearch, []utils.InvalidParams) {
+	search := NearbySearch{RadiusKm: defaultNearbyRadiusKm}
+	filter, validationErr := NewItemFilter(query)
+	search.Filter = filter
+	coordinate := func(name string, max float64) float64 {
+		v := query.Get(name)
+		if v == "" {
+			validationErr = append(validationErr, utils.InvalidParams{Name: name, Reason: name + " required"})
+			return 0
+		}
+		value, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(value) || value < -max || value > max {
+			validationErr = append(validationErr, utils.InvalidParams{Name: name, Reason: fmt.Sprintf("%s should be >= %v and <= %v", name, -max, max)})
+		}
+		return value
+	}
+	search.Latitude = coordinate("lat", 90)
+	search.Longitude = coordinate("lng", 180)
+	if v := query.Get("radius_km"); v != "" {
+		radius, err := strconv.ParseFloat(v, 64)
+		if err != nil || !(radius > 0 && radius <= maxNearbyRadiusKm) {
+			validationErr = append(validationErr, utils.InvalidParams{Name: "radius_km", Reason: fmt.Sprintf("radius_km should be > 0 and <= %v", maxNearbyRadiusKm)})
+		}
+		search.RadiusKm = radius
+	}
+	validationErr = append(validationErr, unorderedParams(query, "distance")...)
+	return search, validationErr
+}
+
+// BoundingBox returns the range of latitudes and longitudes around the point of the search that covers
+// its radius. allLongitudes is set when the radius reaches a pole or the antimeridian, every longitude
+// may be in range then
+func (n NearbySearch) BoundingBox() (minLat, maxLat, minLng, maxLng float64, allLongitudes bool) {
+	angle := n.RadiusKm / earthRadiusKm
+	degrees := angle * 180 / math.Pi
+	minLat, maxLat = n.Latitude-degrees, n.Latitude+degrees
+	if minLat <= -90 || maxLat >= 90 {
+		return math.Max(minLat, -90), math.Min(maxLat, 90), -180, 180, true
+	}
+	ratio := math.Sin(angle) / math.Cos(n.Latitude*math.Pi/180)
+	if ratio >= 1 {
+		return minLat, maxLat, -180, 180, true
+	}
+	lngDegrees := math.Asin(ratio) * 180 / math.Pi
+	minLng, maxLng = n.Longitude-lngDegrees, n.Longitude+lngDegrees
+	if minLng < -180 || maxLng > 180 {
+		return minLat, maxLat, -180, 180, true
+	}
+	return minLat, maxLat, minLng, maxLng, false
+}
+
+// unorderedParams rejects sort and cursor for a listing ordered by its search
+func unorderedParams(query url.Values, order string) []utils.InvalidParams {
+	validationErr := []utils.InvalidParams{}
+	if query.Get("sort") != "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "sort", Reason: "sort is not supported, results are ordered by " + order})
+	}
+	if query.Get("cursor") != "" {
+		validationErr = append(validationErr, utils.InvalidParams{Name: "cursor", Reason: "cursor is not supported, use offset"})
+	}
+	return validationErr
+}
@@ -0,0 +1,54 @@
+package item
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNewItemSearch(t *testing.T) {
+	search, invalidParams := NewItemSearch(url.Values{"q": {" seaside inn "}, "city": {"kovalam"}})
+	if len(invalidParams) != 0 {
+		t.Fatalf("Expected no error got %v", invalidParams)
+	}
+	if search.Query != "seaside inn" || search.Filter.City != "kovalam" || search.Filter.Limit != defaultItemsLimit {
+		t.Errorf("Unexpected search %+v", search)
+	}
+	if _, invalidParams = NewItemSearch(url.Values{"q": {"inn"}, "cursor": {"abc"}}); invalidParams[len(invalidParams)-1].Name != "cursor" {
+		t.Errorf("Expected cursor to be invalid got %v", invalidParams)
+	}
+	// the length is counted in characters, not bytes
+	if _, invalidParams = NewItemSearch(url.Values{"q": {strings.Repeat("東京", maxSearchQueryLength/2)}}); len(invalidParams) != 0 {
+		t.Errorf("Expected no error got %v", invalidParams)
+	}
+	if _, invalidParams = NewItemSearch(url.Values{"q": {strings.Repeat("é", maxSearchQueryLength+1)}}); len(invalidParams) != 1 || invalidParams[0].Name != "q" {
+		t.Errorf("Expected q to be invalid got %v", invalidParams)
+	}
+}
+
+func TestNewNearbySearch(t *testing.T) {
+	search, invalidParams := NewNearbySearch(url.Values{"lat": {"52.52"}, "lng": {"13.405"}})
+	if len(invalidParams) != 0 {
+		t.Fatalf("Expected no error got %v", invalidParams)
+	}
+	if search.Latitude != 52.52 || search.Longitude != 13.405 || search.RadiusKm != defaultNearbyRadiusKm {
+		t.Errorf("Unexpected search %+v", search)
+	}
+	_, invalidParams = NewNearbySearch(url.Values{"lng": {"200"}, "radius_km": {"0"}})
+	if len(invalidParams) != 3 || invalidParams[0].Name != "lat" || invalidParams[1].Name != "lng" || invalidParams[2].Name != "radius_km" {
+		t.Errorf("Expected lat, lng and radius_km to be invalid got %v", invalidParams)
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	minLat, maxLat, minLng, maxLng, all := NearbySearch{Latitude: 0, Longitude: 0, RadiusKm: 111.19}.BoundingBox()
+	if all || minLat > -0.99 || minLat < -1.01 || maxLat < 0.99 || minLng > -0.99 || maxLng < 0.99 {
+		t.Errorf("Expected about one degree around the point got %v %v %v %v", minLat, maxLat, minLng, maxLng)
+	}
+	if _, _, _, _, all = (NearbySearch{Latitude: 10, Longitude: 179.9, RadiusKm: 50}).BoundingBox(); !all {
+		t.Errorf("Expected every longitude across the antimeridian")
+	}
+	if _, maxLat, _, _, all = (NearbySearch{Latitude: 89.9, Longitude: 0, RadiusKm: 50}).BoundingBox(); !all || maxLat != 90 {
+		t.Errorf("Expected every longitude around the pole got %v", maxLat)
+	}
+}
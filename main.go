@@ -16,6 +16,10 @@ import (
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
+	"github.com/sayooj/trivago/amenity"
+	"github.com/sayooj/trivago/currency"
+	"github.com/sayooj/trivago/geocode"
+	"github.com/sayooj/trivago/idempotency"
 	"github.com/sayooj/trivago/item"
 	"github.com/sayooj/trivago/router"
 	"github.com/sayooj/trivago/utils"
@@ -50,6 +54,18 @@ func init() {
 }
 
 func main() {
+	// go run . import items.csv adds items from a file instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+	// go run . postal-codes IN.txt loads postal code reference data instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "postal-codes" {
+		os.Exit(runLoadPostalCodes(os.Args[2:]))
+	}
+	// go run . exchange-rates eurofxref.csv loads the ECB reference rates instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "exchange-rates" {
+		os.Exit(runLoadExchangeRates(os.Args[2:]))
+	}
 	server.runServer()
 }
 
@@ -65,28 +81,61 @@ func initializeRoutes() http.Handler {
 	log.SetFormatter(&logrus.JSONFormatter{})
 	//repositories
 	ir := item.NewItemsRepository(server.db)
+	br := item.NewBookingsRepository(server.db)
+	mr := item.NewImagesRepository(server.db)
+	tr := item.NewRoomTypesRepository(server.db)
+	qr := item.NewPricingRepository(server.db)
+	vr := item.NewReviewsRepository(server.db)
+	kr := idempotency.NewKeysRepository(server.db)
+	pr := geocode.NewPostalCodesRepository(server.db)
+	ar := amenity.NewAmenitiesRepository(server.db)
+	cr := currency.NewExchangeRatesRepository(server.db)
 
 	//usecases
-	iu := item.NewItemsUseCase(ir)
+	gu := geocode.NewGeocodeUseCase(pr)
+	cu := currency.NewExchangeRatesUseCase(cr)
+	iu := item.NewItemsUseCase(ir, gu, cu)
+	bu := item.NewBookingsUseCase(br, ir)
+	mu := item.NewImagesUseCase(mr, ir)
+	tu := item.NewRoomTypesUseCase(tr, ir)
+	qu := item.NewPricingUseCase(qr, ir)
+	vu := item.NewReviewsUseCase(vr, ir)
+	au := amenity.NewAmenitiesUseCase(ar)
 
 	//handlers
 	ih := item.NewItemsHandler(iu, log)
+	bh := item.NewBookingsHandler(bu, log)
+	mh := item.NewImagesHandler(mu, log)
+	th := item.NewRoomTypesHandler(tu, log)
+	qh := item.NewPricingHandler(qu, log)
+	vh := item.NewReviewsHandler(vu, log)
+	ah := amenity.NewAmenitiesHandler(au, log)
+	ch := currency.NewExchangeRatesHandler(cu, log)
+
+	//middlewares
+	im := idempotency.NewMiddleware(kr, log)
 
 	r := chi.NewRouter()
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match", utils.ActorHeader, idempotency.HeaderKey},
+		ExposedHeaders: []string{"Location", "ETag", idempotency.HeaderReplayed},
 	}))
 
+	r.Use(utils.LimitRequestID)
 	r.Use(middleware.RequestID)
+	r.Use(utils.Actor)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	timeout := middleware.Timeout(60 * time.Second)
 	r.Route("/", func(r chi.Router) {
-		r.Mount("/item", router.ItemsRoutes(ih))
+		r.Mount("/item", router.ItemsRoutes(ih, bh, mh, th, qh, vh, im.Handler, timeout))
+		r.With(timeout).Mount("/booking", router.BookingRoutes(bh))
+		r.With(timeout).Mount("/amenity", router.AmenityRoutes(ah))
+		r.With(timeout).Mount("/exchange-rate", router.ExchangeRateRoutes(ch))
 	})
 	return r
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sayooj/trivago/geocode"
+)
+
+//runLoadPostalCodes stores the places of GeoNames postal code dumps, it returns the exit code
+func runLoadPostalCodes(args []string) int {
+	flags := flag.NewFlagSet("postal-codes", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: postal-codes <file>...")
+		fmt.Fprintln(flags.Output(), "files are GeoNames postal code dumps, like IN.txt from https://download.geonames.org/export/zip/")
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	uc := geocode.NewGeocodeUseCase(geocode.NewPostalCodesRepository(server.db))
+	for _, path := range flags.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		count, err := uc.LoadPostalCodes(context.Background(), file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d places loaded\n", path, count)
+	}
+	return 0
+}
@@ -1,20 +1,89 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi"
+	"github.com/sayooj/trivago/amenity"
+	"github.com/sayooj/trivago/currency"
 	"github.com/sayooj/trivago/item"
 )
 
-//ItemsRoutes set the routes for the Item
-func ItemsRoutes(h *item.ItemsHandler) *chi.Mux {
+//ItemsRoutes set the routes for the Item, idempotent guards the routes that create resources and
+//timeout bounds every route but the export and the import, which stream for as long as they take
+func ItemsRoutes(h *item.ItemsHandler, bh *item.BookingsHandler, imh *item.ImagesHandler, rth *item.RoomTypesHandler, ph *item.PricingHandler, rvh *item.ReviewsHandler, idempotent, timeout func(http.Handler) http.Handler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/export", h.ExportItems)  //GET /item/export?format=csv
+	r.Post("/import", h.ImportItems) //POST /item/import
+	r.Group(func(r chi.Router) {
+		r.Use(timeout)
+		r.Get("/", h.GetItems)                                            //GET /item
+		r.Get("/search", h.SearchItems)                                   //GET /item/search?q=berlin
+		r.Get("/nearby", h.NearbyItems)                                   //GET /item/nearby?lat=52.52&lng=13.40&radius_km=5
+		r.Get("/{id}", h.GetItem)                                         //GET /item/56
+		r.With(idempotent).Post("/", h.AddItem)                           //POST /item
+		r.With(idempotent).Post("/batch", h.BatchItems)                   //POST /item/batch
+		r.Put("/{id}", h.UpdateItem)                                      //PUT /item/56
+		r.Patch("/{id}", h.PatchItem)                                     //PATCH /item/56
+		r.Delete("/{id}", h.DeleteItem)                                   //DELETE /item/56
+		r.Post("/{id}/restore", h.RestoreItem)                            //POST /item/56/restore
+		r.Delete("/{id}/purge", h.PurgeItem)                              //DELETE /item/56/purge
+		r.With(idempotent).Post("/{id}/book", h.BookAccommodation)        //POST /item/56/booking
+		r.Get("/{id}/bookings", bh.GetItemBookings)                       //GET /item/56/bookings
+		r.Get("/{id}/history", h.GetItemHistory)                          //GET /item/56/history
+		r.Get("/{id}/images", imh.GetImages)                              //GET /item/56/images
+		r.With(idempotent).Post("/{id}/images", imh.AddImage)             //POST /item/56/images
+		r.Get("/{id}/images/{imageId}", imh.GetImage)                     //GET /item/56/images/3
+		r.Put("/{id}/images/{imageId}", imh.UpdateImage)                  //PUT /item/56/images/3
+		r.Delete("/{id}/images/{imageId}", imh.DeleteImage)               //DELETE /item/56/images/3
+		r.Get("/{id}/room-types", rth.GetRoomTypes)                       //GET /item/56/room-types
+		r.With(idempotent).Post("/{id}/room-types", rth.AddRoomType)      //POST /item/56/room-types
+		r.Get("/{id}/room-types/{roomTypeId}", rth.GetRoomType)           //GET /item/56/room-types/2
+		r.Put("/{id}/room-types/{roomTypeId}", rth.UpdateRoomType)        //PUT /item/56/room-types/2
+		r.Delete("/{id}/room-types/{roomTypeId}", rth.DeleteRoomType)     //DELETE /item/56/room-types/2
+		r.Get("/{id}/pricing-rules", ph.GetPricingRules)                  //GET /item/56/pricing-rules
+		r.With(idempotent).Post("/{id}/pricing-rules", ph.AddPricingRule) //POST /item/56/pricing-rules
+		r.Get("/{id}/pricing-rules/{ruleId}", ph.GetPricingRule)          //GET /item/56/pricing-rules/4
+		r.Put("/{id}/pricing-rules/{ruleId}", ph.UpdatePricingRule)       //PUT /item/56/pricing-rules/4
+		r.Delete("/{id}/pricing-rules/{ruleId}", ph.DeletePricingRule)    //DELETE /item/56/pricing-rules/4
+		r.Get("/{id}/quote", ph.GetQuote)                                 //GET /item/56/quote?checkin=2030-01-10&checkout=2030-01-12&rooms=2
+		r.Get("/{id}/reviews", rvh.GetReviews)                            //GET /item/56/reviews?limit=20&offset=0
+		r.With(idempotent).Post("/{id}/reviews", rvh.AddReview)           //POST /item/56/reviews
+	})
+	return r
+}
+
+//BookingRoutes set the routes for the Booking
+func BookingRoutes(h *item.BookingsHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Get("/{bookingId}", h.GetBooking)                 //GET /booking/12
+		r.Get("/code/{code}", h.GetBookingByCode)           //GET /booking/code/ABCD-EF23
+		r.Put("/{bookingId}/status", h.UpdateBookingStatus) //PUT /booking/12/status
+		r.Delete("/{bookingId}", h.CancelBooking)           //DELETE /booking/12
+	})
+	return r
+}
+
+//AmenityRoutes set the routes for the amenity catalogue
+func AmenityRoutes(h *amenity.AmenitiesHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Get("/", h.GetAmenities)           //GET /amenity?category=wellness
+		r.Get("/{code}", h.GetAmenity)       //GET /amenity/pool
+		r.Post("/", h.AddAmenity)            //POST /amenity
+		r.Put("/{code}", h.UpdateAmenity)    //PUT /amenity/pool
+		r.Delete("/{code}", h.DeleteAmenity) //DELETE /amenity/pool
+	})
+	return r
+}
+
+//ExchangeRateRoutes set the routes for the exchange rate table
+func ExchangeRateRoutes(h *currency.ExchangeRatesHandler) *chi.Mux {
 	r := chi.NewRouter()
 	r.Group(func(r chi.Router) {
-		r.Get("/", h.GetItems)                    //GET /item
-		r.Get("/{id}", h.GetItem)                 //GET /item/56
-		r.Post("/", h.AddItem)                    //POST /item
-		r.Put("/{id}", h.UpdateItem)              //PUT /item/56
-		r.Delete("/{id}", h.DeleteItem)           //DELETE /item/56
-		r.Post("/{id}/book", h.BookAccommodation) //POST /item/56/booking
+		r.Get("/", h.GetRates)  //GET /exchange-rate
+		r.Put("/", h.SaveRates) //PUT /exchange-rate
 	})
 	return r
 }
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+)
+
+//ActorHeader names who makes the request, it is recorded in the item history
+const ActorHeader = "X-Actor"
+
+//AnonymousActor is recorded when a request has no ActorHeader
+const AnonymousActor = "anonymous"
+
+//MaxActorLength is the longest actor and request id the item history keeps, longer ones are cut
+const MaxActorLength = 100
+
+type actorKey struct{}
+
+//Actor is a middleware that stores the ActorHeader of the request in its context
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := r.Header.Get(ActorHeader)
+		if actor == "" {
+			actor = AnonymousActor
+		}
+		actor = truncate(actor, MaxActorLength)
+		next.ServeHTTP(w, r.WithContext(WithActor(r.Context(), actor)))
+	})
+}
+
+//LimitRequestID is a middleware that cuts the request id sent by the client to MaxActorLength, it runs before
+//middleware.RequestID which passes the header through unchanged
+func LimitRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID := r.Header.Get(middleware.RequestIDHeader); requestID != "" {
+			r.Header.Set(middleware.RequestIDHeader, truncate(requestID, MaxActorLength))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// truncate cuts value to max characters, the way a VARCHAR column counts them
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
+
+//WithActor returns a copy of ctx carrying the actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+//GetActor returns the actor stored in ctx, AnonymousActor when there is none
+func GetActor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok {
+		return actor
+	}
+	return AnonymousActor
+}
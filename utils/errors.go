@@ -23,8 +23,80 @@ var (
 	ErrBookingFailed = errors.New("Booking failed")
 	//ErrTransactionBeginFailed when transaction begin failed
 	ErrTransactionBeginFailed = errors.New("Failed to begin transaction")
+	//ErrTransactionCommitFailed when transaction commit failed
+	ErrTransactionCommitFailed = errors.New("Failed to commit transaction")
 	//ErrStatementCreationFailed when statement creation failed
 	ErrStatementCreationFailed = errors.New("Failed to create the statement")
+	//ErrBookingNotFound when booking not found in db
+	ErrBookingNotFound = errors.New("Booking not found")
+	//ErrBookingNotUpdated when an error occured while updating a booking
+	ErrBookingNotUpdated = errors.New("Error occured while updating the booking")
+	//ErrBookingStatusTransition when a booking can not move to the requested status
+	ErrBookingStatusTransition = errors.New("Booking status can not be changed")
+	//ErrInvalidBookingStatus when the requested status is not a booking status
+	ErrInvalidBookingStatus = errors.New("Invalid booking status")
+	//ErrItemVersionMismatch when a item changed since the version the request was based on
+	ErrItemVersionMismatch = errors.New("Item has been modified")
+	//ErrItemHasActiveBookings when a item still has bookings that are not over
+	ErrItemHasActiveBookings = errors.New("Item has active bookings")
+	//ErrInvalidImport when an import file can not be read
+	ErrInvalidImport = errors.New("Invalid import file")
+	//ErrInvalidBatchOperation when an operation of a batch is not valid
+	ErrInvalidBatchOperation = errors.New("Invalid batch operation")
+	//ErrBatchRolledBack when an operation of an atomic batch is undone because another one failed
+	ErrBatchRolledBack = errors.New("Rolled back, another operation of the batch failed")
+	//ErrInvalidPostalCodes when a postal code file can not be read
+	ErrInvalidPostalCodes = errors.New("Invalid postal code file")
+	//ErrPostalCodesNotSaved when postal codes could not be stored
+	ErrPostalCodesNotSaved = errors.New("Error occured while saving postal codes")
+	//ErrInvalidLocation when the zip code of a location is not a postal code of its country
+	ErrInvalidLocation = errors.New("zip code is not a postal code of the country")
+	//ErrImageNotFound when an image is not in the gallery of the item
+	ErrImageNotFound = errors.New("Image not found")
+	//ErrImageNotSaved when an image could not be stored
+	ErrImageNotSaved = errors.New("Error occured while saving the image")
+	//ErrPrimaryImageRequired when the primary image is unset instead of another image being made primary
+	ErrPrimaryImageRequired = errors.New("An item needs a primary image, make another image primary instead")
+	//ErrAmenityNotFound when an amenity is not in the catalogue
+	ErrAmenityNotFound = errors.New("Amenity not found")
+	//ErrAmenityExists when the code of a new amenity is taken
+	ErrAmenityExists = errors.New("An amenity with this code already exists")
+	//ErrAmenityInUse when an amenity offered by items is deleted
+	ErrAmenityInUse = errors.New("Amenity is offered by items, remove it from them first")
+	//ErrAmenityNotSaved when an amenity could not be stored
+	ErrAmenityNotSaved = errors.New("Error occured while saving the amenity")
+	//ErrUnknownAmenity when an item refers to an amenity that is not in the catalogue
+	ErrUnknownAmenity = errors.New("amenities should be codes of the amenity catalogue")
+	//ErrRoomTypeNotFound when a room type is not sold by the item
+	ErrRoomTypeNotFound = errors.New("Room type not found")
+	//ErrRoomTypeExists when the name of a room type is taken by another room type of the item
+	ErrRoomTypeExists = errors.New("The item already has a room type with this name")
+	//ErrRoomTypeNotSaved when a room type could not be stored
+	ErrRoomTypeNotSaved = errors.New("Error occured while saving the room type")
+	//ErrRoomTypeHasActiveBookings when a room type still has bookings that are not over
+	ErrRoomTypeHasActiveBookings = errors.New("Room type has active bookings")
+	//ErrRoomTypeRequired when an item selling room types is booked without one
+	ErrRoomTypeRequired = errors.New("The item sells room types, room_type_id required")
+	//ErrTooManyGuests when the guests of a booking do not fit in the rooms booked
+	ErrTooManyGuests = errors.New("The guests exceed the max occupancy of the rooms")
+	//ErrPricingRuleNotFound when a pricing rule is not a rule of the item
+	ErrPricingRuleNotFound = errors.New("Pricing rule not found")
+	//ErrPricingRuleNotSaved when a pricing rule could not be stored
+	ErrPricingRuleNotSaved = errors.New("Error occured while saving the pricing rule")
+	//ErrReviewNotAllowed when the confirmation code of a review is not a completed booking of the item
+	ErrReviewNotAllowed = errors.New("Only guests with a completed booking at the item can review it")
+	//ErrReviewExists when the booking of a review has already been reviewed
+	ErrReviewExists = errors.New("The booking has already been reviewed")
+	//ErrReviewNotSaved when a review could not be stored
+	ErrReviewNotSaved = errors.New("Error occured while saving the review")
+	//ErrInvalidExchangeRates when an exchange rate file can not be read
+	ErrInvalidExchangeRates = errors.New("Invalid exchange rate file")
+	//ErrExchangeRatesNotSaved when exchange rates could not be stored
+	ErrExchangeRatesNotSaved = errors.New("Error occured while saving exchange rates")
+	//ErrExchangeRateNotFound when a price is converted from or to a currency without an exchange rate
+	ErrExchangeRateNotFound = errors.New("No exchange rate for the currency")
+	//ErrIdempotencyKeyNotSaved when an Idempotency-Key could not be stored
+	ErrIdempotencyKeyNotSaved = errors.New("Error occured while saving the idempotency key")
 )
 
 // ErrorModel struct